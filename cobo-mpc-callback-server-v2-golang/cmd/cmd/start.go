@@ -6,10 +6,15 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/limiter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/policy"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/service"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/verifier"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/log"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/eth_base"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/solana"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/token_registry"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/tron"
 )
 
 func start() {
@@ -23,8 +28,58 @@ func start() {
 	go trapSignal()
 
 	token_registry.InitRegistry()
+	if err := token_registry.RegisterEvmTokens(CfgInstance.EvmTokens); err != nil {
+		log.Fatalf("Failed to register evm tokens: %v", err)
+	}
+	if err := token_registry.RegisterTrc10Tokens(CfgInstance.Tron.Trc10Tokens); err != nil {
+		log.Fatalf("Failed to register trc10 tokens: %v", err)
+	}
+	if err := token_registry.RegisterCosmosTokens(CfgInstance.CosmosTokens); err != nil {
+		log.Fatalf("Failed to register cosmos tokens: %v", err)
+	}
+	if err := token_registry.RegisterJettonTokens(CfgInstance.JettonTokens); err != nil {
+		log.Fatalf("Failed to register jetton tokens: %v", err)
+	}
+	if err := tron.SetBounds(CfgInstance.Tron); err != nil {
+		log.Fatalf("Failed to set tron transaction bounds: %v", err)
+	}
+	if CfgInstance.EvmAbiDir != "" {
+		if err := eth_base.LoadContractABIs(CfgInstance.EvmAbiDir); err != nil {
+			log.Fatalf("Failed to load evm contract abis: %v", err)
+		}
+	}
+	if CfgInstance.SolanaLookupTables != "" {
+		if err := solana.LoadAddressLookupTables(CfgInstance.SolanaLookupTables); err != nil {
+			log.Fatalf("Failed to load solana address lookup tables: %v", err)
+		}
+	}
+
+	policyEngine, err := policy.NewEngine(CfgInstance.Policy)
+	if err != nil {
+		log.Fatalf("Failed to init policy engine: %v", err)
+	}
+
+	amountLimiter, err := limiter.NewLimiter(CfgInstance.AmountLimits)
+	if err != nil {
+		log.Fatalf("Failed to init amount limiter: %v", err)
+	}
+
+	if err := verifier.ValidateDestinationTags(CfgInstance.DestinationTags); err != nil {
+		log.Fatalf("Failed to validate destination tags: %v", err)
+	}
+	if err := verifier.ValidateWalletAddresses(CfgInstance.WalletAddresses); err != nil {
+		log.Fatalf("Failed to validate wallet addresses: %v", err)
+	}
 
-	srv := service.New(CfgInstance, verifier.NewTssVerifier(CfgInstance.AddressWhitelist))
+	srv := service.New(CfgInstance, verifier.NewTssVerifier(
+		CfgInstance.AddressWhitelist,
+		verifier.WithMessageSign(CfgInstance.AllowMessageSign),
+		verifier.WithDestinationTags(CfgInstance.DestinationTags),
+		verifier.WithWalletAddresses(CfgInstance.WalletAddresses),
+		verifier.WithPolicyEngine(policyEngine),
+		verifier.WithLimiter(amountLimiter),
+		verifier.WithConsistencyCheck(CfgInstance.ConsistencyCheck),
+	))
 	srv.Start()
 }
 
@@ -1,10 +1,37 @@
 package config
 
 import (
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/limiter"
 	netService "github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/netservice"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/policy"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/verifier"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/cosmos"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/eth_base"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/ton"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/tron"
 )
 
 type Config struct {
 	CallbackServer   netService.Config `mapstructure:"callback_server"`
 	AddressWhitelist []string          `mapstructure:"address_whitelist"`
+	// AllowMessageSign allows message sign requests without destination address when the address whitelist is set
+	AllowMessageSign bool `mapstructure:"allow_message_sign"`
+	// DestinationTags are the tags required on transfers to addresses, e.g. XRP exchange deposit addresses
+	DestinationTags []verifier.DestinationTag `mapstructure:"destination_tags"`
+	// WalletAddresses are the addresses of the destination wallets of transfers to a wallet, checked by the consistency check
+	WalletAddresses  []verifier.WalletAddresses `mapstructure:"wallet_addresses"`
+	Policy           policy.Config              `mapstructure:"policy"`
+	AmountLimits     limiter.Config             `mapstructure:"amount_limits"`
+	ConsistencyCheck bool                       `mapstructure:"consistency_check"`
+	EvmTokens        []eth_base.TokenConfig     `mapstructure:"evm_tokens"`
+	// EvmAbiDir holds the contract ABIs as <chain_id>/<contract_address>.json, not loaded if empty
+	EvmAbiDir string `mapstructure:"evm_abi_dir"`
+	// Tron bounds the Tron transactions and declares the TRC10 tokens
+	Tron tron.Config `mapstructure:"tron"`
+	// CosmosTokens are verified by the cosmos adapter in addition to the built-in ATOM
+	CosmosTokens []cosmos.TokenConfig `mapstructure:"cosmos_tokens"`
+	// JettonTokens are verified by the ton adapter, the built-in TON sends no jetton
+	JettonTokens []ton.JettonConfig `mapstructure:"jetton_tokens"`
+	// SolanaLookupTables is the JSON snapshot of the Solana address lookup tables, not loaded if empty
+	SolanaLookupTables string `mapstructure:"solana_lookup_tables"`
 }
@@ -0,0 +1,211 @@
+package limiter
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	StorePath string        `mapstructure:"store_path"`
+	Tokens    []TokenConfig `mapstructure:"tokens"`
+}
+
+// TokenConfig holds the limits of a token in the token base unit, an empty limit is not enforced
+type TokenConfig struct {
+	TokenID           string `mapstructure:"token_id"`
+	MaxPerTransaction string `mapstructure:"max_per_transaction"`
+	HourlyLimit       string `mapstructure:"hourly_limit"`
+	DailyLimit        string `mapstructure:"daily_limit"`
+}
+
+type tokenLimit struct {
+	maxPerTransaction *big.Int
+	hourlyLimit       *big.Int
+	dailyLimit        *big.Int
+}
+
+type Limiter struct {
+	mu      sync.Mutex
+	limits  map[string]*tokenLimit
+	store   Store
+	records []Record
+	now     func() time.Time
+}
+
+func NewLimiter(cfg Config) (*Limiter, error) {
+	if len(cfg.Tokens) > 0 && cfg.StorePath == "" {
+		return nil, fmt.Errorf("limit store path is empty")
+	}
+	return newLimiter(cfg, NewFileStore(cfg.StorePath), time.Now)
+}
+
+func newLimiter(cfg Config, store Store, now func() time.Time) (*Limiter, error) {
+	l := &Limiter{
+		limits: make(map[string]*tokenLimit),
+		store:  store,
+		now:    now,
+	}
+
+	for _, tc := range cfg.Tokens {
+		tokenID := normalizeTokenID(tc.TokenID)
+		if tokenID == "" {
+			return nil, fmt.Errorf("limit token id is empty")
+		}
+		if _, ok := l.limits[tokenID]; ok {
+			return nil, fmt.Errorf("limit of token %v is duplicated", tokenID)
+		}
+
+		limit := &tokenLimit{}
+		var err error
+		if limit.maxPerTransaction, err = parseLimit(tc.MaxPerTransaction); err != nil {
+			return nil, fmt.Errorf("invalid max per transaction of token %v: %w", tokenID, err)
+		}
+		if limit.hourlyLimit, err = parseLimit(tc.HourlyLimit); err != nil {
+			return nil, fmt.Errorf("invalid hourly limit of token %v: %w", tokenID, err)
+		}
+		if limit.dailyLimit, err = parseLimit(tc.DailyLimit); err != nil {
+			return nil, fmt.Errorf("invalid daily limit of token %v: %w", tokenID, err)
+		}
+		l.limits[tokenID] = limit
+	}
+
+	if len(l.limits) > 0 {
+		records, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		l.records = records
+	}
+
+	return l, nil
+}
+
+// Check verifies the amount against the token limits and records it when allowed.
+// A retry is a request signing a recorded payload, the digest of the hashes decoded from the raw transaction; it is
+// allowed again without being counted twice, unless its amount is larger than the recorded one, then it is checked and
+// recorded as a new transfer on top of the recorded one. The transaction id comes from the request and is only kept
+// for reference, so a new payload under a recorded transaction id is counted.
+func (l *Limiter) Check(tokenID, transactionID, payload string, amount *big.Int) error {
+	tokenID = normalizeTokenID(tokenID)
+	limit, ok := l.limits[tokenID]
+	if !ok {
+		return nil
+	}
+	if amount == nil {
+		return fmt.Errorf("amount of token %v is unknown", tokenID)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	recorded, err := l.recorded(tokenID, payload)
+	if err != nil {
+		return err
+	}
+	if recorded != nil && amount.Cmp(recorded) <= 0 {
+		return nil
+	}
+
+	if limit.maxPerTransaction != nil && amount.Cmp(limit.maxPerTransaction) > 0 {
+		return fmt.Errorf("amount %v exceeds max per transaction %v of token %v", amount, limit.maxPerTransaction, tokenID)
+	}
+
+	hourlyUsed, dailyUsed, err := l.used(tokenID, now)
+	if err != nil {
+		return err
+	}
+	if limit.hourlyLimit != nil && new(big.Int).Add(hourlyUsed, amount).Cmp(limit.hourlyLimit) > 0 {
+		return fmt.Errorf("amount %v exceeds hourly limit %v of token %v, used %v", amount, limit.hourlyLimit, tokenID, hourlyUsed)
+	}
+	if limit.dailyLimit != nil && new(big.Int).Add(dailyUsed, amount).Cmp(limit.dailyLimit) > 0 {
+		return fmt.Errorf("amount %v exceeds daily limit %v of token %v, used %v", amount, limit.dailyLimit, tokenID, dailyUsed)
+	}
+
+	records := append(l.records, Record{
+		TransactionID: transactionID,
+		Payload:       payload,
+		TokenID:       tokenID,
+		Amount:        amount.String(),
+		Timestamp:     now.Unix(),
+	})
+	if err := l.store.Save(records); err != nil {
+		return fmt.Errorf("failed to save limit records: %w", err)
+	}
+	l.records = records
+
+	return nil
+}
+
+// recorded returns the largest amount recorded for the payload, nil if it is not recorded
+func (l *Limiter) recorded(tokenID, payload string) (*big.Int, error) {
+	var largest *big.Int
+	for _, record := range l.records {
+		if payload == "" || record.Payload != payload || record.TokenID != tokenID {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(record.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid recorded amount %q of transaction %v", record.Amount, record.TransactionID)
+		}
+		if largest == nil || amount.Cmp(largest) > 0 {
+			largest = amount
+		}
+	}
+	return largest, nil
+}
+
+// used sums the recorded amounts of the token in the last hour and the last day
+func (l *Limiter) used(tokenID string, now time.Time) (hourly, daily *big.Int, err error) {
+	hourly, daily = new(big.Int), new(big.Int)
+	hourStart := now.Add(-time.Hour).Unix()
+
+	for _, record := range l.records {
+		if record.TokenID != tokenID {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(record.Amount, 10)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid recorded amount %q of transaction %v", record.Amount, record.TransactionID)
+		}
+		daily.Add(daily, amount)
+		if record.Timestamp > hourStart {
+			hourly.Add(hourly, amount)
+		}
+	}
+
+	return hourly, daily, nil
+}
+
+// prune drops the records out of the daily window
+func (l *Limiter) prune(now time.Time) {
+	dayStart := now.Add(-24 * time.Hour).Unix()
+
+	records := l.records[:0]
+	for _, record := range l.records {
+		if record.Timestamp > dayStart {
+			records = append(records, record)
+		}
+	}
+	l.records = records
+}
+
+func parseLimit(limit string) (*big.Int, error) {
+	if limit == "" {
+		return nil, nil //nolint:nilnil
+	}
+	value, ok := new(big.Int).SetString(limit, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("limit %q is not a non-negative integer", limit)
+	}
+	return value, nil
+}
+
+func normalizeTokenID(tokenID string) string {
+	return strings.ToUpper(strings.TrimSpace(tokenID))
+}
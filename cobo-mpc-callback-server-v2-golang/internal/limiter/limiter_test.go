@@ -0,0 +1,107 @@
+package limiter
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantError bool
+	}{
+		{
+			name:      "Empty config",
+			cfg:       Config{},
+			wantError: false,
+		},
+		{
+			name:      "Empty store path",
+			cfg:       Config{Tokens: []TokenConfig{{TokenID: "ETH", DailyLimit: "100"}}},
+			wantError: true,
+		},
+		{
+			name: "Invalid limit",
+			cfg: Config{
+				StorePath: filepath.Join(t.TempDir(), "limits.json"),
+				Tokens:    []TokenConfig{{TokenID: "ETH", DailyLimit: "1.5"}},
+			},
+			wantError: true,
+		},
+		{
+			name: "Duplicated token",
+			cfg: Config{
+				StorePath: filepath.Join(t.TempDir(), "limits.json"),
+				Tokens:    []TokenConfig{{TokenID: "ETH"}, {TokenID: "eth"}},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewLimiter(tt.cfg)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, l)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, l)
+			}
+		})
+	}
+}
+
+func TestLimiter_Check(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "limits.json")
+	cfg := Config{
+		StorePath: storePath,
+		Tokens: []TokenConfig{
+			{TokenID: "ETH", MaxPerTransaction: "100", HourlyLimit: "150", DailyLimit: "240"},
+		},
+	}
+
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+
+	l, err := newLimiter(cfg, NewFileStore(storePath), clock)
+	assert.NoError(t, err)
+
+	// unlimited token
+	assert.NoError(t, l.Check("TRON", "tx0", "payload0", big.NewInt(1000)))
+
+	// single transaction maximum
+	assert.Error(t, l.Check("ETH", "tx1", "payload1", big.NewInt(101)))
+	assert.NoError(t, l.Check("ETH", "tx1", "payload1", big.NewInt(100)))
+
+	// retry of a recorded payload is not counted twice, whatever its transaction id
+	assert.NoError(t, l.Check("ETH", "tx1", "payload1", big.NewInt(100)))
+	assert.NoError(t, l.Check("ETH", "tx9", "payload1", big.NewInt(30)))
+
+	// retry of a recorded payload with a larger amount is checked again
+	assert.Error(t, l.Check("ETH", "tx1", "payload1", big.NewInt(1000)))
+
+	// another payload under a recorded transaction id is counted
+	assert.Error(t, l.Check("ETH", "tx1", "payload2", big.NewInt(60)))
+	assert.NoError(t, l.Check("ETH", "tx1", "payload2", big.NewInt(50)))
+
+	// hourly limit
+	assert.Error(t, l.Check("ETH", "tx2", "payload3", big.NewInt(1)))
+
+	// hourly window rolls, daily limit still applies after restart
+	now = now.Add(2 * time.Hour)
+	l, err = newLimiter(cfg, NewFileStore(storePath), clock)
+	assert.NoError(t, err)
+	assert.Error(t, l.Check("ETH", "tx3", "payload4", big.NewInt(100)))
+	assert.NoError(t, l.Check("ETH", "tx3", "payload4", big.NewInt(90)))
+	assert.Error(t, l.Check("eth", "tx4", "payload5", big.NewInt(1)))
+
+	// daily window rolls
+	now = now.Add(23 * time.Hour)
+	assert.NoError(t, l.Check("ETH", "tx4", "payload5", big.NewInt(100)))
+}
@@ -0,0 +1,71 @@
+package limiter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Record is an approved transfer counted in the rolling windows
+type Record struct {
+	TransactionID string `json:"transaction_id"`
+	// Payload is the digest of the signed hashes, which identifies a retry of the same transfer
+	Payload string `json:"payload"`
+	TokenID string `json:"token_id"`
+	// Amount in the token base unit
+	Amount    string `json:"amount"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Store persists the approved transfer records, so a restart does not reset the windows
+type Store interface {
+	Load() ([]Record, error)
+	Save(records []Record) error
+}
+
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Load() ([]Record, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read limit store %v: %w", s.path, err)
+	}
+
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse limit store %v: %w", s.path, err)
+	}
+	return records, nil
+}
+
+// Save writes the records to a temp file and renames it, so a crash never leaves a partial store
+func (s *FileStore) Save(records []Record) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal limit records: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create limit store dir: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write limit store: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace limit store: %w", err)
+	}
+	return nil
+}
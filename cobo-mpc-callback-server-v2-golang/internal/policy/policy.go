@@ -0,0 +1,337 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/utils"
+)
+
+type Action string
+
+const (
+	ActionApprove Action = "approve"
+	ActionReject  Action = "reject"
+)
+
+type Config struct {
+	// DefaultAction is applied when no rule matches, approve if empty
+	DefaultAction Action       `mapstructure:"default_action"`
+	Rules         []RuleConfig `mapstructure:"rules"`
+}
+
+type RuleConfig struct {
+	Name   string      `mapstructure:"name"`
+	Action Action      `mapstructure:"action"`
+	Reason string      `mapstructure:"reason"`
+	Match  MatchConfig `mapstructure:"match"`
+}
+
+// MatchConfig holds the rule conditions, an empty condition matches any request
+type MatchConfig struct {
+	TokenIDs         []string `mapstructure:"token_ids"`
+	TransactionTypes []string `mapstructure:"transaction_types"`
+	// SourceAddresses matches when every source address is listed
+	SourceAddresses []string `mapstructure:"source_addresses"`
+	// DestinationAddresses matches when every decoded destination address is listed
+	DestinationAddresses []string `mapstructure:"destination_addresses"`
+	// MinAmount and MaxAmount bound the transaction amount, in token units (e.g. "1.5")
+	MinAmount string `mapstructure:"min_amount"`
+	MaxAmount string `mapstructure:"max_amount"`
+	// UnlimitedApproval matches only requests granting an unlimited token approval when true
+	UnlimitedApproval bool `mapstructure:"unlimited_approval"`
+	// ContractCalls matches when the contract call decoded with the contract ABI is one of the listed methods
+	ContractCalls []ContractCallConfig `mapstructure:"contract_calls"`
+	// TypedDataDomains matches when the EIP-712 domain of a message sign request is one of the listed domains
+	TypedDataDomains []TypedDataDomainConfig `mapstructure:"typed_data_domains"`
+	// PrimaryTypes matches when the EIP-712 primary type of a message sign request is listed, e.g. Permit
+	PrimaryTypes []string `mapstructure:"primary_types"`
+	// MessagePatterns matches when the text of a signed message matches one of the regular expressions
+	MessagePatterns []string `mapstructure:"message_patterns"`
+}
+
+// ContractCallConfig lists the allowed methods of a contract
+type ContractCallConfig struct {
+	Contract string `mapstructure:"contract"`
+	// Methods are the ABI method names, an empty list matches every method of the contract
+	Methods []string `mapstructure:"methods"`
+}
+
+// TypedDataDomainConfig is an EIP-712 domain, an empty field matches any value
+type TypedDataDomainConfig struct {
+	Name              string `mapstructure:"name"`
+	ChainID           uint64 `mapstructure:"chain_id"`
+	VerifyingContract string `mapstructure:"verifying_contract"`
+}
+
+// Input is the request view evaluated by the rules
+type Input struct {
+	TokenID              string
+	TransactionType      string
+	SourceAddresses      []string
+	DestinationAddresses []string
+	// Amount in token units, nil if unknown
+	Amount *big.Rat
+	// UnlimitedApproval is set when the raw transaction grants an unlimited token approval
+	UnlimitedApproval bool
+	// ContractAddress and ContractMethod are the decoded contract call, empty if not decoded
+	ContractAddress string
+	ContractMethod  string
+	// TypedData is the EIP-712 message of a message sign request, nil for another request
+	TypedData *TypedDataInput
+	// MessageContent is the text of a signed message, empty for a transaction or a message which is not text
+	MessageContent string
+}
+
+// TypedDataInput is the primary type and the domain of an EIP-712 message
+type TypedDataInput struct {
+	PrimaryType string
+	Name        string
+	// ChainID is nil if the domain has no chain id
+	ChainID           *big.Int
+	VerifyingContract string
+}
+
+type Decision struct {
+	Action Action
+	// Rule is the name of the matched rule, empty if the default action is applied
+	Rule   string
+	Reason string
+	// TypedData is set when the matched rule has a typed_data_domains or primary_types condition
+	TypedData bool
+}
+
+type Engine struct {
+	defaultAction Action
+	rules         []*rule
+}
+
+type rule struct {
+	name      string
+	action    Action
+	reason    string
+	match     MatchConfig
+	minAmount *big.Rat
+	maxAmount *big.Rat
+	// messagePatterns are the compiled MatchConfig.MessagePatterns
+	messagePatterns []*regexp.Regexp
+	// sourceAddresses and destinationAddresses are the normalized MatchConfig addresses
+	sourceAddresses      []string
+	destinationAddresses []string
+}
+
+func NewEngine(cfg Config) (*Engine, error) {
+	defaultAction := cfg.DefaultAction
+	if defaultAction == "" {
+		defaultAction = ActionApprove
+	}
+	if err := checkAction(defaultAction); err != nil {
+		return nil, fmt.Errorf("invalid default action: %w", err)
+	}
+
+	e := &Engine{defaultAction: defaultAction}
+	for idx, rc := range cfg.Rules {
+		r, err := newRule(idx, rc)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, r)
+	}
+
+	return e, nil
+}
+
+func newRule(idx int, rc RuleConfig) (*rule, error) {
+	name := rc.Name
+	if name == "" {
+		name = fmt.Sprintf("rule_%d", idx)
+	}
+	if err := checkAction(rc.Action); err != nil {
+		return nil, fmt.Errorf("invalid action of policy rule %v: %w", name, err)
+	}
+
+	r := &rule{
+		name:                 name,
+		action:               rc.Action,
+		reason:               rc.Reason,
+		match:                rc.Match,
+		sourceAddresses:      normalizeAddresses(rc.Match.SourceAddresses),
+		destinationAddresses: normalizeAddresses(rc.Match.DestinationAddresses),
+	}
+
+	var err error
+	if r.minAmount, err = parseAmount(rc.Match.MinAmount); err != nil {
+		return nil, fmt.Errorf("invalid min amount of policy rule %v: %w", name, err)
+	}
+	if r.maxAmount, err = parseAmount(rc.Match.MaxAmount); err != nil {
+		return nil, fmt.Errorf("invalid max amount of policy rule %v: %w", name, err)
+	}
+	for _, pattern := range rc.Match.MessagePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message pattern of policy rule %v: %w", name, err)
+		}
+		r.messagePatterns = append(r.messagePatterns, re)
+	}
+
+	return r, nil
+}
+
+// Evaluate returns the decision of the first matching rule, or the default action
+func (e *Engine) Evaluate(input *Input) *Decision {
+	for _, r := range e.rules {
+		if r.matches(input) {
+			return &Decision{
+				Action:    r.action,
+				Rule:      r.name,
+				Reason:    r.reason,
+				TypedData: len(r.match.TypedDataDomains) > 0 || len(r.match.PrimaryTypes) > 0,
+			}
+		}
+	}
+
+	return &Decision{Action: e.defaultAction}
+}
+
+func (r *rule) matches(input *Input) bool {
+	if len(r.match.TokenIDs) > 0 && !containsFold(r.match.TokenIDs, input.TokenID) {
+		return false
+	}
+	if len(r.match.TransactionTypes) > 0 && !containsFold(r.match.TransactionTypes, input.TransactionType) {
+		return false
+	}
+	if len(r.sourceAddresses) > 0 && !utils.IsSubset(normalizeAddresses(input.SourceAddresses), r.sourceAddresses) {
+		return false
+	}
+	if len(r.destinationAddresses) > 0 && !utils.IsSubset(normalizeAddresses(input.DestinationAddresses), r.destinationAddresses) {
+		return false
+	}
+	if r.minAmount != nil && (input.Amount == nil || input.Amount.Cmp(r.minAmount) < 0) {
+		return false
+	}
+	if r.maxAmount != nil && (input.Amount == nil || input.Amount.Cmp(r.maxAmount) > 0) {
+		return false
+	}
+	if r.match.UnlimitedApproval && !input.UnlimitedApproval {
+		return false
+	}
+	if len(r.match.ContractCalls) > 0 && !matchContractCall(r.match.ContractCalls, input) {
+		return false
+	}
+	if len(r.match.TypedDataDomains) > 0 && !matchTypedDataDomain(r.match.TypedDataDomains, input.TypedData) {
+		return false
+	}
+	if len(r.match.PrimaryTypes) > 0 && (input.TypedData == nil || !contains(r.match.PrimaryTypes, input.TypedData.PrimaryType)) {
+		return false
+	}
+	if len(r.messagePatterns) > 0 && !matchMessage(r.messagePatterns, input.MessageContent) {
+		return false
+	}
+
+	return true
+}
+
+func matchContractCall(calls []ContractCallConfig, input *Input) bool {
+	if input.ContractAddress == "" || input.ContractMethod == "" {
+		return false
+	}
+	for _, call := range calls {
+		if !strings.EqualFold(call.Contract, input.ContractAddress) {
+			continue
+		}
+		if len(call.Methods) == 0 {
+			return true
+		}
+		for _, method := range call.Methods {
+			// ABI method names are case sensitive
+			if method == input.ContractMethod {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchTypedDataDomain(domains []TypedDataDomainConfig, typedData *TypedDataInput) bool {
+	if typedData == nil {
+		return false
+	}
+	for _, domain := range domains {
+		if domain.Name != "" && domain.Name != typedData.Name {
+			continue
+		}
+		if domain.ChainID != 0 && (typedData.ChainID == nil || !typedData.ChainID.IsUint64() || typedData.ChainID.Uint64() != domain.ChainID) {
+			continue
+		}
+		if domain.VerifyingContract != "" && !strings.EqualFold(domain.VerifyingContract, typedData.VerifyingContract) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func matchMessage(patterns []*regexp.Regexp, content string) bool {
+	if content == "" {
+		return false
+	}
+	for _, re := range patterns {
+		if re.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkAction(action Action) error {
+	switch action {
+	case ActionApprove, ActionReject:
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+}
+
+func parseAmount(amount string) (*big.Rat, error) {
+	if amount == "" {
+		return nil, nil //nolint:nilnil
+	}
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, fmt.Errorf("amount %q is not a decimal number", amount)
+	}
+	return value, nil
+}
+
+// normalizeAddresses lowercases hex addresses, which are case-insensitive
+func normalizeAddresses(addresses []string) []string {
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if utils.Has0xPrefix(address) {
+			address = strings.ToLower(address)
+		}
+		normalized = append(normalized, address)
+	}
+	return normalized
+}
+
+// contains matches case sensitively, as EIP-712 type names are
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
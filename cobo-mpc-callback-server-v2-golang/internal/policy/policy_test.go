@@ -0,0 +1,351 @@
+package policy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEngine(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantError bool
+	}{
+		{
+			name:      "Empty config",
+			cfg:       Config{},
+			wantError: false,
+		},
+		{
+			name:      "Invalid default action",
+			cfg:       Config{DefaultAction: "allow"},
+			wantError: true,
+		},
+		{
+			name:      "Invalid rule action",
+			cfg:       Config{Rules: []RuleConfig{{Name: "r1", Action: "deny"}}},
+			wantError: true,
+		},
+		{
+			name: "Invalid rule amount",
+			cfg: Config{Rules: []RuleConfig{
+				{Name: "r1", Action: ActionReject, Match: MatchConfig{MinAmount: "1,000"}},
+			}},
+			wantError: true,
+		},
+		{
+			name: "Invalid message pattern",
+			cfg: Config{Rules: []RuleConfig{
+				{Name: "r1", Action: ActionApprove, Match: MatchConfig{MessagePatterns: []string{"(unclosed"}}},
+			}},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewEngine(tt.cfg)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, engine)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, engine)
+			}
+		})
+	}
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	engine, err := NewEngine(Config{
+		DefaultAction: ActionReject,
+		Rules: []RuleConfig{
+			{
+				Name:   "unlimited_approval",
+				Action: ActionReject,
+				Match:  MatchConfig{UnlimitedApproval: true},
+			},
+			{
+				Name:   "large_usdt",
+				Action: ActionReject,
+				Reason: "amount too large",
+				Match: MatchConfig{
+					TokenIDs:  []string{"ETH_USDT"},
+					MinAmount: "10000",
+				},
+			},
+			{
+				Name:   "whitelisted_withdrawal",
+				Action: ActionApprove,
+				Match: MatchConfig{
+					TransactionTypes:     []string{"Withdrawal"},
+					DestinationAddresses: []string{"0xA", "0xB"},
+				},
+			},
+			{
+				Name:   "treasury_withdrawal",
+				Action: ActionApprove,
+				Match: MatchConfig{
+					SourceAddresses:      []string{"0x1111111111111111111111111111111111111111"},
+					DestinationAddresses: []string{"0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		input      *Input
+		wantAction Action
+		wantRule   string
+	}{
+		{
+			name: "Large usdt withdrawal",
+			input: &Input{
+				TokenID:              "eth_usdt",
+				TransactionType:      "Withdrawal",
+				DestinationAddresses: []string{"0xA"},
+				Amount:               big.NewRat(20000, 1),
+			},
+			wantAction: ActionReject,
+			wantRule:   "large_usdt",
+		},
+		{
+			name: "Small usdt withdrawal",
+			input: &Input{
+				TokenID:              "ETH_USDT",
+				TransactionType:      "Withdrawal",
+				DestinationAddresses: []string{"0xA"},
+				Amount:               big.NewRat(1, 2),
+			},
+			wantAction: ActionApprove,
+			wantRule:   "whitelisted_withdrawal",
+		},
+		{
+			name: "Unlimited approval to whitelisted spender",
+			input: &Input{
+				TokenID:              "ETH_USDT",
+				TransactionType:      "Withdrawal",
+				DestinationAddresses: []string{"0xA"},
+				UnlimitedApproval:    true,
+			},
+			wantAction: ActionReject,
+			wantRule:   "unlimited_approval",
+		},
+		{
+			name: "Checksummed destination decoded in lowercase",
+			input: &Input{
+				TokenID:              "ETH",
+				TransactionType:      "Withdrawal",
+				SourceAddresses:      []string{"0x1111111111111111111111111111111111111111"},
+				DestinationAddresses: []string{"0x8b45b84e2cf29e5f826797df7e1aa93fc71a2bfd"},
+			},
+			wantAction: ActionApprove,
+			wantRule:   "treasury_withdrawal",
+		},
+		{
+			name: "Unknown destination",
+			input: &Input{
+				TokenID:              "ETH",
+				TransactionType:      "Withdrawal",
+				DestinationAddresses: []string{"0xA", "0xC"},
+			},
+			wantAction: ActionReject,
+			wantRule:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := engine.Evaluate(tt.input)
+			assert.Equal(t, tt.wantAction, decision.Action)
+			assert.Equal(t, tt.wantRule, decision.Rule)
+		})
+	}
+}
+
+func TestEngine_EvaluateContractCalls(t *testing.T) {
+	engine, err := NewEngine(Config{
+		DefaultAction: ActionReject,
+		Rules: []RuleConfig{
+			{
+				Name:   "staking_deposit",
+				Action: ActionApprove,
+				Match: MatchConfig{
+					ContractCalls: []ContractCallConfig{
+						{Contract: "0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84", Methods: []string{"deposit"}},
+						{Contract: "0x00000000219ab540356cBB839Cbe05303d7705Fa"},
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		input      *Input
+		wantAction Action
+	}{
+		{
+			name:       "Allowed method",
+			input:      &Input{ContractAddress: "0xAE7AB96520DE3A18E5E111B5EAAB095312D7FE84", ContractMethod: "deposit"},
+			wantAction: ActionApprove,
+		},
+		{
+			name:       "Other method",
+			input:      &Input{ContractAddress: "0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84", ContractMethod: "withdraw"},
+			wantAction: ActionReject,
+		},
+		{
+			name:       "Every method of contract",
+			input:      &Input{ContractAddress: "0x00000000219ab540356cBB839Cbe05303d7705Fa", ContractMethod: "withdraw"},
+			wantAction: ActionApprove,
+		},
+		{
+			name:       "Other contract",
+			input:      &Input{ContractAddress: "0xdAC17F958D2ee523a2206206994597C13D831ec7", ContractMethod: "deposit"},
+			wantAction: ActionReject,
+		},
+		{
+			name:       "Call not decoded",
+			input:      &Input{},
+			wantAction: ActionReject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := engine.Evaluate(tt.input)
+			assert.Equal(t, tt.wantAction, decision.Action)
+		})
+	}
+}
+
+func TestEngine_EvaluateTypedData(t *testing.T) {
+	engine, err := NewEngine(Config{
+		DefaultAction: ActionReject,
+		Rules: []RuleConfig{
+			{
+				Name:   "usdc_permit",
+				Action: ActionApprove,
+				Match: MatchConfig{
+					TypedDataDomains: []TypedDataDomainConfig{
+						{Name: "USD Coin", ChainID: 1, VerifyingContract: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"},
+					},
+					PrimaryTypes: []string{"Permit"},
+				},
+			},
+			{
+				Name:   "ether_mail",
+				Action: ActionApprove,
+				Match: MatchConfig{
+					TypedDataDomains: []TypedDataDomainConfig{{Name: "Ether Mail"}},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	usdc := &TypedDataInput{
+		PrimaryType:       "Permit",
+		Name:              "USD Coin",
+		ChainID:           big.NewInt(1),
+		VerifyingContract: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+	}
+
+	tests := []struct {
+		name       string
+		input      *Input
+		wantAction Action
+	}{
+		{
+			name:       "Allowed domain and primary type",
+			input:      &Input{TypedData: usdc},
+			wantAction: ActionApprove,
+		},
+		{
+			name:       "Other primary type",
+			input:      &Input{TypedData: &TypedDataInput{PrimaryType: "Order", Name: usdc.Name, ChainID: usdc.ChainID, VerifyingContract: usdc.VerifyingContract}},
+			wantAction: ActionReject,
+		},
+		{
+			name:       "Other chain",
+			input:      &Input{TypedData: &TypedDataInput{PrimaryType: "Permit", Name: usdc.Name, ChainID: big.NewInt(137), VerifyingContract: usdc.VerifyingContract}},
+			wantAction: ActionReject,
+		},
+		{
+			name:       "Domain without chain id",
+			input:      &Input{TypedData: &TypedDataInput{PrimaryType: "Permit", Name: usdc.Name, VerifyingContract: usdc.VerifyingContract}},
+			wantAction: ActionReject,
+		},
+		{
+			name:       "Domain matched by name",
+			input:      &Input{TypedData: &TypedDataInput{PrimaryType: "Mail", Name: "Ether Mail", ChainID: big.NewInt(10)}},
+			wantAction: ActionApprove,
+		},
+		{
+			name:       "Not a typed data message",
+			input:      &Input{},
+			wantAction: ActionReject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := engine.Evaluate(tt.input)
+			assert.Equal(t, tt.wantAction, decision.Action)
+			// only the typed data rules approve, the default action rejects
+			assert.Equal(t, tt.wantAction == ActionApprove, decision.TypedData)
+		})
+	}
+}
+
+func TestEngine_EvaluateMessagePatterns(t *testing.T) {
+	engine, err := NewEngine(Config{
+		DefaultAction: ActionReject,
+		Rules: []RuleConfig{
+			{
+				Name:   "sign_in",
+				Action: ActionApprove,
+				Match: MatchConfig{
+					TransactionTypes: []string{"MessageSign"},
+					MessagePatterns:  []string{`^example\.com wants you to sign in`, `^Welcome to Example`},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		input      *Input
+		wantAction Action
+	}{
+		{
+			name:       "Matched message",
+			input:      &Input{TransactionType: "MessageSign", MessageContent: "example.com wants you to sign in with your account"},
+			wantAction: ActionApprove,
+		},
+		{
+			name:       "Other message",
+			input:      &Input{TransactionType: "MessageSign", MessageContent: "Transfer all your funds"},
+			wantAction: ActionReject,
+		},
+		{
+			name:       "Message without text",
+			input:      &Input{TransactionType: "MessageSign"},
+			wantAction: ActionReject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := engine.Evaluate(tt.input)
+			assert.Equal(t, tt.wantAction, decision.Action)
+		})
+	}
+}
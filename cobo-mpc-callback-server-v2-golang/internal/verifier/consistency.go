@@ -0,0 +1,302 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/utils"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+)
+
+// verifyConsistency checks the decoded raw transaction against Cobo transaction metadata,
+// so a compromised upstream can not show one transfer and sign another
+func (v *TssVerifier) verifyConsistency(
+	tokenID string,
+	tx token_adapter.Transaction,
+	transfers []token_adapter.Transfer,
+	extra *coboWaaS2.TSSKeySignExtra,
+) error {
+	if err := checkSources(tx, transfers, extra.SourceAddresses); err != nil {
+		return err
+	}
+
+	asset := token_adapter.GetTokenAsset(tokenID)
+
+	destination := extra.Transaction.Destination
+	switch {
+	case destination.TransactionTransferToAddressDestination != nil:
+		expected, err := addressDestinationOutputs(tokenID, destination.TransactionTransferToAddressDestination)
+		if err != nil {
+			return err
+		}
+		if err := checkOutputs(transfers, asset, expected); err != nil {
+			return err
+		}
+	case destination.TransactionTransferToWalletDestination != nil:
+		wallet := destination.TransactionTransferToWalletDestination
+		amount, err := toBaseUnit(tokenID, wallet.Amount)
+		if err != nil {
+			return err
+		}
+		if err := v.checkWalletRecipients(wallet.WalletId, transfers); err != nil {
+			return err
+		}
+		if total := token_adapter.TotalAmount(transfers, asset); total.Cmp(amount) != 0 {
+			return fmt.Errorf("decoded amount %v mismatch transaction amount %v", total, amount)
+		}
+	case destination.TransactionEvmContractDestination != nil:
+		contract := destination.TransactionEvmContractDestination
+		value := "0"
+		if contract.Value != nil {
+			value = *contract.Value
+		}
+		amount, err := toBaseUnit(tokenID, value)
+		if err != nil {
+			return err
+		}
+		expected := map[string]*big.Int{normalizeAddress(contract.Address): amount}
+		// the destination is the called contract, while the transfers may be unwrapped from the call, e.g. a Safe
+		if callTx, ok := tx.(token_adapter.CallTransaction); ok {
+			call, err := callTx.GetCall()
+			if err != nil {
+				return fmt.Errorf("failed to get call: %w", err)
+			}
+			if call == nil {
+				return fmt.Errorf("raw transaction creates a contract instead of calling %v", contract.Address)
+			}
+			if err := checkOutputs([]token_adapter.Transfer{*call}, "", expected); err != nil {
+				return err
+			}
+		} else if err := checkOutputs(transfers, asset, expected); err != nil {
+			return err
+		}
+	case destination.TransactionMessageSignEIP191Destination != nil,
+		destination.TransactionMessageSignEIP712Destination != nil,
+		destination.TransactionRawMessageSignDestination != nil,
+		destination.TransactionBIP137Destination != nil,
+		destination.TransactionBIP322Destination != nil:
+		// the signed message is recomputed from the destination by the adapter
+		if _, ok := tx.(token_adapter.MessageTransaction); !ok {
+			return fmt.Errorf("message sign destination is not decoded as a message")
+		}
+	default:
+		return fmt.Errorf("unsupported transaction destination %T", destination.GetActualInstance())
+	}
+
+	if feeTx, ok := tx.(token_adapter.FeeTransaction); ok && extra.Transaction.Fee != nil {
+		fee, err := feeTx.GetFee()
+		if err != nil {
+			return fmt.Errorf("failed to get fee: %w", err)
+		}
+		if err := checkFee(fee, extra.Transaction.Fee); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSources requires every decoded sender, and the owner of every output spent by a UTXO transaction, to be one of
+// the source addresses. Transfers without any decoded sender are rejected, as their sources can not be checked.
+func checkSources(tx token_adapter.Transaction, transfers []token_adapter.Transfer, sourceAddresses []coboWaaS2.AddressInfo) error {
+	var sources []string
+	for _, source := range sourceAddresses {
+		sources = append(sources, normalizeAddress(source.Address))
+	}
+
+	var senders []string
+	for _, transfer := range transfers {
+		if transfer.From != "" {
+			senders = append(senders, transfer.From)
+		}
+	}
+	if sourceTx, ok := tx.(token_adapter.SourceTransaction); ok {
+		owners, err := sourceTx.GetSourceAddresses()
+		if err != nil {
+			return fmt.Errorf("failed to get source addresses: %w", err)
+		}
+		senders = append(senders, owners...)
+	}
+	if len(senders) == 0 && len(transfers) > 0 {
+		return fmt.Errorf("no sender is decoded from raw transaction to check against source addresses")
+	}
+
+	for _, sender := range senders {
+		if !utils.IsSubset([]string{normalizeAddress(sender)}, sources) {
+			return fmt.Errorf("decoded sender %v is not part of source addresses", sender)
+		}
+	}
+	return nil
+}
+
+// checkWalletRecipients requires every decoded recipient to be an address of the destination wallet,
+// a wallet without configured addresses is rejected
+func (v *TssVerifier) checkWalletRecipients(walletID string, transfers []token_adapter.Transfer) error {
+	addresses, ok := v.walletAddresses[walletID]
+	if !ok {
+		return fmt.Errorf("addresses of destination wallet %v are not configured", walletID)
+	}
+	for _, transfer := range transfers {
+		if !addresses[normalizeAddress(transfer.To)] {
+			return fmt.Errorf("decoded recipient %v is not an address of destination wallet %v", transfer.To, walletID)
+		}
+	}
+	return nil
+}
+
+func addressDestinationOutputs(
+	tokenID string,
+	destination *coboWaaS2.TransactionTransferToAddressDestination,
+) (map[string]*big.Int, error) {
+	expected := make(map[string]*big.Int)
+	add := func(address, amount *string) error {
+		if address == nil || amount == nil {
+			return fmt.Errorf("transaction destination address or amount is nil")
+		}
+		value, err := toBaseUnit(tokenID, *amount)
+		if err != nil {
+			return err
+		}
+		key := normalizeAddress(*address)
+		if expected[key] == nil {
+			expected[key] = new(big.Int)
+		}
+		expected[key].Add(expected[key], value)
+		return nil
+	}
+
+	if destination.AccountOutput != nil {
+		if err := add(destination.AccountOutput.Address, destination.AccountOutput.Amount); err != nil {
+			return nil, err
+		}
+	}
+	for _, output := range destination.UtxoOutputs {
+		if err := add(output.Address, output.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	return expected, nil
+}
+
+// checkOutputs requires the decoded recipients and the amounts of the asset they receive to equal the expected ones.
+// A recipient of another asset or of an approval is a destination receiving none of the asset.
+func checkOutputs(transfers []token_adapter.Transfer, asset string, expected map[string]*big.Int) error {
+	decoded := make(map[string]*big.Int)
+	for _, transfer := range transfers {
+		key := normalizeAddress(transfer.To)
+		if decoded[key] == nil {
+			decoded[key] = new(big.Int)
+		}
+		if transfer.Amount != nil && transfer.Moves(asset) {
+			decoded[key].Add(decoded[key], transfer.Amount)
+		}
+	}
+
+	if len(decoded) != len(expected) {
+		return fmt.Errorf("decoded destinations %v mismatch transaction destinations %v", decoded, expected)
+	}
+	for address, amount := range expected {
+		decodedAmount, ok := decoded[address]
+		if !ok {
+			return fmt.Errorf("transaction destination %v is not found in raw transaction", address)
+		}
+		if decodedAmount.Cmp(amount) != 0 {
+			return fmt.Errorf("decoded amount %v to %v mismatch transaction amount %v", decodedAmount, address, amount)
+		}
+	}
+	return nil
+}
+
+func checkFee(fee *token_adapter.Fee, txFee *coboWaaS2.TransactionFee) error {
+	switch {
+	case txFee.TransactionEvmEip1559Fee != nil:
+		eip1559 := txFee.TransactionEvmEip1559Fee
+		if err := checkFeeField("gas limit", fee.GasLimit, eip1559.GasLimit); err != nil {
+			return err
+		}
+		if err := checkFeeField("max fee per gas", fee.GasPrice, eip1559.MaxFeePerGas); err != nil {
+			return err
+		}
+		if err := checkFeeField("max priority fee per gas", fee.GasTipCap, eip1559.MaxPriorityFeePerGas); err != nil {
+			return err
+		}
+	case txFee.TransactionEvmLegacyFee != nil:
+		legacy := txFee.TransactionEvmLegacyFee
+		if err := checkFeeField("gas limit", fee.GasLimit, legacy.GasLimit); err != nil {
+			return err
+		}
+		if err := checkFeeField("gas price", fee.GasPrice, legacy.GasPrice); err != nil {
+			return err
+		}
+	case txFee.TransactionFixedFee != nil:
+		fixed := txFee.TransactionFixedFee
+		return checkMaxFee(fee.FeeLimit, fixed.TokenId, fixed.MaxFeeAmount)
+	case txFee.TransactionUtxoFee != nil:
+		utxo := txFee.TransactionUtxoFee
+		return checkMaxFee(fee.FeeLimit, utxo.TokenId, utxo.MaxFeeAmount)
+	default:
+		return fmt.Errorf("unsupported transaction fee %T", txFee.GetActualInstance())
+	}
+	return nil
+}
+
+// checkMaxFee requires the decoded fee limit not to exceed the transaction max fee when both are present
+func checkMaxFee(feeLimit *big.Int, tokenID, maxFeeAmount *string) error {
+	if feeLimit == nil || maxFeeAmount == nil || tokenID == nil {
+		return nil
+	}
+	maxFee, err := toBaseUnit(*tokenID, *maxFeeAmount)
+	if err != nil {
+		return err
+	}
+	if feeLimit.Cmp(maxFee) > 0 {
+		return fmt.Errorf("decoded fee limit %v exceeds transaction max fee %v", feeLimit, maxFee)
+	}
+	return nil
+}
+
+// checkFeeField compares a decoded fee field with the transaction fee field when both are present
+func checkFeeField(name string, decoded *big.Int, expected *string) error {
+	if decoded == nil || expected == nil || *expected == "" {
+		return nil
+	}
+	value, ok := new(big.Int).SetString(*expected, 10)
+	if !ok {
+		return fmt.Errorf("invalid transaction %v %q", name, *expected)
+	}
+	if decoded.Cmp(value) != 0 {
+		return fmt.Errorf("decoded %v %v mismatch transaction %v %v", name, decoded, name, value)
+	}
+	return nil
+}
+
+// toBaseUnit converts an amount in token units to the token base unit
+func toBaseUnit(tokenID, amount string) (*big.Int, error) {
+	decimals, ok := token_adapter.GetTokenDecimals(tokenID)
+	if !ok {
+		return nil, fmt.Errorf("decimals of token %v is not registered", tokenID)
+	}
+
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q of token %v", amount, tokenID)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value.Mul(value, new(big.Rat).SetInt(scale))
+	if !value.IsInt() {
+		return nil, fmt.Errorf("amount %q exceeds decimals %v of token %v", amount, decimals, tokenID)
+	}
+	return value.Num(), nil
+}
+
+// normalizeAddress lowercases hex addresses, which are case-insensitive
+func normalizeAddress(address string) string {
+	if utils.Has0xPrefix(address) {
+		return strings.ToLower(address)
+	}
+	return address
+}
@@ -0,0 +1,386 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func registerTestDecimals() {
+	if _, ok := token_adapter.GetTokenDecimals("TEST_USDT"); !ok {
+		_ = token_adapter.RegisterTokenDecimals("TEST_USDT", 6)
+	}
+}
+
+func TestToBaseUnit(t *testing.T) {
+	registerTestDecimals()
+
+	amount, err := toBaseUnit("TEST_USDT", "1.5")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500000), amount)
+
+	_, err = toBaseUnit("TEST_USDT", "0.0000001")
+	assert.Error(t, err)
+
+	_, err = toBaseUnit("UNKNOWN", "1")
+	assert.Error(t, err)
+}
+
+// testTransaction is a decoded transaction of the given input owners and the given top-level call
+type testTransaction struct {
+	token_adapter.Transaction
+	sources []string
+	call    *token_adapter.Transfer
+}
+
+func (t *testTransaction) GetSourceAddresses() ([]string, error) {
+	return t.sources, nil
+}
+
+func (t *testTransaction) GetCall() (*token_adapter.Transfer, error) {
+	return t.call, nil
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	registerTestDecimals()
+
+	address := "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"
+	source := "0x1111111111111111111111111111111111111111"
+
+	newExtra := func(destination string, amount string) *coboWaaS2.TSSKeySignExtra {
+		return &coboWaaS2.TSSKeySignExtra{
+			SourceAddresses: []coboWaaS2.AddressInfo{{Address: source}},
+			Transaction: &coboWaaS2.Transaction{
+				Destination: coboWaaS2.TransactionTransferToAddressDestinationAsTransactionDestination(
+					&coboWaaS2.TransactionTransferToAddressDestination{
+						AccountOutput: &coboWaaS2.TransactionTransferToAddressDestinationAccountOutput{
+							Address: &destination,
+							Amount:  &amount,
+						},
+					},
+				),
+			},
+		}
+	}
+
+	transfers := []token_adapter.Transfer{{
+		From:   source,
+		To:     address,
+		Amount: big.NewInt(50000000),
+	}}
+
+	tests := []struct {
+		name      string
+		tx        token_adapter.Transaction
+		transfers []token_adapter.Transfer
+		extra     *coboWaaS2.TSSKeySignExtra
+		wantError bool
+	}{
+		{
+			name:      "Consistent transfer",
+			transfers: transfers,
+			extra:     newExtra("0x8b45b84e2cf29e5f826797df7e1aa93fc71a2bfd", "50"),
+			wantError: false,
+		},
+		{
+			name:      "Destination mismatch",
+			transfers: transfers,
+			extra:     newExtra("0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f", "50"),
+			wantError: true,
+		},
+		{
+			name:      "Amount mismatch",
+			transfers: transfers,
+			extra:     newExtra(address, "5"),
+			wantError: true,
+		},
+		{
+			name: "Transfer of another asset",
+			transfers: []token_adapter.Transfer{{
+				Asset:  "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+				From:   source,
+				To:     address,
+				Amount: big.NewInt(50000000),
+			}},
+			extra:     newExtra(address, "50"),
+			wantError: true,
+		},
+		{
+			name: "Approval",
+			transfers: []token_adapter.Transfer{{
+				From:   source,
+				To:     address,
+				Amount: big.NewInt(50000000),
+				Method: "approve",
+			}},
+			extra:     newExtra(address, "50"),
+			wantError: true,
+		},
+		{
+			name: "Source mismatch",
+			transfers: []token_adapter.Transfer{{
+				From:   "0x2222222222222222222222222222222222222222",
+				To:     address,
+				Amount: big.NewInt(50000000),
+			}},
+			extra:     newExtra(address, "50"),
+			wantError: true,
+		},
+		{
+			name:      "Input owned by source address",
+			tx:        &testTransaction{sources: []string{source}},
+			transfers: []token_adapter.Transfer{{To: address, Amount: big.NewInt(50000000)}},
+			extra:     newExtra(address, "50"),
+			wantError: false,
+		},
+		{
+			name:      "Input owner mismatch",
+			tx:        &testTransaction{sources: []string{source, "0x2222222222222222222222222222222222222222"}},
+			transfers: []token_adapter.Transfer{{To: address, Amount: big.NewInt(50000000)}},
+			extra:     newExtra(address, "50"),
+			wantError: true,
+		},
+		{
+			name:      "No decoded sender",
+			transfers: []token_adapter.Transfer{{To: address, Amount: big.NewInt(50000000)}},
+			extra:     newExtra(address, "50"),
+			wantError: true,
+		},
+	}
+
+	v := &TssVerifier{consistencyCheck: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.verifyConsistency("TEST_USDT", tt.tx, tt.transfers, tt.extra)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyConsistency_ContractDestination(t *testing.T) {
+	registerTestDecimals()
+
+	safe := "0x1111111111111111111111111111111111111111"
+	extra := &coboWaaS2.TSSKeySignExtra{
+		SourceAddresses: []coboWaaS2.AddressInfo{{Address: safe}},
+		Transaction: &coboWaaS2.Transaction{
+			Destination: coboWaaS2.TransactionEvmContractDestinationAsTransactionDestination(
+				&coboWaaS2.TransactionEvmContractDestination{Address: safe, Value: coboWaaS2.PtrString("0")},
+			),
+		},
+	}
+	// the transfers unwrapped from a Safe execTransaction are sent by the Safe, not to the called Safe
+	transfers := []token_adapter.Transfer{
+		{From: safe, To: "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd", Amount: big.NewInt(1000)},
+		{From: safe, To: "0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f", Amount: big.NewInt(2000)},
+	}
+
+	tests := []struct {
+		name      string
+		call      *token_adapter.Transfer
+		wantError bool
+	}{
+		{
+			name:      "Safe call",
+			call:      &token_adapter.Transfer{From: safe, To: safe, Amount: big.NewInt(0)},
+			wantError: false,
+		},
+		{
+			name:      "Call of another contract",
+			call:      &token_adapter.Transfer{From: safe, To: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Amount: big.NewInt(0)},
+			wantError: true,
+		},
+		{
+			name:      "Call with value",
+			call:      &token_adapter.Transfer{From: safe, To: safe, Amount: big.NewInt(1)},
+			wantError: true,
+		},
+		{
+			name:      "Contract creation",
+			wantError: true,
+		},
+	}
+
+	v := &TssVerifier{consistencyCheck: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.verifyConsistency("TEST_USDT", &testTransaction{call: tt.call}, transfers, extra)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// testMessage is a decoded message sign request
+type testMessage struct {
+	token_adapter.Transaction
+}
+
+func (m *testMessage) GetMessage() (*token_adapter.Message, error) {
+	return &token_adapter.Message{}, nil
+}
+
+func TestVerifyConsistency_WalletDestination(t *testing.T) {
+	registerTestDecimals()
+
+	source := "0x1111111111111111111111111111111111111111"
+	walletAddress := "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"
+	extra := &coboWaaS2.TSSKeySignExtra{
+		SourceAddresses: []coboWaaS2.AddressInfo{{Address: source}},
+		Transaction: &coboWaaS2.Transaction{
+			Destination: coboWaaS2.TransactionTransferToWalletDestinationAsTransactionDestination(
+				&coboWaaS2.TransactionTransferToWalletDestination{WalletId: "wallet1", Amount: "50"},
+			),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		wallets   []WalletAddresses
+		transfers []token_adapter.Transfer
+		wantError bool
+	}{
+		{
+			name:      "Transfer to a wallet address",
+			wallets:   []WalletAddresses{{WalletID: "wallet1", Addresses: []string{walletAddress}}},
+			transfers: []token_adapter.Transfer{{From: source, To: "0x8b45b84e2cf29e5f826797df7e1aa93fc71a2bfd", Amount: big.NewInt(50000000)}},
+			wantError: false,
+		},
+		{
+			name:      "Transfer to another address",
+			wallets:   []WalletAddresses{{WalletID: "wallet1", Addresses: []string{walletAddress}}},
+			transfers: []token_adapter.Transfer{{From: source, To: "0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f", Amount: big.NewInt(50000000)}},
+			wantError: true,
+		},
+		{
+			name:    "Transfer of another asset to another address",
+			wallets: []WalletAddresses{{WalletID: "wallet1", Addresses: []string{walletAddress}}},
+			transfers: []token_adapter.Transfer{
+				{From: source, To: walletAddress, Amount: big.NewInt(50000000)},
+				{From: source, To: "0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f", Asset: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Amount: big.NewInt(1)},
+			},
+			wantError: true,
+		},
+		{
+			name:      "Amount mismatch",
+			wallets:   []WalletAddresses{{WalletID: "wallet1", Addresses: []string{walletAddress}}},
+			transfers: []token_adapter.Transfer{{From: source, To: walletAddress, Amount: big.NewInt(5000000)}},
+			wantError: true,
+		},
+		{
+			name:      "Wallet not configured",
+			wallets:   []WalletAddresses{{WalletID: "wallet2", Addresses: []string{walletAddress}}},
+			transfers: []token_adapter.Transfer{{From: source, To: walletAddress, Amount: big.NewInt(50000000)}},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &TssVerifier{consistencyCheck: true}
+			WithWalletAddresses(tt.wallets)(v)
+			err := v.verifyConsistency("TEST_USDT", nil, tt.transfers, extra)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyConsistency_UnsupportedDestination(t *testing.T) {
+	registerTestDecimals()
+
+	newExtra := func(destination coboWaaS2.TransactionDestination) *coboWaaS2.TSSKeySignExtra {
+		return &coboWaaS2.TSSKeySignExtra{Transaction: &coboWaaS2.Transaction{Destination: destination}}
+	}
+	eip191 := coboWaaS2.TransactionMessageSignEIP191DestinationAsTransactionDestination(
+		&coboWaaS2.TransactionMessageSignEIP191Destination{Message: "aGVsbG8="},
+	)
+
+	tests := []struct {
+		name      string
+		tx        token_adapter.Transaction
+		extra     *coboWaaS2.TSSKeySignExtra
+		wantError bool
+	}{
+		{
+			name:      "Message sign",
+			tx:        &testMessage{},
+			extra:     newExtra(eip191),
+			wantError: false,
+		},
+		{
+			name:      "Message sign destination of a transaction",
+			tx:        &testTransaction{},
+			extra:     newExtra(eip191),
+			wantError: true,
+		},
+		{
+			name: "Solana contract",
+			tx:   &testTransaction{},
+			extra: newExtra(coboWaaS2.TransactionSolContractDestinationAsTransactionDestination(
+				&coboWaaS2.TransactionSolContractDestination{},
+			)),
+			wantError: true,
+		},
+		{
+			name: "Deposit to address",
+			tx:   &testTransaction{},
+			extra: newExtra(coboWaaS2.TransactionDepositToAddressDestinationAsTransactionDestination(
+				&coboWaaS2.TransactionDepositToAddressDestination{Address: "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd", Amount: "1"},
+			)),
+			wantError: true,
+		},
+		{
+			name:      "No destination",
+			tx:        &testTransaction{},
+			extra:     newExtra(coboWaaS2.TransactionDestination{}),
+			wantError: true,
+		},
+	}
+
+	v := &TssVerifier{consistencyCheck: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.verifyConsistency("TEST_USDT", tt.tx, nil, tt.extra)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckFee_MaxFee(t *testing.T) {
+	registerTestDecimals()
+
+	newUtxoFee := func(maxFee string) *coboWaaS2.TransactionFee {
+		return &coboWaaS2.TransactionFee{
+			TransactionUtxoFee: &coboWaaS2.TransactionUtxoFee{
+				TokenId:      coboWaaS2.PtrString("TEST_USDT"),
+				MaxFeeAmount: coboWaaS2.PtrString(maxFee),
+			},
+		}
+	}
+
+	fee := &token_adapter.Fee{FeeLimit: big.NewInt(1000)}
+	assert.NoError(t, checkFee(fee, newUtxoFee("0.001")))
+	assert.Error(t, checkFee(fee, newUtxoFee("0.000999")))
+}
+
+func TestCheckFee_UnsupportedFee(t *testing.T) {
+	assert.Error(t, checkFee(&token_adapter.Fee{FeeLimit: big.NewInt(1000)}, &coboWaaS2.TransactionFee{}))
+}
@@ -0,0 +1,31 @@
+package verifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+)
+
+func (v *TssVerifier) verifyLimit(
+	tokenID string,
+	hashes []string,
+	transfers []token_adapter.Transfer,
+	extra *coboWaaS2.TSSKeySignExtra,
+) error {
+	amount := token_adapter.TotalAmount(transfers, token_adapter.GetTokenAsset(tokenID))
+	if err := v.limiter.Check(tokenID, extra.Transaction.TransactionId, payloadDigest(hashes), amount); err != nil {
+		return fmt.Errorf("amount limit exceeded: %w", err)
+	}
+	return nil
+}
+
+// payloadDigest identifies the signed payload by the hashes decoded from the raw transaction, so a retry is recognized
+// by what it signs rather than by the transaction id of the request
+func payloadDigest(hashes []string) string {
+	digest := sha256.Sum256([]byte(strings.ToLower(strings.Join(hashes, ","))))
+	return hex.EncodeToString(digest[:])
+}
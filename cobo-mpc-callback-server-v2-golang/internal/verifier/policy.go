@@ -0,0 +1,118 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/policy"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/log"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+)
+
+func (v *TssVerifier) verifyPolicy(
+	tokenID string,
+	toAddresses []string,
+	transfers []token_adapter.Transfer,
+	contractCall *token_adapter.ContractCall,
+	message *token_adapter.Message,
+	extra *coboWaaS2.TSSKeySignExtra,
+) error {
+	input, err := buildPolicyInput(tokenID, toAddresses, transfers, contractCall, message, extra)
+	if err != nil {
+		return fmt.Errorf("failed to build policy input: %w", err)
+	}
+
+	decision := v.policyEngine.Evaluate(input)
+	log.Debugf("policy decision: action %v, rule %v, reason %v", decision.Action, decision.Rule, decision.Reason)
+
+	if decision.Action == policy.ActionReject {
+		if decision.Rule == "" {
+			return fmt.Errorf("rejected by policy default action")
+		}
+		return fmt.Errorf("rejected by policy rule %v: %v", decision.Rule, decision.Reason)
+	}
+
+	if undecodedTypedData(message) && !decision.TypedData {
+		return fmt.Errorf("typed data of primary type %q is not approved by a typed_data_domains or primary_types policy rule",
+			message.PrimaryType)
+	}
+
+	return nil
+}
+
+// undecodedTypedData reports whether the message is EIP-712 typed data whose transfers are not decoded,
+// which the whitelist and the amount checks can not see
+func undecodedTypedData(message *token_adapter.Message) bool {
+	return message != nil && message.Standard == token_adapter.MessageStandardEIP712 && !message.Decoded
+}
+
+func buildPolicyInput(
+	tokenID string,
+	toAddresses []string,
+	transfers []token_adapter.Transfer,
+	contractCall *token_adapter.ContractCall,
+	message *token_adapter.Message,
+	extra *coboWaaS2.TSSKeySignExtra,
+) (*policy.Input, error) {
+	input := &policy.Input{
+		TokenID:              tokenID,
+		DestinationAddresses: toAddresses,
+	}
+
+	for _, transfer := range transfers {
+		if transfer.UnlimitedApproval {
+			input.UnlimitedApproval = true
+		}
+	}
+
+	if contractCall != nil {
+		input.ContractAddress = contractCall.Contract
+		input.ContractMethod = contractCall.Method
+	}
+
+	if message != nil {
+		input.MessageContent = message.Content
+	}
+	if message != nil && message.Domain != nil {
+		input.TypedData = &policy.TypedDataInput{
+			PrimaryType:       message.PrimaryType,
+			Name:              message.Domain.Name,
+			ChainID:           message.Domain.ChainID,
+			VerifyingContract: message.Domain.VerifyingContract,
+		}
+	}
+
+	for _, source := range extra.SourceAddresses {
+		input.SourceAddresses = append(input.SourceAddresses, source.Address)
+	}
+
+	if extra.Transaction.Type != nil {
+		input.TransactionType = string(*extra.Transaction.Type)
+	}
+
+	amount, err := transferAmount(tokenID, transfers)
+	if err != nil {
+		return nil, err
+	}
+	input.Amount = amount
+
+	return input, nil
+}
+
+// transferAmount sums the decoded transfers of the token asset, in token units, nil for a request without transfers,
+// e.g. a message sign request
+func transferAmount(tokenID string, transfers []token_adapter.Transfer) (*big.Rat, error) {
+	if len(transfers) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	decimals, ok := token_adapter.GetTokenDecimals(tokenID)
+	if !ok {
+		return nil, fmt.Errorf("decimals of token %v is not registered", tokenID)
+	}
+
+	total := token_adapter.TotalAmount(transfers, token_adapter.GetTokenAsset(tokenID))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(total, scale), nil
+}
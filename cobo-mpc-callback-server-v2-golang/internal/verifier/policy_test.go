@@ -0,0 +1,94 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/policy"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferAmount(t *testing.T) {
+	registerTestDecimals()
+
+	transfers := []token_adapter.Transfer{
+		{To: "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd", Amount: big.NewInt(1500000)},
+		{To: "0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f", Amount: big.NewInt(250000)},
+		{To: "0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f", Amount: big.NewInt(900000000), Method: "approve"},
+	}
+
+	amount, err := transferAmount("TEST_USDT", transfers)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(175, 100), amount)
+
+	amount, err = transferAmount("TEST_USDT", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, amount)
+
+	_, err = transferAmount("UNKNOWN", transfers)
+	assert.Error(t, err)
+}
+
+func TestVerifyPolicy_TypedData(t *testing.T) {
+	registerTestDecimals()
+
+	engine, err := policy.NewEngine(policy.Config{
+		Rules: []policy.RuleConfig{
+			{
+				Name:   "ether_mail",
+				Action: policy.ActionApprove,
+				Match:  policy.MatchConfig{PrimaryTypes: []string{"Mail"}},
+			},
+			{
+				Name:   "message_sign",
+				Action: policy.ActionApprove,
+				Match:  policy.MatchConfig{TransactionTypes: []string{"MessageSign"}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	messageSign := coboWaaS2.TRANSACTIONTYPE_MESSAGE_SIGN
+	extra := &coboWaaS2.TSSKeySignExtra{Transaction: &coboWaaS2.Transaction{Type: &messageSign}}
+	domain := &token_adapter.TypedDataDomain{Name: "Ether Mail", ChainID: big.NewInt(1)}
+
+	tests := []struct {
+		name      string
+		message   *token_adapter.Message
+		wantError bool
+	}{
+		{
+			name:    "Typed data allowed by primary type",
+			message: &token_adapter.Message{Standard: token_adapter.MessageStandardEIP712, PrimaryType: "Mail", Domain: domain},
+		},
+		{
+			name:      "Typed data allowed by another rule",
+			message:   &token_adapter.Message{Standard: token_adapter.MessageStandardEIP712, PrimaryType: "Order", Domain: domain},
+			wantError: true,
+		},
+		{
+			name: "Decoded typed data",
+			message: &token_adapter.Message{
+				Standard: token_adapter.MessageStandardEIP712, PrimaryType: "Permit", Domain: domain, Decoded: true,
+			},
+		},
+		{
+			name:    "Personal message",
+			message: &token_adapter.Message{Standard: token_adapter.MessageStandardEIP191, Content: "hello"},
+		},
+	}
+
+	v := &TssVerifier{policyEngine: engine}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.verifyPolicy("TEST_USDT", nil, nil, nil, tt.message, extra)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
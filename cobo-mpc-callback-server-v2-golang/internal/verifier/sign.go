@@ -3,6 +3,7 @@ package verifier
 import (
 	"fmt"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/log"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/utils"
 	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
@@ -48,8 +49,71 @@ func (v *TssVerifier) verifySign(detail *coboWaaS2.TSSKeySignRequest, extra *cob
 	}
 
 	if len(v.addressWhitelist) > 0 {
-		if !utils.IsSubset(toAddresses, v.addressWhitelist) {
-			return fmt.Errorf("destination addresses %v is not part of address whitelist", toAddresses)
+		if err := v.checkWhitelist(tx, toAddresses); err != nil {
+			return err
+		}
+	}
+
+	transfers, err := tx.GetTransfers()
+	if err != nil {
+		return fmt.Errorf("failed to get transfers: %w", err)
+	}
+	for _, transfer := range transfers {
+		log.Debugf("transfer chain %v asset %v method %v from %v to %v amount %v memo %q tag %q",
+			transfer.Chain, transfer.Asset, transfer.Method, transfer.From, transfer.To, transfer.Amount, transfer.Memo, transfer.Tag)
+		if transfer.UnlimitedApproval {
+			log.Warnf("transfer asset %v grants an unlimited approval to %v", transfer.Asset, transfer.To)
+		}
+	}
+
+	// check the destination tags required by whitelisted addresses
+	if err := v.checkDestinationTags(transfers); err != nil {
+		return err
+	}
+
+	// decode the contract call with the registered contract ABI
+	var contractCall *token_adapter.ContractCall
+	if callTx, ok := tx.(token_adapter.ContractCallTransaction); ok {
+		if contractCall, err = callTx.GetContractCall(); err != nil {
+			return fmt.Errorf("failed to decode contract call: %w", err)
+		}
+		if contractCall != nil {
+			log.Debugf("contract call %v method %v args %v value %v",
+				contractCall.Contract, contractCall.Method, contractCall.Args, contractCall.Value)
+		}
+	}
+
+	// get the signed message of a message sign request
+	var message *token_adapter.Message
+	if msgTx, ok := tx.(token_adapter.MessageTransaction); ok {
+		if message, err = msgTx.GetMessage(); err != nil {
+			return fmt.Errorf("failed to get message: %w", err)
+		}
+		if message != nil {
+			log.Debugf("message standard %v primary type %q", message.Standard, message.PrimaryType)
+		}
+	}
+
+	// check raw transaction against transaction metadata
+	if v.consistencyCheck {
+		if err := v.verifyConsistency(tokenID, tx, transfers, extra); err != nil {
+			return fmt.Errorf("transaction consistency check failed: %w", err)
+		}
+	}
+
+	// check policy rules, typed data that is not decoded must be allow-listed by a rule
+	if v.policyEngine != nil {
+		if err := v.verifyPolicy(tokenID, toAddresses, transfers, contractCall, message, extra); err != nil {
+			return err
+		}
+	} else if undecodedTypedData(message) {
+		return fmt.Errorf("typed data of primary type %q is not decoded and no policy is configured to allow it", message.PrimaryType)
+	}
+
+	// check amount limits, the last check as approved amounts are recorded
+	if v.limiter != nil {
+		if err := v.verifyLimit(tokenID, hashes, transfers, extra); err != nil {
+			return err
 		}
 	}
 
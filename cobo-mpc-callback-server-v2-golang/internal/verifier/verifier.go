@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/limiter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/internal/policy"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/log"
 	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
 )
@@ -14,12 +16,80 @@ type Verifier interface {
 
 type TssVerifier struct {
 	addressWhitelist []string
+	// allowMessageSign allows message sign requests without destination when the address whitelist is set
+	allowMessageSign bool
+	// destinationTags maps a normalized address to the tag required on transfers to it
+	destinationTags map[string]string
+	// walletAddresses maps a wallet id to its normalized addresses
+	walletAddresses  map[string]map[string]bool
+	policyEngine     *policy.Engine
+	limiter          *limiter.Limiter
+	consistencyCheck bool
 }
 
-func NewTssVerifier(addressWhitelist []string) Verifier {
-	return &TssVerifier{
+type Option func(v *TssVerifier)
+
+// WithMessageSign allows message sign requests without destination address, which the address whitelist rejects otherwise
+func WithMessageSign(allowed bool) Option {
+	return func(v *TssVerifier) {
+		v.allowMessageSign = allowed
+	}
+}
+
+// WithPolicyEngine evaluates key sign requests against the policy rules
+func WithPolicyEngine(engine *policy.Engine) Option {
+	return func(v *TssVerifier) {
+		v.policyEngine = engine
+	}
+}
+
+// WithLimiter enforces the per token amount limits on key sign requests
+func WithLimiter(l *limiter.Limiter) Option {
+	return func(v *TssVerifier) {
+		v.limiter = l
+	}
+}
+
+// WithConsistencyCheck rejects key sign requests whose raw transaction mismatches Cobo transaction metadata
+func WithConsistencyCheck(enabled bool) Option {
+	return func(v *TssVerifier) {
+		v.consistencyCheck = enabled
+	}
+}
+
+// WithDestinationTags requires the tag of transfers to the addresses, the tags must be validated by ValidateDestinationTags
+func WithDestinationTags(tags []DestinationTag) Option {
+	return func(v *TssVerifier) {
+		v.destinationTags = make(map[string]string, len(tags))
+		for _, tag := range tags {
+			v.destinationTags[normalizeAddress(tag.Address)] = tag.Tag
+		}
+	}
+}
+
+// WithWalletAddresses sets the addresses of the destination wallets checked by the consistency check,
+// the wallets must be validated by ValidateWalletAddresses
+func WithWalletAddresses(wallets []WalletAddresses) Option {
+	return func(v *TssVerifier) {
+		v.walletAddresses = make(map[string]map[string]bool, len(wallets))
+		for _, wallet := range wallets {
+			addresses := make(map[string]bool, len(wallet.Addresses))
+			for _, address := range wallet.Addresses {
+				addresses[normalizeAddress(address)] = true
+			}
+			v.walletAddresses[wallet.WalletID] = addresses
+		}
+	}
+}
+
+func NewTssVerifier(addressWhitelist []string, opts ...Option) Verifier {
+	v := &TssVerifier{
 		addressWhitelist: addressWhitelist,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
 func (v *TssVerifier) Verify(request *coboWaaS2.TSSCallbackRequest) error {
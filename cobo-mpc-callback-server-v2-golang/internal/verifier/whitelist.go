@@ -0,0 +1,92 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/utils"
+)
+
+// checkWhitelist requires the destination addresses to be whitelisted. A message sign request without destination,
+// e.g. a login message, moves no asset and is allowed only with allowMessageSign.
+func (v *TssVerifier) checkWhitelist(tx token_adapter.Transaction, toAddresses []string) error {
+	if _, ok := tx.(token_adapter.MessageTransaction); ok && len(toAddresses) == 0 {
+		if !v.allowMessageSign {
+			return fmt.Errorf("message sign request has no destination address to check against address whitelist")
+		}
+		return nil
+	}
+	if !utils.IsSubset(toAddresses, v.addressWhitelist) {
+		return fmt.Errorf("destination addresses %v is not part of address whitelist", toAddresses)
+	}
+	return nil
+}
+
+// DestinationTag is the tag required on transfers to a whitelisted address,
+// e.g. the XRP destination tag of an exchange deposit address shared by the exchange users
+type DestinationTag struct {
+	Address string `mapstructure:"address"`
+	Tag     string `mapstructure:"tag"`
+}
+
+// ValidateDestinationTags rejects an empty address or tag, and an address with more than one tag
+func ValidateDestinationTags(tags []DestinationTag) error {
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		address := normalizeAddress(tag.Address)
+		if address == "" {
+			return fmt.Errorf("destination tag address is empty")
+		}
+		if tag.Tag == "" {
+			return fmt.Errorf("destination tag of address %v is empty", tag.Address)
+		}
+		if seen[address] {
+			return fmt.Errorf("address %v has more than one destination tag", tag.Address)
+		}
+		seen[address] = true
+	}
+	return nil
+}
+
+// WalletAddresses are the addresses of a wallet receiving transfers to the wallet
+type WalletAddresses struct {
+	WalletID  string   `mapstructure:"wallet_id"`
+	Addresses []string `mapstructure:"addresses"`
+}
+
+// ValidateWalletAddresses rejects an empty wallet id or address, and a wallet declared more than once
+func ValidateWalletAddresses(wallets []WalletAddresses) error {
+	seen := make(map[string]bool)
+	for _, wallet := range wallets {
+		if wallet.WalletID == "" {
+			return fmt.Errorf("wallet id is empty")
+		}
+		if len(wallet.Addresses) == 0 {
+			return fmt.Errorf("addresses of wallet %v are empty", wallet.WalletID)
+		}
+		for _, address := range wallet.Addresses {
+			if address == "" {
+				return fmt.Errorf("address of wallet %v is empty", wallet.WalletID)
+			}
+		}
+		if seen[wallet.WalletID] {
+			return fmt.Errorf("wallet %v is declared more than once", wallet.WalletID)
+		}
+		seen[wallet.WalletID] = true
+	}
+	return nil
+}
+
+// checkDestinationTags requires a transfer to an address with a destination tag to carry the tag
+func (v *TssVerifier) checkDestinationTags(transfers []token_adapter.Transfer) error {
+	for _, transfer := range transfers {
+		tag, ok := v.destinationTags[normalizeAddress(transfer.To)]
+		if !ok {
+			continue
+		}
+		if transfer.Tag != tag {
+			return fmt.Errorf("transfer to %v has tag %q, the address requires tag %q", transfer.To, transfer.Tag, tag)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTssVerifier_CheckWhitelist(t *testing.T) {
+	whitelist := []string{"0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"}
+
+	tests := []struct {
+		name             string
+		tx               token_adapter.Transaction
+		toAddresses      []string
+		allowMessageSign bool
+		wantError        bool
+	}{
+		{
+			name:        "Whitelisted destination",
+			tx:          &testTransaction{},
+			toAddresses: whitelist,
+		},
+		{
+			name:        "Destination not whitelisted",
+			tx:          &testTransaction{},
+			toAddresses: []string{"0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f"},
+			wantError:   true,
+		},
+		{
+			name:      "Transaction without destination",
+			tx:        &testTransaction{},
+			wantError: true,
+		},
+		{
+			name:      "Message without destination",
+			tx:        &testMessage{},
+			wantError: true,
+		},
+		{
+			name:             "Message without destination allowed",
+			tx:               &testMessage{},
+			allowMessageSign: true,
+		},
+		{
+			name:             "Message granting a transfer to an address not whitelisted",
+			tx:               &testMessage{},
+			toAddresses:      []string{"0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f"},
+			allowMessageSign: true,
+			wantError:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewTssVerifier(whitelist, WithMessageSign(tt.allowMessageSign)).(*TssVerifier)
+			err := v.checkWhitelist(tt.tx, tt.toAddresses)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDestinationTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		tags      []DestinationTag
+		wantError bool
+	}{
+		{
+			name: "Valid tags",
+			tags: []DestinationTag{
+				{Address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Tag: "123456"},
+				{Address: "rrrrrrrrrrrrrrrrrrrrBZbvji", Tag: "1"},
+			},
+		},
+		{
+			name: "No tags",
+		},
+		{
+			name:      "Empty address",
+			tags:      []DestinationTag{{Tag: "1"}},
+			wantError: true,
+		},
+		{
+			name:      "Empty tag",
+			tags:      []DestinationTag{{Address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}},
+			wantError: true,
+		},
+		{
+			name: "Duplicate address",
+			tags: []DestinationTag{
+				{Address: "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd", Tag: "1"},
+				{Address: "0x8b45b84e2cf29e5f826797df7e1aa93fc71a2bfd", Tag: "2"},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDestinationTags(tt.tags)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWalletAddresses(t *testing.T) {
+	tests := []struct {
+		name      string
+		wallets   []WalletAddresses
+		wantError bool
+	}{
+		{
+			name:    "Valid wallets",
+			wallets: []WalletAddresses{{WalletID: "wallet1", Addresses: []string{"0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"}}},
+		},
+		{
+			name:      "Empty wallet id",
+			wallets:   []WalletAddresses{{Addresses: []string{"0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"}}},
+			wantError: true,
+		},
+		{
+			name:      "No addresses",
+			wallets:   []WalletAddresses{{WalletID: "wallet1"}},
+			wantError: true,
+		},
+		{
+			name:      "Empty address",
+			wallets:   []WalletAddresses{{WalletID: "wallet1", Addresses: []string{""}}},
+			wantError: true,
+		},
+		{
+			name: "Duplicate wallet",
+			wallets: []WalletAddresses{
+				{WalletID: "wallet1", Addresses: []string{"0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"}},
+				{WalletID: "wallet1", Addresses: []string{"0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f"}},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWalletAddresses(tt.wallets)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTssVerifier_CheckDestinationTags(t *testing.T) {
+	address := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	v := NewTssVerifier(nil, WithDestinationTags([]DestinationTag{{Address: address, Tag: "123456"}})).(*TssVerifier)
+
+	tests := []struct {
+		name      string
+		transfers []token_adapter.Transfer
+		wantError bool
+	}{
+		{
+			name:      "Required tag",
+			transfers: []token_adapter.Transfer{{To: address, Tag: "123456"}},
+		},
+		{
+			name:      "Address without tag",
+			transfers: []token_adapter.Transfer{{To: "rrrrrrrrrrrrrrrrrrrrBZbvji"}},
+		},
+		{
+			name:      "Missing tag",
+			transfers: []token_adapter.Transfer{{To: address}},
+			wantError: true,
+		},
+		{
+			name: "Other tag",
+			transfers: []token_adapter.Transfer{
+				{To: "rrrrrrrrrrrrrrrrrrrrBZbvji"},
+				{To: address, Tag: "654321"},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.checkDestinationTags(tt.transfers)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
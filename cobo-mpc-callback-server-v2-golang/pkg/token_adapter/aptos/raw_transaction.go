@@ -0,0 +1,217 @@
+package aptos
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/bcs"
+)
+
+const (
+	addressLength = 32
+
+	// payloadEntryFunction is the TransactionPayload variant of an entry function call,
+	// a script, a module bundle or a multisig payload is rejected
+	payloadEntryFunction = 2
+
+	// maxTypeTagDepth bounds the nesting of vector and struct type tags
+	maxTypeTagDepth = 8
+)
+
+// Address is an Aptos account address
+type Address [addressLength]byte
+
+// String returns the long form of the address, 0x followed by 64 hex characters
+func (a Address) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// RawTransaction is a BCS decoded Aptos RawTransaction with an entry function payload
+type RawTransaction struct {
+	sender                  Address
+	sequenceNumber          uint64
+	payload                 *EntryFunction
+	maxGasAmount            uint64
+	gasUnitPrice            uint64
+	expirationTimestampSecs uint64
+	chainID                 uint8
+}
+
+// EntryFunction is the entry function called by a transaction
+type EntryFunction struct {
+	moduleAddress Address
+	moduleName    string
+	functionName  string
+	// typeArgs are the type arguments in their canonical form, e.g. 0x1::aptos_coin::AptosCoin
+	typeArgs []string
+	// args are the BCS encoded arguments
+	args [][]byte
+}
+
+// ParseRawTransaction decodes the BCS bytes of a RawTransaction
+func ParseRawTransaction(rawTx []byte) (*RawTransaction, error) {
+	d := bcs.NewDecoder(rawTx)
+	tx := &RawTransaction{}
+
+	var err error
+	if tx.sender, err = readAddress(d); err != nil {
+		return nil, fmt.Errorf("decode sender: %w", err)
+	}
+	if tx.sequenceNumber, err = d.ReadU64(); err != nil {
+		return nil, fmt.Errorf("decode sequence number: %w", err)
+	}
+	if tx.payload, err = readPayload(d); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	if tx.maxGasAmount, err = d.ReadU64(); err != nil {
+		return nil, fmt.Errorf("decode max gas amount: %w", err)
+	}
+	if tx.gasUnitPrice, err = d.ReadU64(); err != nil {
+		return nil, fmt.Errorf("decode gas unit price: %w", err)
+	}
+	if tx.expirationTimestampSecs, err = d.ReadU64(); err != nil {
+		return nil, fmt.Errorf("decode expiration timestamp: %w", err)
+	}
+	if tx.chainID, err = d.ReadU8(); err != nil {
+		return nil, fmt.Errorf("decode chain id: %w", err)
+	}
+	if err := d.Finish(); err != nil {
+		return nil, fmt.Errorf("decode raw transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+func readAddress(d *bcs.Decoder) (Address, error) {
+	var address Address
+	b, err := d.ReadFixedBytes(addressLength)
+	if err != nil {
+		return address, err
+	}
+	copy(address[:], b)
+	return address, nil
+}
+
+func readPayload(d *bcs.Decoder) (*EntryFunction, error) {
+	variant, err := d.ReadVariant()
+	if err != nil {
+		return nil, err
+	}
+	if variant != payloadEntryFunction {
+		return nil, fmt.Errorf("unsupported payload variant %d, only entry function is supported", variant)
+	}
+
+	f := &EntryFunction{}
+	if f.moduleAddress, err = readAddress(d); err != nil {
+		return nil, fmt.Errorf("decode module address: %w", err)
+	}
+	if f.moduleName, err = d.ReadString(); err != nil {
+		return nil, fmt.Errorf("decode module name: %w", err)
+	}
+	if f.functionName, err = d.ReadString(); err != nil {
+		return nil, fmt.Errorf("decode function name: %w", err)
+	}
+
+	count, err := d.ReadLength()
+	if err != nil {
+		return nil, fmt.Errorf("decode type arguments: %w", err)
+	}
+	for i := 0; i < count; i++ {
+		typeArg, err := readTypeTag(d, 0)
+		if err != nil {
+			return nil, fmt.Errorf("decode type argument %d: %w", i, err)
+		}
+		f.typeArgs = append(f.typeArgs, typeArg)
+	}
+
+	if count, err = d.ReadLength(); err != nil {
+		return nil, fmt.Errorf("decode arguments: %w", err)
+	}
+	for i := 0; i < count; i++ {
+		arg, err := d.ReadBytes()
+		if err != nil {
+			return nil, fmt.Errorf("decode argument %d: %w", i, err)
+		}
+		f.args = append(f.args, arg)
+	}
+
+	return f, nil
+}
+
+// primitiveTypeTags maps the TypeTag variants without fields to their names
+var primitiveTypeTags = map[uint32]string{
+	0:  "bool",
+	1:  "u8",
+	2:  "u64",
+	3:  "u128",
+	4:  "address",
+	5:  "signer",
+	8:  "u16",
+	9:  "u32",
+	10: "u256",
+}
+
+// readTypeTag decodes a TypeTag into its canonical form
+func readTypeTag(d *bcs.Decoder, depth int) (string, error) {
+	if depth > maxTypeTagDepth {
+		return "", fmt.Errorf("type tag is nested more than %d levels", maxTypeTagDepth)
+	}
+	variant, err := d.ReadVariant()
+	if err != nil {
+		return "", err
+	}
+	if name, ok := primitiveTypeTags[variant]; ok {
+		return name, nil
+	}
+
+	switch variant {
+	case 6:
+		inner, err := readTypeTag(d, depth+1)
+		if err != nil {
+			return "", err
+		}
+		return "vector<" + inner + ">", nil
+	case 7:
+		address, err := readAddress(d)
+		if err != nil {
+			return "", err
+		}
+		module, err := d.ReadString()
+		if err != nil {
+			return "", err
+		}
+		name, err := d.ReadString()
+		if err != nil {
+			return "", err
+		}
+		count, err := d.ReadLength()
+		if err != nil {
+			return "", err
+		}
+		var typeArgs []string
+		for i := 0; i < count; i++ {
+			typeArg, err := readTypeTag(d, depth+1)
+			if err != nil {
+				return "", err
+			}
+			typeArgs = append(typeArgs, typeArg)
+		}
+		tag := shortAddress(address) + "::" + module + "::" + name
+		if len(typeArgs) > 0 {
+			tag += "<" + strings.Join(typeArgs, ", ") + ">"
+		}
+		return tag, nil
+	default:
+		return "", fmt.Errorf("unsupported type tag variant %d", variant)
+	}
+}
+
+// shortAddress returns the address without leading zeros, as in a type tag, e.g. 0x1
+func shortAddress(a Address) string {
+	trimmed := strings.TrimLeft(hex.EncodeToString(a[:]), "0")
+	if trimmed == "" {
+		trimmed = "0"
+	}
+	return "0x" + trimmed
+}
@@ -0,0 +1,56 @@
+package aptos
+
+import (
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mainnetChainID is the chain id of the Aptos mainnet
+const mainnetChainID = 1
+
+type Token struct {
+	tokenID string
+	// chainID is the chain id bound to the token, a transaction of another chain is rejected
+	chainID uint8
+}
+
+// NewToken returns the APT token of the Aptos mainnet
+func NewToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+		chainID: mainnetChainID,
+	}
+}
+
+func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	preTxData, err := prepareBuildTransactionData(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
+	}
+
+	tx, err := ParseRawTransaction(preTxData.rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("prepare aptos transaction error: %w", err)
+	}
+	if tx.chainID != t.chainID {
+		return nil, fmt.Errorf("transaction chain id %v mismatch chain id %v of token %v", tx.chainID, t.chainID, t.tokenID)
+	}
+
+	return &Transaction{tx: tx, PrepareTransactionData: preTxData, token: t}, nil
+}
+
+func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
+		return nil, fmt.Errorf("transaction info raw tx is nil")
+	}
+
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
+	if len(rawTxBytes) == 0 {
+		return nil, fmt.Errorf("transaction raw tx is empty")
+	}
+
+	return &PrepareTransactionData{rawTx: rawTxBytes, chainID: txInfo.Transaction.GetChainId()}, nil
+}
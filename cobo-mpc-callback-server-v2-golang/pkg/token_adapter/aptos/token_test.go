@@ -0,0 +1,98 @@
+package aptos
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("APT")
+	aptToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "APT", aptToken.tokenID)
+	assert.Equal(t, uint8(mainnetChainID), aptToken.chainID)
+}
+
+// aptTransfer is the mainnet RawTransaction of 0x1::aptos_account::transfer of 1 APT from
+// 0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e at sequence number 7 to
+// 0xa7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e1980309070123d5ef6, with a max gas of 2000 at price 100
+// expiring at 1700000000
+const aptTransfer = "7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e070000000000000002000000000000000000000000000000" +
+	"00000000000000000000000000000000010d6170746f735f6163636f756e74087472616e73666572000220a7536c86055012cb7753fdb08e" +
+	"cb6c8bf1eb735ad75a2e1980309070123d5ef60800e1f50500000000d007000000000000640000000000000000f153650000000001"
+
+// aptCoinTransfer is aptTransfer calling 0x1::coin::transfer<0x1::aptos_coin::AptosCoin> instead
+const aptCoinTransfer = "7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e070000000000000002000000000000000000000000000000" +
+	"000000000000000000000000000000000104636f696e087472616e7366657201070000000000000000000000000000000000000000000000" +
+	"0000000000000000010a6170746f735f636f696e094170746f73436f696e000220a7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e" +
+	"1980309070123d5ef60800e1f50500000000d007000000000000640000000000000000f153650000000001"
+
+func TestToken_BuildTransaction(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:  "Transfer",
+			rawTx: aptTransfer,
+			// sha3-256("APTOS::RawTransaction") followed by the raw transaction
+			wantHashes: []string{"0xb5e97db07fa0bd0e5598aa3643a9bc6f6693bddc1a9fec9e674a461eaa00b193" +
+				"7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e0700000000000000020000000000000000000000000000000000000000000000" +
+				"0000000000000000010d6170746f735f6163636f756e74087472616e73666572000220a7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e198030907012" +
+				"3d5ef60800e1f50500000000d007000000000000640000000000000000f153650000000001"},
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  defaultChainID,
+				From:   "0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e",
+				To:     "0xa7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e1980309070123d5ef6",
+				Amount: big.NewInt(100000000),
+			}},
+		},
+		{
+			name:      "Testnet transaction",
+			rawTx:     aptTransfer[:len(aptTransfer)-2] + "02",
+			wantError: true,
+		},
+		{
+			name:      "Truncated transaction",
+			rawTx:     aptTransfer[:len(aptTransfer)-2],
+			wantError: true,
+		},
+		{
+			name:      "Empty raw tx",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewToken("APT").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+
+	_, err := NewToken("APT").BuildTransaction(nil)
+	assert.Error(t, err)
+}
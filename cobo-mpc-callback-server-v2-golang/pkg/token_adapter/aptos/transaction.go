@@ -0,0 +1,111 @@
+package aptos
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	defaultChainID = "APT"
+
+	// rawTransactionSalt is hashed into the prefix of the signing message of a RawTransaction
+	rawTransactionSalt = "APTOS::RawTransaction"
+
+	// frameworkAddress is 0x1, the address of the Aptos framework modules
+	frameworkAddress = "0x1"
+)
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx *RawTransaction
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx   []byte
+	chainID string
+}
+
+// GetHashes implements Transaction interface for Aptos.
+// The ed25519 signing message is the sha3-256 of the salt followed by the BCS bytes of the transaction.
+func (t *Transaction) GetHashes() ([]string, error) {
+	if len(t.rawTx) == 0 {
+		return nil, fmt.Errorf("transaction raw data is empty")
+	}
+
+	prefix := sha3.Sum256([]byte(rawTransactionSalt))
+	message := append(prefix[:], t.rawTx...)
+	return []string{"0x" + hex.EncodeToString(message)}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for Aptos
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Aptos, only 0x1::aptos_account::transfer is supported
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil || t.tx.payload == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	f := t.tx.payload
+	function := shortAddress(f.moduleAddress) + "::" + f.moduleName + "::" + f.functionName
+	if function != frameworkAddress+"::aptos_account::transfer" {
+		return nil, fmt.Errorf("unsupported entry function %v", function)
+	}
+	if len(f.typeArgs) != 0 || len(f.args) != 2 {
+		return nil, fmt.Errorf("entry function %v has %d type arguments and %d arguments", function, len(f.typeArgs), len(f.args))
+	}
+	if len(f.args[0]) != addressLength {
+		return nil, fmt.Errorf("invalid recipient argument length %d", len(f.args[0]))
+	}
+	if len(f.args[1]) != 8 {
+		return nil, fmt.Errorf("invalid amount argument length %d", len(f.args[1]))
+	}
+
+	var to Address
+	copy(to[:], f.args[0])
+	return []token_adapter.Transfer{{
+		Chain:  chainID,
+		From:   t.tx.sender.String(),
+		To:     to.String(),
+		Amount: new(big.Int).SetUint64(binary.LittleEndian.Uint64(f.args[1])),
+	}}, nil
+}
+
+// GetFee implements FeeTransaction interface for Aptos, the fee limit is the max gas amount times the gas unit price
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	gasLimit := new(big.Int).SetUint64(t.tx.maxGasAmount)
+	gasPrice := new(big.Int).SetUint64(t.tx.gasUnitPrice)
+	return &token_adapter.Fee{
+		GasLimit: gasLimit,
+		GasPrice: gasPrice,
+		FeeLimit: new(big.Int).Mul(gasLimit, gasPrice),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package aptos
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	recipient := "20a7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e1980309070123d5ef6"
+
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:  "aptos_account transfer",
+			rawTx: aptTransfer,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  defaultChainID,
+				From:   "0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e",
+				To:     "0xa7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e1980309070123d5ef6",
+				Amount: big.NewInt(100000000),
+			}},
+		},
+		{
+			name:      "coin transfer",
+			rawTx:     aptCoinTransfer,
+			wantError: true,
+		},
+		{
+			// one argument, the recipient
+			name:      "Missing amount",
+			rawTx:     strings.Replace(strings.Replace(aptTransfer, "0800e1f50500000000", "", 1), "02"+recipient, "01"+recipient, 1),
+			wantError: true,
+		},
+		{
+			name:      "Short recipient",
+			rawTx:     strings.Replace(aptTransfer, recipient, "14"+recipient[2:42], 1),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTx, err := hex.DecodeString(tt.rawTx)
+			assert.NoError(t, err)
+			raw, err := ParseRawTransaction(rawTx)
+			assert.NoError(t, err)
+			tx := &Transaction{tx: raw, PrepareTransactionData: &PrepareTransactionData{rawTx: rawTx}, token: &Token{tokenID: "APT"}}
+
+			transfers, err := tx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			fee, err := tx.GetFee()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Fee{GasLimit: big.NewInt(2000), GasPrice: big.NewInt(100), FeeLimit: big.NewInt(200000)}, fee)
+		})
+	}
+}
+
+func TestParseRawTransaction(t *testing.T) {
+	valid, err := hex.DecodeString(aptCoinTransfer)
+	assert.NoError(t, err)
+
+	raw, err := ParseRawTransaction(valid)
+	assert.NoError(t, err)
+	assert.Equal(t, "coin", raw.payload.moduleName)
+	assert.Equal(t, "transfer", raw.payload.functionName)
+	assert.Equal(t, []string{"0x1::aptos_coin::AptosCoin"}, raw.payload.typeArgs)
+	assert.Equal(t, uint64(7), raw.sequenceNumber)
+	assert.Equal(t, uint64(1700000000), raw.expirationTimestampSecs)
+
+	script := append([]byte{}, valid...)
+	script[addressLength+8] = 0
+	_, err = ParseRawTransaction(script)
+	assert.Error(t, err)
+
+	_, err = ParseRawTransaction(append(append([]byte{}, valid...), 0))
+	assert.Error(t, err)
+
+	_, err = ParseRawTransaction(valid[:len(valid)-1])
+	assert.Error(t, err)
+}
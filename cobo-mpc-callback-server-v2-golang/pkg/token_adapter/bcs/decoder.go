@@ -0,0 +1,140 @@
+package bcs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// Decoder reads the Binary Canonical Serialization of Move values, shared by Aptos and Sui
+type Decoder struct {
+	data   []byte
+	offset int
+}
+
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Remaining returns the number of bytes left
+func (d *Decoder) Remaining() int {
+	return len(d.data) - d.offset
+}
+
+// Finish rejects trailing bytes, a canonical value is decoded from all of its bytes
+func (d *Decoder) Finish() error {
+	if d.Remaining() != 0 {
+		return fmt.Errorf("%d trailing bytes", d.Remaining())
+	}
+	return nil
+}
+
+// ReadFixedBytes reads n bytes, e.g. an address
+func (d *Decoder) ReadFixedBytes(n int) ([]byte, error) {
+	if n < 0 || n > d.Remaining() {
+		return nil, fmt.Errorf("read %d bytes at offset %d: out of range", n, d.offset)
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *Decoder) ReadU8() (uint8, error) {
+	b, err := d.ReadFixedBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *Decoder) ReadU16() (uint16, error) {
+	b, err := d.ReadFixedBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (d *Decoder) ReadU64() (uint64, error) {
+	b, err := d.ReadFixedBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (d *Decoder) ReadBool() (bool, error) {
+	b, err := d.ReadU8()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid bool %d at offset %d", b, d.offset-1)
+	}
+}
+
+// ReadUleb128 reads a canonical uleb128 u32, which encodes lengths and enum variants
+func (d *Decoder) ReadUleb128() (uint32, error) {
+	var value uint64
+	for shift := 0; shift < 35; shift += 7 {
+		b, err := d.ReadU8()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if shift > 0 && b == 0 {
+				return 0, fmt.Errorf("non-canonical uleb128 at offset %d", d.offset-1)
+			}
+			if value > math.MaxUint32 {
+				return 0, fmt.Errorf("uleb128 %d overflows u32", value)
+			}
+			return uint32(value), nil
+		}
+	}
+	return 0, fmt.Errorf("uleb128 at offset %d is too long", d.offset)
+}
+
+// ReadVariant reads the variant index of an enum
+func (d *Decoder) ReadVariant() (uint32, error) {
+	return d.ReadUleb128()
+}
+
+// ReadLength reads the length of a sequence, which can not hold more items than the remaining bytes
+func (d *Decoder) ReadLength() (int, error) {
+	length, err := d.ReadUleb128()
+	if err != nil {
+		return 0, err
+	}
+	if int(length) > d.Remaining() {
+		return 0, fmt.Errorf("length %d at offset %d exceeds remaining %d bytes", length, d.offset, d.Remaining())
+	}
+	return int(length), nil
+}
+
+// ReadBytes reads a length prefixed byte vector
+func (d *Decoder) ReadBytes() ([]byte, error) {
+	length, err := d.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	return d.ReadFixedBytes(length)
+}
+
+// ReadString reads a length prefixed UTF-8 string
+func (d *Decoder) ReadString() (string, error) {
+	b, err := d.ReadBytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("invalid utf-8 string at offset %d", d.offset-len(b))
+	}
+	return string(b), nil
+}
@@ -0,0 +1,71 @@
+package bcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder_ReadUleb128(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		want      uint32
+		wantError bool
+	}{
+		{name: "Single byte", data: []byte{0x7f}, want: 127},
+		{name: "Two bytes", data: []byte{0x80, 0x01}, want: 128},
+		{name: "Max u32", data: []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, want: 0xffffffff},
+		{name: "Non-canonical", data: []byte{0x80, 0x00}, wantError: true},
+		{name: "Overflow", data: []byte{0xff, 0xff, 0xff, 0xff, 0x1f}, wantError: true},
+		{name: "Too long", data: []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x01}, wantError: true},
+		{name: "Truncated", data: []byte{0x80}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := NewDecoder(tt.data).ReadUleb128()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	d := NewDecoder([]byte{
+		0x01,       // bool
+		0x34, 0x12, // u16
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // u64
+		0x03, 'a', 'p', 't', // string
+		0x02, 0xaa, // bytes of length 2, truncated
+	})
+
+	b, err := d.ReadBool()
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	u16, err := d.ReadU16()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x1234), u16)
+
+	u64, err := d.ReadU64()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x0102030405060708), u64)
+
+	s, err := d.ReadString()
+	assert.NoError(t, err)
+	assert.Equal(t, "apt", s)
+
+	assert.Error(t, d.Finish())
+	_, err = d.ReadBytes()
+	assert.Error(t, err)
+
+	_, err = NewDecoder([]byte{0x02}).ReadBool()
+	assert.Error(t, err)
+	_, err = NewDecoder([]byte{0x02, 0xff, 0xfe}).ReadString()
+	assert.Error(t, err)
+}
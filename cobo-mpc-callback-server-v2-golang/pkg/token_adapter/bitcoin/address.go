@@ -0,0 +1,228 @@
+package bitcoin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+const cashAddrCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// cashaddr address types
+const (
+	cashAddrP2PKH byte = 0
+	cashAddrP2SH  byte = 1
+)
+
+// encodeAddress returns the address of a single address output script in the network format
+func encodeAddress(pkScript []byte, network *Network) (string, error) {
+	class, addresses, _, err := txscript.ExtractPkScriptAddrs(pkScript, network.Params)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract output address: %w", err)
+	}
+	if isWitnessClass(class) && !network.segWit() {
+		return "", fmt.Errorf("witness output is not supported on %v", network.ChainID)
+	}
+	if len(addresses) != 1 {
+		return "", fmt.Errorf("output script %x is not a standard single address script", pkScript)
+	}
+
+	if network.CashAddrPrefix == "" {
+		return addresses[0].EncodeAddress(), nil
+	}
+	switch address := addresses[0].(type) {
+	case *btcutil.AddressPubKeyHash:
+		return encodeCashAddr(network.CashAddrPrefix, cashAddrP2PKH, address.Hash160()[:])
+	case *btcutil.AddressPubKey:
+		return encodeCashAddr(network.CashAddrPrefix, cashAddrP2PKH, address.AddressPubKeyHash().Hash160()[:])
+	case *btcutil.AddressScriptHash:
+		return encodeCashAddr(network.CashAddrPrefix, cashAddrP2SH, address.Hash160()[:])
+	default:
+		return "", fmt.Errorf("output script %x has no cashaddr address", pkScript)
+	}
+}
+
+// addressScript returns the output script paying to an address in the network format
+func addressScript(address string, network *Network) ([]byte, error) {
+	decoded, err := decodeAddress(address, network)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(decoded)
+}
+
+func decodeAddress(address string, network *Network) (btcutil.Address, error) {
+	if network.CashAddrPrefix != "" && !isBase58Address(address, network) {
+		addrType, hash, err := decodeCashAddr(address, network.CashAddrPrefix)
+		if err != nil {
+			return nil, err
+		}
+		switch addrType {
+		case cashAddrP2PKH:
+			return btcutil.NewAddressPubKeyHash(hash, network.Params)
+		case cashAddrP2SH:
+			return btcutil.NewAddressScriptHashFromHash(hash, network.Params)
+		default:
+			return nil, fmt.Errorf("unknown cashaddr type %d", addrType)
+		}
+	}
+
+	if hrp := network.Params.Bech32HRPSegwit; hrp != "" && strings.HasPrefix(strings.ToLower(address), hrp+"1") {
+		return decodeSegWitAddress(address, network)
+	}
+
+	decoded, err := btcutil.DecodeAddress(address, network.Params)
+	if err != nil {
+		return nil, err
+	}
+	if !decoded.IsForNet(network.Params) {
+		return nil, fmt.Errorf("address %v is not for network %v", address, network.ChainID)
+	}
+	return decoded, nil
+}
+
+// isBase58Address reports whether the address is a legacy base58 address of the network
+func isBase58Address(address string, network *Network) bool {
+	decoded, err := btcutil.DecodeAddress(address, network.Params)
+	if err != nil {
+		return false
+	}
+	switch decoded.(type) {
+	case *btcutil.AddressPubKeyHash, *btcutil.AddressScriptHash:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeSegWitAddress decodes a bech32 segwit address with the network prefix,
+// which does not need to be registered in chaincfg
+func decodeSegWitAddress(address string, network *Network) (btcutil.Address, error) {
+	hrp, data, version, err := bech32.DecodeGeneric(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode segwit address: %w", err)
+	}
+	if hrp != network.Params.Bech32HRPSegwit {
+		return nil, fmt.Errorf("segwit address prefix %v mismatch network %v", hrp, network.ChainID)
+	}
+	if len(data) < 1 {
+		return nil, fmt.Errorf("segwit address has no witness version")
+	}
+
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid segwit address program: %w", err)
+	}
+
+	switch witnessVersion := data[0]; {
+	case witnessVersion == 0 && version == bech32.Version0 && len(program) == 20:
+		return btcutil.NewAddressWitnessPubKeyHash(program, network.Params)
+	case witnessVersion == 0 && version == bech32.Version0 && len(program) == 32:
+		return btcutil.NewAddressWitnessScriptHash(program, network.Params)
+	case witnessVersion == 1 && version == bech32.VersionM && len(program) == 32:
+		return btcutil.NewAddressTaproot(program, network.Params)
+	default:
+		return nil, fmt.Errorf("unsupported segwit address version %d", witnessVersion)
+	}
+}
+
+// encodeCashAddr encodes a hash160 in the cashaddr format with the prefix
+func encodeCashAddr(prefix string, addrType byte, hash []byte) (string, error) {
+	if len(hash) != 20 {
+		return "", fmt.Errorf("invalid cashaddr hash length %d", len(hash))
+	}
+
+	// version byte: type in the high bits, size code 0 for 160 bits
+	payload, err := bech32.ConvertBits(append([]byte{addrType << 3}, hash...), 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert cashaddr payload: %w", err)
+	}
+
+	checksum := cashAddrPolymod(append(append(cashAddrPrefixData(prefix), payload...), make([]byte, 8)...))
+
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteByte(':')
+	for _, b := range payload {
+		sb.WriteByte(cashAddrCharset[b])
+	}
+	for i := 0; i < 8; i++ {
+		sb.WriteByte(cashAddrCharset[(checksum>>(5*(7-i)))&0x1f])
+	}
+	return sb.String(), nil
+}
+
+// decodeCashAddr decodes a cashaddr address, the prefix may be omitted
+func decodeCashAddr(address, prefix string) (byte, []byte, error) {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return 0, nil, fmt.Errorf("cashaddr address %v has mixed case", address)
+	}
+	address = strings.ToLower(address)
+
+	if idx := strings.IndexByte(address, ':'); idx >= 0 {
+		if address[:idx] != prefix {
+			return 0, nil, fmt.Errorf("cashaddr prefix %v mismatch %v", address[:idx], prefix)
+		}
+		address = address[idx+1:]
+	}
+	if len(address) <= 8 {
+		return 0, nil, fmt.Errorf("cashaddr address is too short")
+	}
+
+	data := make([]byte, 0, len(address))
+	for _, c := range address {
+		idx := strings.IndexRune(cashAddrCharset, c)
+		if idx < 0 {
+			return 0, nil, fmt.Errorf("invalid cashaddr character %q", c)
+		}
+		data = append(data, byte(idx))
+	}
+	if cashAddrPolymod(append(cashAddrPrefixData(prefix), data...)) != 0 {
+		return 0, nil, fmt.Errorf("invalid cashaddr checksum")
+	}
+
+	payload, err := bech32.ConvertBits(data[:len(data)-8], 5, 8, false)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid cashaddr payload: %w", err)
+	}
+	if len(payload) != 21 || payload[0]&0x07 != 0 {
+		return 0, nil, fmt.Errorf("unsupported cashaddr hash size")
+	}
+	return payload[0] >> 3, payload[1:], nil
+}
+
+// cashAddrPrefixData returns the lower 5 bits of the prefix characters followed by the separator
+func cashAddrPrefixData(prefix string) []byte {
+	data := make([]byte, 0, len(prefix)+1)
+	for i := 0; i < len(prefix); i++ {
+		data = append(data, prefix[i]&0x1f)
+	}
+	return append(data, 0)
+}
+
+func cashAddrPolymod(values []byte) uint64 {
+	c := uint64(1)
+	for _, d := range values {
+		c0 := byte(c >> 35)
+		c = ((c & 0x07ffffffff) << 5) ^ uint64(d)
+		if c0&0x01 != 0 {
+			c ^= 0x98f2bc8e61
+		}
+		if c0&0x02 != 0 {
+			c ^= 0x79b76d99e2
+		}
+		if c0&0x04 != 0 {
+			c ^= 0xf33e5fb3c4
+		}
+		if c0&0x08 != 0 {
+			c ^= 0xae2eabe2a8
+		}
+		if c0&0x10 != 0 {
+			c ^= 0x1e4f43e470
+		}
+	}
+	return c ^ 1
+}
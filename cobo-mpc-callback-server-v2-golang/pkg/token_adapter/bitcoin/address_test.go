@@ -0,0 +1,125 @@
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCashAddr(t *testing.T) {
+	hash := common.FromHex("76a04053bda0a88bda5177b86a15c3b29f559873")
+
+	address, err := encodeCashAddr("bitcoincash", cashAddrP2PKH, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", address)
+
+	tests := []struct {
+		name      string
+		address   string
+		wantError bool
+	}{
+		{
+			name:      "With prefix",
+			address:   "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			wantError: false,
+		},
+		{
+			name:      "Without prefix",
+			address:   "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			wantError: false,
+		},
+		{
+			name:      "Upper case",
+			address:   "BITCOINCASH:QPM2QSZNHKS23Z7629MMS6S4CWEF74VCWVY22GDX6A",
+			wantError: false,
+		},
+		{
+			name:      "Mixed case",
+			address:   "bitcoincash:Qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			wantError: true,
+		},
+		{
+			name:      "Invalid checksum",
+			address:   "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6b",
+			wantError: true,
+		},
+		{
+			name:      "Wrong prefix",
+			address:   "bchtest:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addrType, decoded, err := decodeCashAddr(tt.address, "bitcoincash")
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, cashAddrP2PKH, addrType)
+				assert.Equal(t, hash, decoded)
+			}
+		})
+	}
+}
+
+func TestAddressScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		address    string
+		network    *Network
+		wantScript string
+		wantError  bool
+	}{
+		{
+			name:       "BTC P2WPKH",
+			address:    "bc1qr583w2swedy2acd7rung055k8t3n7udp7vyzyg",
+			network:    MainNet,
+			wantScript: "00141d0f172a0ecb48aee1be1f2687d2963ae33f71a1",
+		},
+		{
+			name:       "LTC P2WPKH",
+			address:    "ltc1qr583w2swedy2acd7rung055k8t3n7udp6s7xuc",
+			network:    LitecoinMainNet,
+			wantScript: "00141d0f172a0ecb48aee1be1f2687d2963ae33f71a1",
+		},
+		{
+			name:       "DOGE P2PKH",
+			address:    "D7nkDKujExBEuU8myVVtw1kWgV2haBtQcR",
+			network:    DogecoinMainNet,
+			wantScript: "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac",
+		},
+		{
+			name:       "BCH legacy P2PKH",
+			address:    "1Cu32FVupVCgHkMMRJdYJugxwo2Aprgk7H",
+			network:    BitcoinCashMainNet,
+			wantScript: "76a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac",
+		},
+		{
+			name:       "BCH cashaddr P2PKH",
+			address:    "bitcoincash:qqws79e2pm953thphc0jdp7jjcawx0m35yh5n25x03",
+			network:    BitcoinCashMainNet,
+			wantScript: "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac",
+		},
+		{
+			name:      "BTC address on LTC",
+			address:   "bc1qr583w2swedy2acd7rung055k8t3n7udp7vyzyg",
+			network:   LitecoinMainNet,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := addressScript(tt.address, tt.network)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, common.FromHex(tt.wantScript), script)
+			}
+		})
+	}
+}
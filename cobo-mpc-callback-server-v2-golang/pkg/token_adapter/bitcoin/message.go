@@ -0,0 +1,141 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// bip322Tag is the tagged hash tag of a BIP-322 message
+var bip322Tag = []byte("BIP0322-signed-message")
+
+// Message is a BIP-137 or BIP-322 message signed by the source address
+type Message struct {
+	token    *Token
+	standard string
+	hash     []byte
+	message  []byte
+}
+
+// GetHashes implements Transaction interface for a Bitcoin message
+func (m *Message) GetHashes() ([]string, error) {
+	return []string{"0x" + hex.EncodeToString(m.hash)}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for a Bitcoin message
+func (m *Message) GetDestinationAddresses() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetTransfers implements Transaction interface for a Bitcoin message
+func (m *Message) GetTransfers() ([]token_adapter.Transfer, error) {
+	return []token_adapter.Transfer{}, nil
+}
+
+// GetMessage implements MessageTransaction interface for a Bitcoin message
+func (m *Message) GetMessage() (*token_adapter.Message, error) {
+	return &token_adapter.Message{
+		Standard: m.standard,
+		Content:  token_adapter.MessageContent(m.message),
+	}, nil
+}
+
+// BIP137MessageHash returns the double sha256 of the message with the magic prefix of the network
+func BIP137MessageHash(message []byte, network *Network) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := wire.WriteVarString(&buf, 0, network.MessagePrefix); err != nil {
+		return nil, err
+	}
+	if err := wire.WriteVarBytes(&buf, 0, message); err != nil {
+		return nil, err
+	}
+	return chainhash.DoubleHashB(buf.Bytes()), nil
+}
+
+// BIP322ToSign returns the virtual to_spend and to_sign transactions of a BIP-322 simple signature
+func BIP322ToSign(message, pkScript []byte) (toSpend, toSign *wire.MsgTx, err error) {
+	messageHash := chainhash.TaggedHash(bip322Tag, message)
+	scriptSig, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(messageHash[:]).Script()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	toSpend = wire.NewMsgTx(0)
+	toSpend.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 0xffffffff},
+		SignatureScript:  scriptSig,
+		Sequence:         0,
+	})
+	toSpend.AddTxOut(wire.NewTxOut(0, pkScript))
+
+	toSign = wire.NewMsgTx(0)
+	toSign.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: toSpend.TxHash(), Index: 0},
+		Sequence:         0,
+	})
+	toSign.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	return toSpend, toSign, nil
+}
+
+// BIP322MessageHash returns the sighash of the to_sign transaction signed by a P2WPKH or P2TR address
+func BIP322MessageHash(message []byte, address string, network *Network) ([]byte, error) {
+	pkScript, err := addressScript(address, network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer address %v: %w", address, err)
+	}
+
+	toSpend, toSign, err := BIP322ToSign(message, pkScript)
+	if err != nil {
+		return nil, err
+	}
+	fetcher := txscript.NewCannedPrevOutputFetcher(pkScript, 0)
+	sigHashes := txscript.NewTxSigHashes(toSign, fetcher)
+
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.WitnessV0PubKeyHashTy:
+		return txscript.CalcWitnessSigHash(pkScript, sigHashes, txscript.SigHashAll, toSign, 0, toSpend.TxOut[0].Value)
+	case txscript.WitnessV1TaprootTy:
+		return txscript.CalcTaprootSignatureHash(sigHashes, txscript.SigHashDefault, toSign, 0, fetcher)
+	default:
+		return nil, fmt.Errorf("bip322 signature of address %v is not supported, only P2WPKH and P2TR are", address)
+	}
+}
+
+// buildMessage builds the message of a BIP-137 or BIP-322 message sign request, nil for another request
+func (t *Token) buildMessage(txInfo *token_adapter.TransactionInfo) (*Message, error) {
+	if txInfo == nil || txInfo.Transaction == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	destination := txInfo.Transaction.Destination
+	switch {
+	case destination.TransactionBIP137Destination != nil:
+		message := common.FromHex(destination.TransactionBIP137Destination.MessageBip137)
+		hash, err := BIP137MessageHash(message, t.network)
+		if err != nil {
+			return nil, err
+		}
+		return &Message{token: t, standard: token_adapter.MessageStandardBIP137, hash: hash, message: message}, nil
+	case destination.TransactionBIP322Destination != nil:
+		if !t.network.segWit() {
+			return nil, fmt.Errorf("bip322 message is not supported on %v", t.network.ChainID)
+		}
+		if len(txInfo.SourceAddresses) != 1 {
+			return nil, fmt.Errorf("bip322 message needs one source address, got %d", len(txInfo.SourceAddresses))
+		}
+		message := common.FromHex(destination.TransactionBIP322Destination.MessageBip322)
+		hash, err := BIP322MessageHash(message, txInfo.SourceAddresses[0].Address, t.network)
+		if err != nil {
+			return nil, err
+		}
+		return &Message{token: t, standard: token_adapter.MessageStandardBIP322, hash: hash, message: message}, nil
+	default:
+		return nil, nil //nolint:nilnil
+	}
+}
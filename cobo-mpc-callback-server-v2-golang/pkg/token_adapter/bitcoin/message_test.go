@@ -0,0 +1,142 @@
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// bip322Address is the signer address of the BIP-322 test vectors
+const bip322Address = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+
+func TestBIP322ToSign(t *testing.T) {
+	pkScript, err := addressScript(bip322Address, MainNet)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		message     string
+		wantToSpend string
+		wantToSign  string
+	}{
+		{
+			name:        "Empty message",
+			message:     "",
+			wantToSpend: "c5680aa69bb8d860bf82d4e9cd3504b55dde018de765a91bb566283c545a99a7",
+			wantToSign:  "1e9654e951a5ba44c8604c4de6c67fd78a27e81dcadcfe1edf638ba3aaebaed6",
+		},
+		{
+			name:        "Hello World",
+			message:     "Hello World",
+			wantToSpend: "b79d196740ad5217771c1098fc4a4b51e0535c32236c71f1ea4d61a2d603352b",
+			wantToSign:  "88737ae86f2077145f93cc4b153ae9a1cb8d56afa511988c149c5c8c9d93bddf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toSpend, toSign, err := BIP322ToSign([]byte(tt.message), pkScript)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantToSpend, toSpend.TxHash().String())
+			assert.Equal(t, tt.wantToSign, toSign.TxHash().String())
+		})
+	}
+}
+
+func TestToken_BuildTransaction_Message(t *testing.T) {
+	message := []byte("hello world")
+	first := sha256.Sum256(append([]byte("\x18Bitcoin Signed Message:\n\x0b"), message...))
+	bip137Hash := sha256.Sum256(first[:])
+
+	taprootAddress, err := encodeAddress(common.FromHex("51205c1d8d910ffd0765e14664f1de01764035dda11e621c86059ec8e7182bdc8d66"), MainNet)
+	assert.NoError(t, err)
+
+	bip137 := coboWaaS2.TransactionBIP137DestinationAsTransactionDestination(
+		&coboWaaS2.TransactionBIP137Destination{MessageBip137: hex.EncodeToString(message)},
+	)
+	bip322 := coboWaaS2.TransactionBIP322DestinationAsTransactionDestination(
+		&coboWaaS2.TransactionBIP322Destination{MessageBip322: hex.EncodeToString(message)},
+	)
+
+	tests := []struct {
+		name         string
+		token        *Token
+		destination  coboWaaS2.TransactionDestination
+		source       string
+		wantHash     string
+		wantStandard string
+		wantError    bool
+	}{
+		{
+			name:         "BIP137",
+			token:        &Token{tokenID: "BTC", network: MainNet},
+			destination:  bip137,
+			wantHash:     "0x" + hex.EncodeToString(bip137Hash[:]),
+			wantStandard: token_adapter.MessageStandardBIP137,
+		},
+		{
+			name:         "BIP322 P2WPKH",
+			token:        &Token{tokenID: "BTC", network: MainNet},
+			destination:  bip322,
+			source:       bip322Address,
+			wantStandard: token_adapter.MessageStandardBIP322,
+		},
+		{
+			name:         "BIP322 P2TR",
+			token:        &Token{tokenID: "BTC", network: MainNet},
+			destination:  bip322,
+			source:       taprootAddress,
+			wantStandard: token_adapter.MessageStandardBIP322,
+		},
+		{
+			name:        "BIP322 P2PKH",
+			token:       &Token{tokenID: "BTC", network: MainNet},
+			destination: bip322,
+			source:      "1HkrFxLyNoQydvW889WmubyRHycE4bvw1Y",
+			wantError:   true,
+		},
+		{
+			name:        "BIP322 without segwit",
+			token:       &Token{tokenID: "DOGE", network: DogecoinMainNet},
+			destination: bip322,
+			source:      "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L",
+			wantError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txInfo := &token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{Destination: tt.destination},
+			}
+			if tt.source != "" {
+				txInfo.SourceAddresses = []coboWaaS2.AddressInfo{{Address: tt.source}}
+			}
+
+			tx, err := tt.token.BuildTransaction(txInfo)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Len(t, hashes, 1)
+			assert.Len(t, common.FromHex(hashes[0]), 32)
+			if tt.wantHash != "" {
+				assert.Equal(t, tt.wantHash, hashes[0])
+			}
+
+			msg, err := tx.(token_adapter.MessageTransaction).GetMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Message{Standard: tt.wantStandard, Content: "hello world"}, msg)
+		})
+	}
+}
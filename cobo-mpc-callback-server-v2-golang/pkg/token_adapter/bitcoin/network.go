@@ -0,0 +1,83 @@
+package bitcoin
+
+import (
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// sigHashForkID is the Bitcoin Cash replay protection flag of the sighash type
+const sigHashForkID txscript.SigHashType = 0x40
+
+// Network holds the parameters of a bitcoin like chain
+type Network struct {
+	// ChainID is the Cobo chain id, e.g. BTC
+	ChainID string
+	// Params holds the base58 address versions and the segwit bech32 prefix, which is empty without segwit
+	Params *chaincfg.Params
+	// Decimals of the native token, used to convert the selected UTXO values
+	Decimals int32
+	// CashAddrPrefix encodes addresses in the cashaddr format when set, e.g. bitcoincash
+	CashAddrPrefix string
+	// ForkID signs every input with the SIGHASH_FORKID digest
+	ForkID bool
+	// MessagePrefix is the magic prefix of a BIP-137 signed message
+	MessagePrefix string
+}
+
+var (
+	MainNet = &Network{
+		ChainID:       "BTC",
+		Params:        &chaincfg.MainNetParams,
+		Decimals:      8,
+		MessagePrefix: "Bitcoin Signed Message:\n",
+	}
+
+	LitecoinMainNet = &Network{
+		ChainID: "LTC",
+		Params: &chaincfg.Params{
+			Name:             "litecoin",
+			Bech32HRPSegwit:  "ltc",
+			PubKeyHashAddrID: 0x30,
+			ScriptHashAddrID: 0x32,
+		},
+		Decimals:      8,
+		MessagePrefix: "Litecoin Signed Message:\n",
+	}
+
+	DogecoinMainNet = &Network{
+		ChainID: "DOGE",
+		Params: &chaincfg.Params{
+			Name:             "dogecoin",
+			PubKeyHashAddrID: 0x1e,
+			ScriptHashAddrID: 0x16,
+		},
+		Decimals:      8,
+		MessagePrefix: "Dogecoin Signed Message:\n",
+	}
+
+	BitcoinCashMainNet = &Network{
+		ChainID: "BCH",
+		Params: &chaincfg.Params{
+			Name:             "bitcoincash",
+			PubKeyHashAddrID: 0x00,
+			ScriptHashAddrID: 0x05,
+		},
+		Decimals:       8,
+		CashAddrPrefix: "bitcoincash",
+		ForkID:         true,
+		MessagePrefix:  "Bitcoin Signed Message:\n",
+	}
+)
+
+func (n *Network) segWit() bool {
+	return n.Params.Bech32HRPSegwit != ""
+}
+
+func isWitnessClass(class txscript.ScriptClass) bool {
+	switch class {
+	case txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy, txscript.WitnessV1TaprootTy, txscript.WitnessUnknownTy:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,254 @@
+package bitcoin
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// psbtMagic is the leading bytes of a serialized PSBT
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+type Token struct {
+	tokenID string
+	network *Network
+}
+
+func NewToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+		network: MainNet,
+	}
+}
+
+func NewLitecoinToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+		network: LitecoinMainNet,
+	}
+}
+
+func NewDogecoinToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+		network: DogecoinMainNet,
+	}
+}
+
+func NewBitcoinCashToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+		network: BitcoinCashMainNet,
+	}
+}
+
+func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	message, err := t.buildMessage(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare message error: %w", err)
+	}
+	if message != nil {
+		return message, nil
+	}
+
+	preTxData, err := prepareBuildTransactionData(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
+	}
+
+	packet, err := ParseBitcoinTransaction(preTxData.rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("prepare bitcoin transaction error: %w", err)
+	}
+
+	inputs, err := resolveInputs(packet, preTxData.selectedUtxos, t.network)
+	if err != nil {
+		return nil, fmt.Errorf("resolve bitcoin transaction inputs error: %w", err)
+	}
+
+	return &Transaction{packet: packet, inputs: inputs, PrepareTransactionData: preTxData, token: t}, nil
+}
+
+func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
+		return nil, fmt.Errorf("transaction info raw tx is nil")
+	}
+
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
+
+	var sourceAddresses []string
+	for _, source := range txInfo.SourceAddresses {
+		sourceAddresses = append(sourceAddresses, source.Address)
+	}
+
+	return &PrepareTransactionData{
+		rawTx:           rawTxBytes,
+		chainID:         txInfo.Transaction.GetChainId(),
+		sourceAddresses: sourceAddresses,
+		selectedUtxos:   txInfo.Transaction.RawTxInfo.SelectedUtxos,
+	}, nil
+}
+
+// ParseBitcoinTransaction parses a PSBT or an unsigned raw transaction into a PSBT packet
+func ParseBitcoinTransaction(rawTx []byte) (*psbt.Packet, error) {
+	if len(rawTx) == 0 {
+		return nil, fmt.Errorf("transaction raw data is empty")
+	}
+
+	if bytes.HasPrefix(rawTx, psbtMagic) {
+		packet, err := psbt.NewFromRawBytes(bytes.NewReader(rawTx), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse psbt: %w", err)
+		}
+		return packet, nil
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return nil, fmt.Errorf("failed to deserialize transaction: %w", err)
+	}
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build psbt from transaction: %w", err)
+	}
+	return packet, nil
+}
+
+// resolveInputs collects the spent outputs and scripts of every input, from the PSBT
+// or from the selected UTXOs of the Cobo transaction
+func resolveInputs(packet *psbt.Packet, utxos []coboWaaS2.TransactionSelectedUtxo, network *Network) ([]*inputInfo, error) {
+	tx := packet.UnsignedTx
+	if len(tx.TxIn) == 0 {
+		return nil, fmt.Errorf("transaction has no input")
+	}
+
+	inputs := make([]*inputInfo, 0, len(tx.TxIn))
+	for idx, txIn := range tx.TxIn {
+		pIn := packet.Inputs[idx]
+		info := &inputInfo{sigHashType: pIn.SighashType}
+		outPoint := txIn.PreviousOutPoint
+
+		switch {
+		case pIn.WitnessUtxo != nil:
+			info.prevOut = pIn.WitnessUtxo
+		case pIn.NonWitnessUtxo != nil:
+			if pIn.NonWitnessUtxo.TxHash() != outPoint.Hash || int(outPoint.Index) >= len(pIn.NonWitnessUtxo.TxOut) {
+				return nil, fmt.Errorf("non witness utxo of input %d mismatch outpoint %v", idx, outPoint)
+			}
+			info.prevOut = pIn.NonWitnessUtxo.TxOut[outPoint.Index]
+		}
+
+		if len(pIn.RedeemScript) > 0 {
+			info.redeemScript = pIn.RedeemScript
+		}
+		if len(pIn.WitnessScript) > 0 {
+			info.witnessScript = pIn.WitnessScript
+		}
+		if len(pIn.TaprootLeafScript) > 0 {
+			info.leafScript = pIn.TaprootLeafScript[0].Script
+		}
+
+		if info.prevOut == nil || info.redeemScript == nil && info.witnessScript == nil || info.leafScript == nil {
+			if err := fillFromUtxo(info, outPoint, utxos, network); err != nil {
+				return nil, fmt.Errorf("input %d: %w", idx, err)
+			}
+		}
+		if info.prevOut == nil {
+			return nil, fmt.Errorf("spent output of input %d is unknown", idx)
+		}
+
+		inputs = append(inputs, info)
+	}
+
+	return inputs, nil
+}
+
+// fillFromUtxo fills the missing input data from the selected UTXO spent by the outpoint
+func fillFromUtxo(info *inputInfo, outPoint wire.OutPoint, utxos []coboWaaS2.TransactionSelectedUtxo, network *Network) error {
+	utxo, err := findUtxo(outPoint, utxos)
+	if err != nil || utxo == nil {
+		return err
+	}
+
+	if info.prevOut == nil {
+		prevOut, err := utxoOutput(utxo, network)
+		if err != nil {
+			return err
+		}
+		info.prevOut = prevOut
+	}
+	// the script of a selected UTXO is the witness script of a P2WSH output, and the redeem script otherwise
+	if script := utxo.GetRedeemScript(); script != "" {
+		if txscript.IsPayToWitnessScriptHash(info.prevOut.PkScript) {
+			if info.witnessScript == nil {
+				info.witnessScript = common.FromHex(script)
+			}
+		} else if info.redeemScript == nil {
+			info.redeemScript = common.FromHex(script)
+		}
+	}
+	if info.leafScript == nil && utxo.GetRevealedScript() != "" {
+		info.leafScript = common.FromHex(utxo.GetRevealedScript())
+	}
+	return nil
+}
+
+func findUtxo(outPoint wire.OutPoint, utxos []coboWaaS2.TransactionSelectedUtxo) (*coboWaaS2.TransactionSelectedUtxo, error) {
+	for idx := range utxos {
+		utxo := &utxos[idx]
+		if utxo.TxHash == nil || utxo.VoutN == nil {
+			continue
+		}
+		hash, err := chainhash.NewHashFromStr(*utxo.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selected utxo hash %v: %w", *utxo.TxHash, err)
+		}
+		if *hash == outPoint.Hash && *utxo.VoutN >= 0 && uint32(*utxo.VoutN) == outPoint.Index {
+			return utxo, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+// utxoOutput rebuilds the spent output from the selected UTXO address and value
+func utxoOutput(utxo *coboWaaS2.TransactionSelectedUtxo, network *Network) (*wire.TxOut, error) {
+	if utxo.Address == nil || utxo.Value == nil {
+		return nil, fmt.Errorf("selected utxo address or value is nil")
+	}
+
+	pkScript, err := addressScript(*utxo.Address, network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selected utxo address %v: %w", *utxo.Address, err)
+	}
+
+	value, err := toBaseUnit(*utxo.Value, network.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selected utxo value: %w", err)
+	}
+
+	return wire.NewTxOut(value, pkScript), nil
+}
+
+// toBaseUnit converts a value in token units to satoshi
+func toBaseUnit(value string, decimals int32) (int64, error) {
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return 0, fmt.Errorf("value %q is not a decimal number", value)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	amount.Mul(amount, new(big.Rat).SetInt(scale))
+	if !amount.IsInt() || amount.Sign() < 0 || !amount.Num().IsInt64() {
+		return 0, fmt.Errorf("value %q is out of range", value)
+	}
+	return amount.Num().Int64(), nil
+}
@@ -0,0 +1,192 @@
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// BIP143 native P2WPKH example, spending a P2PKH and a P2WPKH output
+	btcRawTx = "0x0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000"
+
+	// PSBT spending a P2TR output, carrying the witness utxo
+	btcPsbt = "0x70736274ff01007d0200000001b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c30100000000ffffffff0250c30000000000001600141d0f172a0ecb48aee1be1f2687d2963ae33f71a1409c0000000000002251205c1d8d910ffd0765e14664f1de01764035dda11e621c86059ec8e7182bdc8d66000000000001012ba0860100000000002251205c1d8d910ffd0765e14664f1de01764035dda11e621c86059ec8e7182bdc8d66000000"
+
+	// PSBT of the BIP143 P2SH-P2WSH 6-of-6 multisig example, with the redeem script and the witness script
+	btcNestedP2wshPsbt = "0x70736274ff010077010000000136641869ca081e70f394c6948e8af409e18b619df2ed74aa106c1ca29787b96e0100000000ffffffff0200e9a435000000001976a914389ffce9cd9ae88dcc0631e88a821ffdbe9bfe2688acc0832f05000000001976a9147480a33f950689af511e6e84c138dbbd3c3ee41588ac0000000000010120b168de3a0000000017a9149993a429037b5d912407a71c252019287b8d27a5870104220020a16b5755f7f6f96dbd65f5f0d6ab9418b89af4b1f14a1bb8a09062c35f0dcb540105cf56210307b8ae49ac90a048e9b53357a2354b3334e9c8bee813ecb98e99a7e07e8c3ba32103b28f0c28bfab54554ae8c658ac5c3e0ce6e79ad336331f78c428dd43eea8449b21034b8113d703413d57761b8b9781957b8c0ac1dfe69f492580ca4195f50376ba4a21033400f6afecb833092a9a21cfdf1ed1376e58c5d1f47de74683123987e967a8f42103a6d48b1131e94ba04d9737d61acdaa1322008af9602b3b14862c07a1789aac162102d8b661b0b3302ee2f162b09e07a55ad5dfbe673a9f01d9f0c19617681024306b56ae000000"
+
+	btcSelectedUtxos = []coboWaaS2.TransactionSelectedUtxo{
+		{
+			TxHash:  coboWaaS2.PtrString("9f96ade4b41d5433f4eda31e1738ec2b36f6e7d1420d94a6af99801a88f7f7ff"),
+			VoutN:   coboWaaS2.PtrInt32(0),
+			Address: coboWaaS2.PtrString("1HkrFxLyNoQydvW889WmubyRHycE4bvw1Y"),
+			Value:   coboWaaS2.PtrString("6.25"),
+		},
+		{
+			TxHash:  coboWaaS2.PtrString("8ac60eb9575db5b2d987e29f301b5b819ea83a5c6579d282d189cc04b8e151ef"),
+			VoutN:   coboWaaS2.PtrInt32(1),
+			Address: coboWaaS2.PtrString("bc1qr583w2swedy2acd7rung055k8t3n7udp7vyzyg"),
+			Value:   coboWaaS2.PtrString("6"),
+		},
+	}
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("BTC")
+	btcToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "BTC", btcToken.tokenID)
+	assert.Equal(t, MainNet, btcToken.network)
+}
+
+func TestNewForkTokens(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       token_adapter.Token
+		wantNetwork *Network
+	}{
+		{name: "LTC", token: NewLitecoinToken("LTC"), wantNetwork: LitecoinMainNet},
+		{name: "DOGE", token: NewDogecoinToken("DOGE"), wantNetwork: DogecoinMainNet},
+		{name: "BCH", token: NewBitcoinCashToken("BCH"), wantNetwork: BitcoinCashMainNet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forkToken, ok := tt.token.(*Token)
+			assert.True(t, ok)
+			assert.Equal(t, tt.name, forkToken.tokenID)
+			assert.Equal(t, tt.wantNetwork, forkToken.network)
+		})
+	}
+}
+
+func TestToken_BuildTransaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		txInfo    *token_adapter.TransactionInfo
+		wantError bool
+	}{
+		{
+			name: "Valid raw transaction with selected utxos",
+			txInfo: &token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+						UnsignedRawTx: &btcRawTx,
+						SelectedUtxos: btcSelectedUtxos,
+					},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "Valid PSBT",
+			txInfo: &token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+						UnsignedRawTx: &btcPsbt,
+					},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "Raw transaction without selected utxos",
+			txInfo: &token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+						UnsignedRawTx: &btcRawTx,
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name:      "Nil transaction info",
+			txInfo:    nil,
+			wantError: true,
+		},
+		{
+			name: "Nil raw tx",
+			txInfo: &token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+						UnsignedRawTx: nil,
+					},
+				},
+			},
+			wantError: true,
+		},
+	}
+
+	token := NewToken("BTC")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := token.BuildTransaction(tt.txInfo)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, tx)
+			}
+		})
+	}
+}
+
+func TestParseBitcoinTransaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		rawTx     string
+		wantError bool
+	}{
+		{
+			name:      "Raw transaction",
+			rawTx:     btcRawTx,
+			wantError: false,
+		},
+		{
+			name:      "PSBT",
+			rawTx:     btcPsbt,
+			wantError: false,
+		},
+		{
+			name:      "Empty",
+			rawTx:     "",
+			wantError: true,
+		},
+		{
+			name:      "Invalid",
+			rawTx:     "0x1234",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet, err := ParseBitcoinTransaction(common.FromHex(tt.rawTx))
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, packet)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, packet)
+			}
+		})
+	}
+}
+
+func TestToBaseUnit(t *testing.T) {
+	value, err := toBaseUnit("0.00012345", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), value)
+
+	_, err = toBaseUnit("0.000000001", 8)
+	assert.Error(t, err)
+
+	_, err = toBaseUnit("-1", 8)
+	assert.Error(t, err)
+}
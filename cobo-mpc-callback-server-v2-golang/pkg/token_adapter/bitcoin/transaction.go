@@ -0,0 +1,271 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	packet *psbt.Packet
+	inputs []*inputInfo
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx           []byte
+	chainID         string
+	sourceAddresses []string
+	selectedUtxos   []coboWaaS2.TransactionSelectedUtxo
+}
+
+// inputInfo holds the data needed to compute the sighash of an input
+type inputInfo struct {
+	prevOut *wire.TxOut
+	// redeemScript is the P2SH redeem script, the witness program of a nested segwit input
+	redeemScript []byte
+	// witnessScript is the script of a P2WSH or a nested P2WSH input, committed to by its witness program
+	witnessScript []byte
+	// leafScript is the tapscript leaf of a taproot script path spend
+	leafScript  []byte
+	sigHashType txscript.SigHashType
+}
+
+// GetHashes implements Transaction interface for Bitcoin, returning the sighash of every input
+func (t *Transaction) GetHashes() ([]string, error) {
+	tx := t.packet.UnsignedTx
+	fetcher := t.prevOutFetcher()
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+
+	hashes := make([]string, 0, len(t.inputs))
+	for idx, info := range t.inputs {
+		hash, err := t.inputSigHash(idx, info, sigHashes, fetcher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute sighash of input %d: %w", idx, err)
+		}
+		hashes = append(hashes, "0x"+hex.EncodeToString(hash))
+	}
+	return hashes, nil
+}
+
+// inputSigHash computes the legacy, BIP143 or BIP341 sighash by the spent output script
+func (t *Transaction) inputSigHash(
+	idx int,
+	info *inputInfo,
+	sigHashes *txscript.TxSigHashes,
+	fetcher txscript.PrevOutputFetcher,
+) ([]byte, error) {
+	tx := t.packet.UnsignedTx
+	pkScript := info.prevOut.PkScript
+	hashType := info.sigHashType
+	class := txscript.GetScriptClass(pkScript)
+
+	if isWitnessClass(class) && !t.token.network.segWit() {
+		return nil, fmt.Errorf("witness input is not supported on %v", t.token.network.ChainID)
+	}
+	if t.token.network.ForkID {
+		return t.forkIDSigHash(idx, info, sigHashes)
+	}
+	if err := checkSigHashType(hashType, class == txscript.WitnessV1TaprootTy); err != nil {
+		return nil, err
+	}
+
+	switch class {
+	case txscript.WitnessV1TaprootTy:
+		if len(info.leafScript) > 0 {
+			leaf := txscript.NewBaseTapLeaf(info.leafScript)
+			return txscript.CalcTapscriptSignaturehash(sigHashes, hashType, tx, idx, fetcher, leaf)
+		}
+		return txscript.CalcTaprootSignatureHash(sigHashes, hashType, tx, idx, fetcher)
+	case txscript.WitnessV0PubKeyHashTy:
+		return txscript.CalcWitnessSigHash(pkScript, sigHashes, legacyHashType(hashType), tx, idx, info.prevOut.Value)
+	case txscript.WitnessV0ScriptHashTy:
+		if err := checkWitnessScript(pkScript, info.witnessScript); err != nil {
+			return nil, err
+		}
+		return txscript.CalcWitnessSigHash(info.witnessScript, sigHashes, legacyHashType(hashType), tx, idx, info.prevOut.Value)
+	case txscript.ScriptHashTy:
+		if len(info.redeemScript) == 0 {
+			return nil, fmt.Errorf("redeem script is missing")
+		}
+		if !bytes.Equal(pkScript[2:22], btcutil.Hash160(info.redeemScript)) {
+			return nil, fmt.Errorf("redeem script mismatch script hash of spent output")
+		}
+		if txscript.IsPayToWitnessPubKeyHash(info.redeemScript) {
+			return txscript.CalcWitnessSigHash(info.redeemScript, sigHashes, legacyHashType(hashType), tx, idx, info.prevOut.Value)
+		}
+		// a nested P2WSH input signs the witness script with BIP143, the redeem script is only its witness program
+		if txscript.IsPayToWitnessScriptHash(info.redeemScript) {
+			if err := checkWitnessScript(info.redeemScript, info.witnessScript); err != nil {
+				return nil, err
+			}
+			return txscript.CalcWitnessSigHash(info.witnessScript, sigHashes, legacyHashType(hashType), tx, idx, info.prevOut.Value)
+		}
+		return txscript.CalcSignatureHash(info.redeemScript, legacyHashType(hashType), tx, idx)
+	default:
+		return txscript.CalcSignatureHash(pkScript, legacyHashType(hashType), tx, idx)
+	}
+}
+
+// checkWitnessScript checks the witness script against the sha256 of the P2WSH witness program
+func checkWitnessScript(program []byte, witnessScript []byte) error {
+	if len(witnessScript) == 0 {
+		return fmt.Errorf("witness script is missing")
+	}
+	hash := sha256.Sum256(witnessScript)
+	if !bytes.Equal(program[2:], hash[:]) {
+		return fmt.Errorf("witness script mismatch witness program")
+	}
+	return nil
+}
+
+// forkIDSigHash computes the Bitcoin Cash SIGHASH_FORKID digest, which follows the BIP143 algorithm for every input
+func (t *Transaction) forkIDSigHash(idx int, info *inputInfo, sigHashes *txscript.TxSigHashes) ([]byte, error) {
+	hashType := legacyHashType(info.sigHashType) | sigHashForkID
+	if hashType != txscript.SigHashAll|sigHashForkID {
+		return nil, fmt.Errorf("sighash type %#x is not SIGHASH_ALL|SIGHASH_FORKID", uint32(info.sigHashType))
+	}
+
+	scriptCode := info.prevOut.PkScript
+	if txscript.GetScriptClass(scriptCode) == txscript.ScriptHashTy {
+		if len(info.redeemScript) == 0 {
+			return nil, fmt.Errorf("redeem script is missing")
+		}
+		scriptCode = info.redeemScript
+	}
+
+	return txscript.CalcWitnessSigHash(scriptCode, sigHashes, hashType, t.packet.UnsignedTx, idx, info.prevOut.Value)
+}
+
+// checkSigHashType rejects SIGHASH_NONE, SIGHASH_SINGLE and ANYONECANPAY, whose signature does not commit to every
+// input and output, so the outputs checked here could be rewritten after signing. An unset PSBT sighash type is
+// SIGHASH_ALL, and SIGHASH_DEFAULT signs the same as SIGHASH_ALL for a taproot input.
+func checkSigHashType(hashType txscript.SigHashType, taproot bool) error {
+	if hashType == txscript.SigHashAll || hashType == txscript.SigHashDefault {
+		return nil
+	}
+	if taproot {
+		return fmt.Errorf("sighash type %#x is neither SIGHASH_DEFAULT nor SIGHASH_ALL", uint32(hashType))
+	}
+	return fmt.Errorf("sighash type %#x is not SIGHASH_ALL", uint32(hashType))
+}
+
+// legacyHashType defaults the sighash type of non taproot inputs to SIGHASH_ALL
+func legacyHashType(hashType txscript.SigHashType) txscript.SigHashType {
+	if hashType == txscript.SigHashDefault {
+		return txscript.SigHashAll
+	}
+	return hashType
+}
+
+func (t *Transaction) prevOutFetcher() *txscript.MultiPrevOutFetcher {
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for idx, txIn := range t.packet.UnsignedTx.TxIn {
+		fetcher.AddPrevOut(txIn.PreviousOutPoint, t.inputs[idx].prevOut)
+	}
+	return fetcher
+}
+
+// GetDestinationAddresses implements Transaction interface for Bitcoin
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Bitcoin, returning the outputs other than the change
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = t.token.network.ChainID
+	}
+
+	// change pays back to the script of a source address, matching by script accepts any address format Cobo uses
+	changeScripts := make(map[string]bool)
+	for _, source := range t.sourceAddresses {
+		if pkScript, err := addressScript(source, t.token.network); err == nil {
+			changeScripts[string(pkScript)] = true
+		}
+	}
+
+	var transfers []token_adapter.Transfer
+	for idx, txOut := range t.packet.UnsignedTx.TxOut {
+		if txscript.GetScriptClass(txOut.PkScript) == txscript.NullDataTy {
+			if txOut.Value != 0 {
+				return nil, fmt.Errorf("output %d burns %d with a null data script", idx, txOut.Value)
+			}
+			continue
+		}
+
+		address, err := encodeAddress(txOut.PkScript, t.token.network)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %w", idx, err)
+		}
+		if changeScripts[string(txOut.PkScript)] {
+			continue
+		}
+
+		transfers = append(transfers, token_adapter.Transfer{
+			Chain:  chainID,
+			To:     address,
+			Amount: big.NewInt(txOut.Value),
+		})
+	}
+
+	return transfers, nil
+}
+
+// GetSourceAddresses implements SourceTransaction interface for Bitcoin, returning the owner of every spent output.
+// The source address of the same script is returned as is, so it matches whatever address format Cobo uses.
+func (t *Transaction) GetSourceAddresses() ([]string, error) {
+	sourceScripts := make(map[string]string)
+	for _, source := range t.sourceAddresses {
+		if pkScript, err := addressScript(source, t.token.network); err == nil {
+			sourceScripts[string(pkScript)] = source
+		}
+	}
+
+	addresses := make([]string, 0, len(t.inputs))
+	for idx, info := range t.inputs {
+		if source, ok := sourceScripts[string(info.prevOut.PkScript)]; ok {
+			addresses = append(addresses, source)
+			continue
+		}
+		address, err := encodeAddress(info.prevOut.PkScript, t.token.network)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", idx, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// GetFee implements FeeTransaction interface for Bitcoin, the fee is the inputs minus the outputs
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	fee := new(big.Int)
+	for _, info := range t.inputs {
+		fee.Add(fee, big.NewInt(info.prevOut.Value))
+	}
+	for _, txOut := range t.packet.UnsignedTx.TxOut {
+		fee.Sub(fee, big.NewInt(txOut.Value))
+	}
+	if fee.Sign() < 0 {
+		return nil, fmt.Errorf("transaction outputs exceed inputs by %v", new(big.Int).Neg(fee))
+	}
+
+	return &token_adapter.Fee{FeeLimit: fee}, nil
+}
@@ -0,0 +1,325 @@
+package bitcoin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestTransaction(t *testing.T, token token_adapter.Token, rawTx string, utxos []coboWaaS2.TransactionSelectedUtxo, sources ...string) *Transaction {
+	var sourceAddresses []coboWaaS2.AddressInfo
+	for _, source := range sources {
+		sourceAddresses = append(sourceAddresses, coboWaaS2.AddressInfo{Address: source})
+	}
+
+	tx, err := token.BuildTransaction(&token_adapter.TransactionInfo{
+		SourceAddresses: sourceAddresses,
+		Transaction: &coboWaaS2.Transaction{
+			RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+				UnsignedRawTx: &rawTx,
+				SelectedUtxos: utxos,
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	btcTx, ok := tx.(*Transaction)
+	assert.True(t, ok)
+	return btcTx
+}
+
+func TestTransaction_GetHashes(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawTx      string
+		utxos      []coboWaaS2.TransactionSelectedUtxo
+		wantHashes []string
+	}{
+		{
+			name:  "Legacy and BIP143 inputs",
+			rawTx: btcRawTx,
+			utxos: btcSelectedUtxos,
+			wantHashes: []string{
+				"0x309e93b10b8faba95cb4aa73d554a6a0f8462b50c01032c9028ffd55c4928278",
+				"0xc37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670",
+			},
+		},
+		{
+			name:  "BIP341 key path input",
+			rawTx: btcPsbt,
+			wantHashes: []string{
+				"0x4bd4b69e9e3afcb559d3ab0f33c4393c4ded996c9b92f96ac7c32e559a638835",
+			},
+		},
+		{
+			name:  "BIP143 nested P2WSH input",
+			rawTx: btcNestedP2wshPsbt,
+			wantHashes: []string{
+				"0x185c0be5263dce5b4bb50a047973c1b6272bfbd0103a89444597dc40b248ee7c",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := buildTestTransaction(t, NewToken("BTC"), tt.rawTx, tt.utxos)
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+		})
+	}
+}
+
+func TestTransaction_GetHashes_NestedP2WSH(t *testing.T) {
+	tests := []struct {
+		name          string
+		redeemScript  []byte
+		witnessScript []byte
+	}{
+		{name: "Missing witness script"},
+		{name: "Witness script as redeem script", redeemScript: []byte{txscript.OP_1, txscript.OP_CHECKSIG}},
+		{name: "Witness script mismatch witness program", witnessScript: []byte{txscript.OP_1, txscript.OP_CHECKSIG}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := buildTestTransaction(t, NewToken("BTC"), btcNestedP2wshPsbt, nil)
+			if tt.redeemScript != nil {
+				tx.inputs[0].redeemScript = tt.redeemScript
+			}
+			tx.inputs[0].witnessScript = tt.witnessScript
+
+			_, err := tx.GetHashes()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTransaction_GetHashes_SigHashType(t *testing.T) {
+	bchUtxos := []coboWaaS2.TransactionSelectedUtxo{btcSelectedUtxos[0], btcSelectedUtxos[1]}
+	bchUtxos[1].Address = coboWaaS2.PtrString("bitcoincash:qqws79e2pm953thphc0jdp7jjcawx0m35yh5n25x03")
+
+	tests := []struct {
+		name      string
+		token     token_adapter.Token
+		rawTx     string
+		utxos     []coboWaaS2.TransactionSelectedUtxo
+		hashType  txscript.SigHashType
+		wantError bool
+	}{
+		{name: "Legacy SIGHASH_ALL", token: NewToken("BTC"), rawTx: btcRawTx, utxos: btcSelectedUtxos, hashType: txscript.SigHashAll},
+		{name: "Legacy SIGHASH_NONE", token: NewToken("BTC"), rawTx: btcRawTx, utxos: btcSelectedUtxos, hashType: txscript.SigHashNone, wantError: true},
+		{
+			name: "Legacy SIGHASH_SINGLE", token: NewToken("BTC"), rawTx: btcRawTx, utxos: btcSelectedUtxos,
+			hashType: txscript.SigHashSingle, wantError: true,
+		},
+		{
+			name: "Legacy SIGHASH_ALL|ANYONECANPAY", token: NewToken("BTC"), rawTx: btcRawTx, utxos: btcSelectedUtxos,
+			hashType: txscript.SigHashAll | txscript.SigHashAnyOneCanPay, wantError: true,
+		},
+		{
+			name: "Legacy SIGHASH_NONE|ANYONECANPAY", token: NewToken("BTC"), rawTx: btcRawTx, utxos: btcSelectedUtxos,
+			hashType: txscript.SigHashNone | txscript.SigHashAnyOneCanPay, wantError: true,
+		},
+		{
+			name: "Legacy SIGHASH_SINGLE|ANYONECANPAY", token: NewToken("BTC"), rawTx: btcRawTx, utxos: btcSelectedUtxos,
+			hashType: txscript.SigHashSingle | txscript.SigHashAnyOneCanPay, wantError: true,
+		},
+		{name: "Taproot SIGHASH_ALL", token: NewToken("BTC"), rawTx: btcPsbt, hashType: txscript.SigHashAll},
+		{name: "Taproot SIGHASH_NONE", token: NewToken("BTC"), rawTx: btcPsbt, hashType: txscript.SigHashNone, wantError: true},
+		{name: "Taproot SIGHASH_SINGLE", token: NewToken("BTC"), rawTx: btcPsbt, hashType: txscript.SigHashSingle, wantError: true},
+		{
+			name: "Taproot SIGHASH_ALL|ANYONECANPAY", token: NewToken("BTC"), rawTx: btcPsbt,
+			hashType: txscript.SigHashAll | txscript.SigHashAnyOneCanPay, wantError: true,
+		},
+		{
+			name: "Fork id SIGHASH_ALL|FORKID", token: NewBitcoinCashToken("BCH"), rawTx: btcRawTx, utxos: bchUtxos,
+			hashType: txscript.SigHashAll | sigHashForkID,
+		},
+		{
+			name: "Fork id SIGHASH_NONE|FORKID", token: NewBitcoinCashToken("BCH"), rawTx: btcRawTx, utxos: bchUtxos,
+			hashType: txscript.SigHashNone | sigHashForkID, wantError: true,
+		},
+		{
+			name: "Fork id SIGHASH_SINGLE|FORKID", token: NewBitcoinCashToken("BCH"), rawTx: btcRawTx, utxos: bchUtxos,
+			hashType: txscript.SigHashSingle | sigHashForkID, wantError: true,
+		},
+		{
+			name: "Fork id SIGHASH_ALL|FORKID|ANYONECANPAY", token: NewBitcoinCashToken("BCH"), rawTx: btcRawTx, utxos: bchUtxos,
+			hashType: txscript.SigHashAll | sigHashForkID | txscript.SigHashAnyOneCanPay, wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := buildTestTransaction(t, tt.token, tt.rawTx, tt.utxos)
+			tx.inputs[0].sigHashType = tt.hashType
+
+			_, err := tx.GetHashes()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTransaction_GetSourceAddresses(t *testing.T) {
+	tx := buildTestTransaction(t, NewToken("BTC"), btcRawTx, btcSelectedUtxos)
+	addresses, err := tx.GetSourceAddresses()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1HkrFxLyNoQydvW889WmubyRHycE4bvw1Y", "bc1qr583w2swedy2acd7rung055k8t3n7udp7vyzyg"}, addresses)
+}
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		utxos         []coboWaaS2.TransactionSelectedUtxo
+		sources       []string
+		wantTransfers []token_adapter.Transfer
+	}{
+		{
+			name:  "All outputs without source addresses",
+			rawTx: btcRawTx,
+			utxos: btcSelectedUtxos,
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "BTC", To: "1Cu32FVupVCgHkMMRJdYJugxwo2Aprgk7H", Amount: big.NewInt(112340000)},
+				{Chain: "BTC", To: "16TZ8J6Q5iZKBWizWzFAYnrsaox5Z5aBRV", Amount: big.NewInt(223450000)},
+			},
+		},
+		{
+			name:    "Change output to a source address",
+			rawTx:   btcRawTx,
+			utxos:   btcSelectedUtxos,
+			sources: []string{"16TZ8J6Q5iZKBWizWzFAYnrsaox5Z5aBRV"},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "BTC", To: "1Cu32FVupVCgHkMMRJdYJugxwo2Aprgk7H", Amount: big.NewInt(112340000)},
+			},
+		},
+		{
+			name:    "Taproot change output",
+			rawTx:   btcPsbt,
+			sources: []string{"bc1ptswcmyg0l5rktc2xvncauqtkgq6amgg7vgwgvpv7ern3s27u34nq4udgm0"},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "BTC", To: "bc1qr583w2swedy2acd7rung055k8t3n7udp7vyzyg", Amount: big.NewInt(50000)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := buildTestTransaction(t, NewToken("BTC"), tt.rawTx, tt.utxos, tt.sources...)
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, token_adapter.DestinationAddresses(tt.wantTransfers), addresses)
+		})
+	}
+}
+
+func TestTransaction_GetFee(t *testing.T) {
+	tx := buildTestTransaction(t, NewToken("BTC"), btcRawTx, btcSelectedUtxos)
+	fee, err := tx.GetFee()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(889210000), fee.FeeLimit)
+
+	tx = buildTestTransaction(t, NewToken("BTC"), btcPsbt, nil)
+	fee, err = tx.GetFee()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(10000), fee.FeeLimit)
+}
+
+func TestTransaction_GetHashes_Forks(t *testing.T) {
+	newUtxos := func(address0, address1 string) []coboWaaS2.TransactionSelectedUtxo {
+		utxos := []coboWaaS2.TransactionSelectedUtxo{btcSelectedUtxos[0], btcSelectedUtxos[1]}
+		utxos[0].Address = coboWaaS2.PtrString(address0)
+		utxos[1].Address = coboWaaS2.PtrString(address1)
+		return utxos
+	}
+
+	tests := []struct {
+		name          string
+		token         token_adapter.Token
+		utxos         []coboWaaS2.TransactionSelectedUtxo
+		wantHashes    []string
+		wantAddresses []string
+	}{
+		{
+			name:  "LTC legacy and BIP143 inputs",
+			token: NewLitecoinToken("LTC"),
+			utxos: newUtxos("LbyoXAeoTTf2tjCHJHW5Bd3BWByWCvCUpE", "ltc1qr583w2swedy2acd7rung055k8t3n7udp6s7xuc"),
+			wantHashes: []string{
+				"0x309e93b10b8faba95cb4aa73d554a6a0f8462b50c01032c9028ffd55c4928278",
+				"0xc37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670",
+			},
+			wantAddresses: []string{"LX7zHToju9SjYZ3WbScqavkjA1PStdjwEX", "LQgWPWQEANoNSKR9h8ETpovdo2KMgCn2bw"},
+		},
+		{
+			name:  "DOGE legacy inputs",
+			token: NewDogecoinToken("DOGE"),
+			utxos: newUtxos("DMtwoDHcgDKGAvgirjWLTN92B7LXQJvktg", "D7nkDKujExBEuU8myVVtw1kWgV2haBtQcR"),
+			wantHashes: []string{
+				"0x309e93b10b8faba95cb4aa73d554a6a0f8462b50c01032c9028ffd55c4928278",
+				"0xc46030820cbc48402a47cc5b5d3d41648f4e3a711f56b804d601d09dc112a6a4",
+			},
+			wantAddresses: []string{"DH38ZWSZ7u6xpkXx9td6rfrZpvkU9ssuJf", "DAbefZ33P8TbiWubFaEj6Z2UTwgNsksfKf"},
+		},
+		{
+			name:  "BCH fork id inputs",
+			token: NewBitcoinCashToken("BCH"),
+			utxos: newUtxos("bitcoincash:qzmu6prtd4fz50tpm094ydwqa8xfwfj52uec5n0exu", "qqws79e2pm953thphc0jdp7jjcawx0m35yh5n25x03"),
+			wantHashes: []string{
+				"0xe41c35b842a8b01942b73478bc06003cd4e0c2526c88c325e5c1dd515c931532",
+				"0x467f411d178762db122a6aced76370a1c8324355bf0796502bf82eeaeda86a35",
+			},
+			wantAddresses: []string{
+				"bitcoincash:qzpgpvma7dudhx0kd7zujknc8fm2c7ndty6enk84u6",
+				"bitcoincash:qqaauskmaelym0n2yxed2r8z7qt8l25pty7etduawh",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := buildTestTransaction(t, tt.token, btcRawTx, tt.utxos)
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAddresses, addresses)
+		})
+	}
+}
+
+func TestTransaction_GetHashes_WitnessInputWithoutSegWit(t *testing.T) {
+	tx := buildTestTransaction(t, NewDogecoinToken("DOGE"), btcPsbt, nil)
+	_, err := tx.GetHashes()
+	assert.Error(t, err)
+
+	_, err = tx.GetTransfers()
+	assert.Error(t, err)
+}
+
+func TestTransaction_GetTransfers_CashAddrChange(t *testing.T) {
+	utxos := []coboWaaS2.TransactionSelectedUtxo{btcSelectedUtxos[0], btcSelectedUtxos[1]}
+	utxos[1].Address = coboWaaS2.PtrString("bitcoincash:qqws79e2pm953thphc0jdp7jjcawx0m35yh5n25x03")
+
+	// change to the legacy format of the source address
+	tx := buildTestTransaction(t, NewBitcoinCashToken("BCH"), btcRawTx, utxos, "16TZ8J6Q5iZKBWizWzFAYnrsaox5Z5aBRV")
+	transfers, err := tx.GetTransfers()
+	assert.NoError(t, err)
+	assert.Equal(t, []token_adapter.Transfer{
+		{Chain: "BCH", To: "bitcoincash:qzpgpvma7dudhx0kd7zujknc8fm2c7ndty6enk84u6", Amount: big.NewInt(112340000)},
+	}, transfers)
+}
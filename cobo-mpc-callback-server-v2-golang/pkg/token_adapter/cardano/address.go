@@ -0,0 +1,48 @@
+package cardano
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+const (
+	mainnetNetworkID = 1
+	addressHRP       = "addr"
+
+	// a base address has a payment and a stake credential, an enterprise address a payment credential only
+	baseAddressLength       = 57
+	enterpriseAddressLength = 29
+)
+
+// encodeAddress returns the bech32 of a Shelley payment address of the mainnet, e.g. addr1...
+// A Byron, pointer or reward address is rejected.
+func encodeAddress(address []byte) (string, error) {
+	if len(address) == 0 {
+		return "", fmt.Errorf("address is empty")
+	}
+
+	header := address[0]
+	addressType, networkID := header>>4, header&0x0f
+	switch addressType {
+	case 0, 1, 2, 3:
+		if len(address) != baseAddressLength {
+			return "", fmt.Errorf("invalid base address length %d", len(address))
+		}
+	case 6, 7:
+		if len(address) != enterpriseAddressLength {
+			return "", fmt.Errorf("invalid enterprise address length %d", len(address))
+		}
+	default:
+		return "", fmt.Errorf("unsupported address type %d", addressType)
+	}
+	if networkID != mainnetNetworkID {
+		return "", fmt.Errorf("address network id %d is not the mainnet", networkID)
+	}
+
+	data, err := bech32.ConvertBits(address, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(addressHRP, data)
+}
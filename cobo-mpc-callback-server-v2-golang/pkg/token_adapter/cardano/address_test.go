@@ -0,0 +1,73 @@
+package cardano
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// credentials of the CIP-19 test vectors
+var (
+	paymentKeyHash = common.FromHex("9493315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e")
+	stakeKeyHash   = common.FromHex("337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251")
+)
+
+func testAddress(header byte, credentials ...[]byte) []byte {
+	address := []byte{header}
+	for _, credential := range credentials {
+		address = append(address, credential...)
+	}
+	return address
+}
+
+func TestEncodeAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		address   []byte
+		want      string
+		wantError bool
+	}{
+		{
+			name:    "Base address",
+			address: testAddress(0x01, paymentKeyHash, stakeKeyHash),
+			want:    "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x",
+		},
+		{
+			name:    "Enterprise address",
+			address: testAddress(0x61, paymentKeyHash),
+			want:    "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8",
+		},
+		{
+			name:      "Testnet address",
+			address:   testAddress(0x60, paymentKeyHash),
+			wantError: true,
+		},
+		{
+			name:      "Reward address",
+			address:   testAddress(0xe1, stakeKeyHash),
+			wantError: true,
+		},
+		{
+			name:      "Invalid length",
+			address:   testAddress(0x01, paymentKeyHash),
+			wantError: true,
+		},
+		{
+			name:      "Empty",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := encodeAddress(tt.address)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, address)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package cardano
+
+import (
+	"fmt"
+)
+
+// Transaction body keys
+const (
+	bodyInputs            = 0
+	bodyOutputs           = 1
+	bodyFee               = 2
+	bodyTTL               = 3
+	bodyAuxiliaryDataHash = 7
+	bodyValidityStart     = 8
+	bodyNetworkID         = 15
+
+	// output keys of a post-Alonzo output map
+	outputAddress = 0
+	outputValue   = 1
+)
+
+// bodyKeyNames names the transaction body keys that are rejected
+var bodyKeyNames = map[uint64]string{
+	4:  "certificates",
+	5:  "withdrawals",
+	6:  "update",
+	9:  "mint",
+	11: "script data hash",
+	13: "collateral inputs",
+	14: "required signers",
+	16: "collateral return",
+	17: "total collateral",
+	18: "reference inputs",
+	19: "voting procedures",
+	20: "proposal procedures",
+	21: "current treasury value",
+	22: "donation",
+}
+
+// TransactionBody is a decoded Cardano transaction body of ADA payments
+type TransactionBody struct {
+	// raw is the CBOR of the body as serialized, its blake2b-256 is the transaction id signed by the witnesses
+	raw     []byte
+	inputs  []Input
+	outputs []Output
+	// fee in lovelace
+	fee uint64
+	// networkID is nil if the body has no network id
+	networkID *uint64
+}
+
+// Input is an output spent by the transaction
+type Input struct {
+	txID  []byte
+	index uint64
+}
+
+// Output is a transaction output paying lovelace to an address
+type Output struct {
+	address []byte
+	amount  uint64
+}
+
+// ParseTransaction decodes a transaction body, or the body of a transaction array of the body, the witness set,
+// the validity flag and the auxiliary data
+func ParseTransaction(rawTx []byte) (*TransactionBody, error) {
+	d := &decoder{data: rawTx}
+	major, err := d.peekMajor()
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	switch major {
+	case majorMap:
+		if raw, err = d.skip(); err != nil {
+			return nil, fmt.Errorf("decode transaction body: %w", err)
+		}
+	case majorArray:
+		length, err := d.readArrayLength()
+		if err != nil {
+			return nil, err
+		}
+		if length != 3 && length != 4 {
+			return nil, fmt.Errorf("invalid transaction array length %d", length)
+		}
+		if raw, err = d.skip(); err != nil {
+			return nil, fmt.Errorf("decode transaction body: %w", err)
+		}
+		for i := uint64(1); i < length; i++ {
+			if _, err := d.skip(); err != nil {
+				return nil, fmt.Errorf("decode transaction item %d: %w", i, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("transaction is neither a body nor a transaction array")
+	}
+	if d.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after transaction", d.remaining())
+	}
+
+	body, err := parseBody(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode transaction body: %w", err)
+	}
+	return body, nil
+}
+
+// parseBody decodes the keys of a body, a key that may move funds other than by outputs, such as certificates,
+// withdrawals, mint or scripts, is rejected
+func parseBody(raw []byte) (*TransactionBody, error) {
+	d := &decoder{data: raw}
+	entries, err := d.readMapLength()
+	if err != nil {
+		return nil, err
+	}
+
+	body := &TransactionBody{raw: raw}
+	seen := make(map[uint64]bool)
+	for i := uint64(0); i < entries; i++ {
+		key, err := d.readUint()
+		if err != nil {
+			return nil, fmt.Errorf("decode key: %w", err)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate key %d", key)
+		}
+		seen[key] = true
+
+		if err := body.readKey(d, key); err != nil {
+			return nil, fmt.Errorf("decode key %d: %w", key, err)
+		}
+	}
+	if d.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes", d.remaining())
+	}
+
+	for _, required := range []uint64{bodyInputs, bodyOutputs, bodyFee} {
+		if !seen[required] {
+			return nil, fmt.Errorf("body has no key %d", required)
+		}
+	}
+	if len(body.inputs) == 0 || len(body.outputs) == 0 {
+		return nil, fmt.Errorf("body has %d inputs and %d outputs", len(body.inputs), len(body.outputs))
+	}
+	return body, nil
+}
+
+func (b *TransactionBody) readKey(d *decoder, key uint64) (err error) {
+	switch key {
+	case bodyInputs:
+		return b.readInputs(d)
+	case bodyOutputs:
+		count, err := d.readArrayLength()
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < count; i++ {
+			output, err := readOutput(d)
+			if err != nil {
+				return fmt.Errorf("output %d: %w", i, err)
+			}
+			b.outputs = append(b.outputs, output)
+		}
+		return nil
+	case bodyFee:
+		b.fee, err = d.readUint()
+		return err
+	case bodyTTL, bodyValidityStart:
+		_, err = d.readUint()
+		return err
+	case bodyAuxiliaryDataHash:
+		_, err = d.readHash()
+		return err
+	case bodyNetworkID:
+		var networkID uint64
+		if networkID, err = d.readUint(); err == nil {
+			b.networkID = &networkID
+		}
+		return err
+	default:
+		if name, ok := bodyKeyNames[key]; ok {
+			return fmt.Errorf("%v are not supported", name)
+		}
+		return fmt.Errorf("unsupported key")
+	}
+}
+
+// readInputs reads the set of inputs, each the id of a transaction and an output index
+func (b *TransactionBody) readInputs(d *decoder) error {
+	count, err := d.readSetLength()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		length, err := d.readArrayLength()
+		if err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+		if length != 2 {
+			return fmt.Errorf("input %d has %d items", i, length)
+		}
+		var input Input
+		if input.txID, err = d.readHash(); err != nil {
+			return fmt.Errorf("input %d transaction id: %w", i, err)
+		}
+		if input.index, err = d.readUint(); err != nil {
+			return fmt.Errorf("input %d index: %w", i, err)
+		}
+		b.inputs = append(b.inputs, input)
+	}
+	return nil
+}
+
+// readOutput reads a legacy output array or a post-Alonzo output map, an output with a datum or a script reference
+// is rejected
+func readOutput(d *decoder) (Output, error) {
+	var output Output
+	major, err := d.peekMajor()
+	if err != nil {
+		return output, err
+	}
+
+	if major == majorArray {
+		length, err := d.readArrayLength()
+		if err != nil {
+			return output, err
+		}
+		if length != 2 {
+			return output, fmt.Errorf("legacy output with %d items, a datum hash is not supported", length)
+		}
+		if output.address, err = d.readByteString(); err != nil {
+			return output, fmt.Errorf("decode address: %w", err)
+		}
+		output.amount, err = readValue(d)
+		return output, err
+	}
+
+	entries, err := d.readMapLength()
+	if err != nil {
+		return output, err
+	}
+	seen := make(map[uint64]bool)
+	for i := uint64(0); i < entries; i++ {
+		key, err := d.readUint()
+		if err != nil {
+			return output, err
+		}
+		if seen[key] {
+			return output, fmt.Errorf("duplicate output key %d", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case outputAddress:
+			if output.address, err = d.readByteString(); err != nil {
+				return output, fmt.Errorf("decode address: %w", err)
+			}
+		case outputValue:
+			if output.amount, err = readValue(d); err != nil {
+				return output, err
+			}
+		default:
+			return output, fmt.Errorf("output key %d is not supported, a datum or a script reference is rejected", key)
+		}
+	}
+	if !seen[outputAddress] || !seen[outputValue] {
+		return output, fmt.Errorf("output has no address or value")
+	}
+	return output, nil
+}
+
+// readValue reads an amount of lovelace, a multi-asset value is rejected
+func readValue(d *decoder) (uint64, error) {
+	major, err := d.peekMajor()
+	if err != nil {
+		return 0, err
+	}
+	if major != majorUint {
+		return 0, fmt.Errorf("multi-asset value is not supported")
+	}
+	return d.readUint()
+}
@@ -0,0 +1,134 @@
+package cardano
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	baseAddress       = testAddress(0x01, paymentKeyHash, stakeKeyHash)
+	enterpriseAddress = testAddress(0x61, paymentKeyHash)
+)
+
+func testInput(index uint64) []byte {
+	return cborArray(cborBytes(bytes.Repeat([]byte{0xab}, 32)), cborUint(index))
+}
+
+func legacyOutput(address []byte, amount uint64) []byte {
+	return cborArray(cborBytes(address), cborUint(amount))
+}
+
+func mapOutput(address []byte, amount uint64) []byte {
+	return cborMap(cborUint(outputAddress), cborBytes(address), cborUint(outputValue), cborUint(amount))
+}
+
+// encodeBody returns a body spending one input with a fee of 170000 lovelace, followed by the extra keys and values
+func encodeBody(outputs [][]byte, extra ...[]byte) []byte {
+	entries := [][]byte{
+		cborUint(bodyInputs), cborArray(testInput(0)),
+		cborUint(bodyOutputs), cborArray(outputs...),
+		cborUint(bodyFee), cborUint(170000),
+	}
+	return cborMap(append(entries, extra...)...)
+}
+
+func TestParseTransaction(t *testing.T) {
+	outputs := [][]byte{legacyOutput(baseAddress, 1000000), mapOutput(enterpriseAddress, 2000000)}
+	body := encodeBody(outputs, cborUint(bodyTTL), cborUint(150000000))
+	// a witness set with no witness, the validity flag and no auxiliary data
+	transaction := cborArray(body, cborMap(), []byte{0xf5}, []byte{0xf6})
+
+	tests := []struct {
+		name        string
+		rawTx       []byte
+		wantOutputs []Output
+		wantError   bool
+	}{
+		{
+			name:        "Transaction body",
+			rawTx:       body,
+			wantOutputs: []Output{{address: baseAddress, amount: 1000000}, {address: enterpriseAddress, amount: 2000000}},
+		},
+		{
+			name:        "Transaction array",
+			rawTx:       transaction,
+			wantOutputs: []Output{{address: baseAddress, amount: 1000000}, {address: enterpriseAddress, amount: 2000000}},
+		},
+		{
+			name: "Inputs set",
+			rawTx: cborMap(
+				cborUint(bodyInputs), append(cborHeader(majorTag, tagSet), cborArray(testInput(0), testInput(1))...),
+				cborUint(bodyOutputs), cborArray(legacyOutput(baseAddress, 1000000)),
+				cborUint(bodyFee), cborUint(170000),
+				cborUint(bodyNetworkID), cborUint(mainnetNetworkID),
+			),
+			wantOutputs: []Output{{address: baseAddress, amount: 1000000}},
+		},
+		{
+			name:      "Multi-asset output",
+			rawTx:     encodeBody([][]byte{cborArray(cborBytes(baseAddress), cborArray(cborUint(1000000), cborMap()))}),
+			wantError: true,
+		},
+		{
+			name:      "Output with datum hash",
+			rawTx:     encodeBody([][]byte{cborArray(cborBytes(baseAddress), cborUint(1000000), cborBytes(make([]byte, 32)))}),
+			wantError: true,
+		},
+		{
+			name: "Output with inline datum",
+			rawTx: encodeBody([][]byte{cborMap(cborUint(outputAddress), cborBytes(baseAddress), cborUint(outputValue), cborUint(1),
+				cborUint(2), cborArray(cborUint(1), cborBytes([]byte{1})))}),
+			wantError: true,
+		},
+		{
+			name:      "Certificates",
+			rawTx:     encodeBody(outputs, cborUint(4), cborArray()),
+			wantError: true,
+		},
+		{
+			name:      "Withdrawals",
+			rawTx:     encodeBody(outputs, cborUint(5), cborMap(cborBytes(testAddress(0xe1, stakeKeyHash)), cborUint(1))),
+			wantError: true,
+		},
+		{
+			name:      "Duplicate key",
+			rawTx:     encodeBody(outputs, cborUint(bodyFee), cborUint(1)),
+			wantError: true,
+		},
+		{
+			name:      "No fee",
+			rawTx:     cborMap(cborUint(bodyInputs), cborArray(testInput(0)), cborUint(bodyOutputs), cborArray(outputs...)),
+			wantError: true,
+		},
+		{
+			name:      "No output",
+			rawTx:     encodeBody(nil),
+			wantError: true,
+		},
+		{
+			name:      "Transaction array without witness set",
+			rawTx:     cborArray(body, cborMap()),
+			wantError: true,
+		},
+		{
+			name:      "Trailing bytes",
+			rawTx:     append(append([]byte{}, body...), 0x00),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := ParseTransaction(tt.rawTx)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOutputs, tx.outputs)
+			assert.Equal(t, uint64(170000), tx.fee)
+		})
+	}
+}
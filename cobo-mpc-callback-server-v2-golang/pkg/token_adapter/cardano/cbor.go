@@ -0,0 +1,195 @@
+package cardano
+
+import (
+	"fmt"
+)
+
+// CBOR major types
+const (
+	majorUint   = 0
+	majorNegint = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+
+	// tagSet is the tag of a set, e.g. the inputs of a transaction body since the Conway era
+	tagSet = 258
+
+	// maxNesting bounds the depth of the items skipped
+	maxNesting = 64
+)
+
+// decoder reads definite length CBOR items, an indefinite length item is rejected
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.offset
+}
+
+func (d *decoder) readBytes(n uint64) ([]byte, error) {
+	if n > uint64(d.remaining()) {
+		return nil, fmt.Errorf("read %d bytes at offset %d: out of range", n, d.offset)
+	}
+	b := d.data[d.offset : d.offset+int(n)]
+	d.offset += int(n)
+	return b, nil
+}
+
+// readHeader reads the major type and the argument of an item
+func (d *decoder) readHeader() (major byte, arg uint64, err error) {
+	b, err := d.readBytes(1)
+	if err != nil {
+		return 0, 0, err
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		v, err := d.readBytes(1 << (info - 24))
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, c := range v {
+			arg = arg<<8 | uint64(c)
+		}
+		return major, arg, nil
+	case info == 31:
+		return 0, 0, fmt.Errorf("indefinite length item at offset %d is not supported", d.offset-1)
+	default:
+		return 0, 0, fmt.Errorf("invalid additional info %d at offset %d", info, d.offset-1)
+	}
+}
+
+// peekMajor returns the major type of the next item without reading it
+func (d *decoder) peekMajor() (byte, error) {
+	if d.remaining() == 0 {
+		return 0, fmt.Errorf("read item at offset %d: out of range", d.offset)
+	}
+	return d.data[d.offset] >> 5, nil
+}
+
+func (d *decoder) readExpected(expected byte) (uint64, error) {
+	major, arg, err := d.readHeader()
+	if err != nil {
+		return 0, err
+	}
+	if major != expected {
+		return 0, fmt.Errorf("unexpected major type %d, expected %d", major, expected)
+	}
+	return arg, nil
+}
+
+func (d *decoder) readUint() (uint64, error) {
+	return d.readExpected(majorUint)
+}
+
+func (d *decoder) readByteString() ([]byte, error) {
+	length, err := d.readExpected(majorBytes)
+	if err != nil {
+		return nil, err
+	}
+	return d.readBytes(length)
+}
+
+// readArrayLength reads the length of an array, bounded by the bytes left since an item has at least one byte
+func (d *decoder) readArrayLength() (uint64, error) {
+	return d.readLength(majorArray)
+}
+
+// readMapLength reads the number of entries of a map
+func (d *decoder) readMapLength() (uint64, error) {
+	return d.readLength(majorMap)
+}
+
+func (d *decoder) readLength(major byte) (uint64, error) {
+	length, err := d.readExpected(major)
+	if err != nil {
+		return 0, err
+	}
+	if length > uint64(d.remaining()) {
+		return 0, fmt.Errorf("%d items at offset %d: out of range", length, d.offset)
+	}
+	return length, nil
+}
+
+// readSetLength reads the length of an array, optionally tagged as a set
+func (d *decoder) readSetLength() (uint64, error) {
+	major, err := d.peekMajor()
+	if err != nil {
+		return 0, err
+	}
+	if major == majorTag {
+		tag, err := d.readExpected(majorTag)
+		if err != nil {
+			return 0, err
+		}
+		if tag != tagSet {
+			return 0, fmt.Errorf("unexpected tag %d, expected a set", tag)
+		}
+	}
+	return d.readArrayLength()
+}
+
+// skip reads an item and returns its bytes
+func (d *decoder) skip() ([]byte, error) {
+	start := d.offset
+	if err := d.skipItem(0); err != nil {
+		return nil, err
+	}
+	return d.data[start:d.offset], nil
+}
+
+func (d *decoder) skipItem(depth int) error {
+	if depth > maxNesting {
+		return fmt.Errorf("items nested deeper than %d", maxNesting)
+	}
+	major, arg, err := d.readHeader()
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case majorUint, majorNegint, majorSimple:
+		return nil
+	case majorBytes, majorText:
+		_, err := d.readBytes(arg)
+		return err
+	case majorArray, majorMap:
+		// an item has at least one byte
+		if arg > uint64(d.remaining()) {
+			return fmt.Errorf("%d items at offset %d: out of range", arg, d.offset)
+		}
+		items := arg
+		if major == majorMap {
+			items *= 2
+		}
+		for i := uint64(0); i < items; i++ {
+			if err := d.skipItem(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return d.skipItem(depth + 1)
+	}
+}
+
+// readHash reads a byte string of 32 bytes, e.g. a transaction id
+func (d *decoder) readHash() ([]byte, error) {
+	b, err := d.readByteString()
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 32 {
+		return nil, fmt.Errorf("invalid hash length %d", len(b))
+	}
+	return b, nil
+}
@@ -0,0 +1,78 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func cborHeader(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, arg)
+	}
+}
+
+func cborUint(value uint64) []byte {
+	return cborHeader(majorUint, value)
+}
+
+func cborBytes(b []byte) []byte {
+	return append(cborHeader(majorBytes, uint64(len(b))), b...)
+}
+
+func cborArray(items ...[]byte) []byte {
+	return append(cborHeader(majorArray, uint64(len(items))), bytes.Join(items, nil)...)
+}
+
+// cborMap encodes the keys and values in order
+func cborMap(keysAndValues ...[]byte) []byte {
+	return append(cborHeader(majorMap, uint64(len(keysAndValues)/2)), bytes.Join(keysAndValues, nil)...)
+}
+
+func TestDecoder(t *testing.T) {
+	item := cborMap(cborUint(1), cborArray(cborUint(1000000), cborBytes([]byte("ab")), []byte{0xf6}), cborUint(2), cborUint(1<<40))
+	d := &decoder{data: append(item, 0x01)}
+	skipped, err := d.skip()
+	assert.NoError(t, err)
+	assert.Equal(t, item, skipped)
+	value, err := d.readUint()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), value)
+	assert.Equal(t, 0, d.remaining())
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Indefinite length array", data: []byte{0x9f, 0x01, 0xff}},
+		{name: "Truncated byte string", data: []byte{0x43, 0x01}},
+		{name: "Array longer than data", data: cborHeader(majorArray, 1<<62)},
+		{name: "Map longer than data", data: cborHeader(majorMap, 1<<63)},
+		{name: "Reserved additional info", data: []byte{0x1c}},
+		{name: "Deep nesting", data: bytes.Repeat([]byte{0x81}, maxNesting+2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&decoder{data: tt.data}).skip()
+			assert.Error(t, err)
+		})
+	}
+
+	set := append(cborHeader(majorTag, tagSet), cborArray(cborUint(1))...)
+	length, err := (&decoder{data: set}).readSetLength()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), length)
+	_, err = (&decoder{data: append(cborHeader(majorTag, 24), cborArray()...)}).readSetLength()
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package cardano
+
+import (
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Token struct {
+	tokenID string
+}
+
+// NewToken returns the ADA token of the Cardano mainnet
+func NewToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+	}
+}
+
+func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	preTxData, err := prepareBuildTransactionData(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
+	}
+
+	body, err := ParseTransaction(preTxData.rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("prepare cardano transaction error: %w", err)
+	}
+	if body.networkID != nil && *body.networkID != mainnetNetworkID {
+		return nil, fmt.Errorf("transaction network id %v is not the mainnet of token %v", *body.networkID, t.tokenID)
+	}
+
+	return &Transaction{tx: body, PrepareTransactionData: preTxData, token: t}, nil
+}
+
+func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
+		return nil, fmt.Errorf("transaction info raw tx is nil")
+	}
+
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
+	if len(rawTxBytes) == 0 {
+		return nil, fmt.Errorf("transaction raw tx is empty")
+	}
+
+	var sourceAddresses []string
+	for _, source := range txInfo.SourceAddresses {
+		sourceAddresses = append(sourceAddresses, source.Address)
+	}
+
+	return &PrepareTransactionData{
+		rawTx:           rawTxBytes,
+		chainID:         txInfo.Transaction.GetChainId(),
+		sourceAddresses: sourceAddresses,
+		selectedUtxos:   txInfo.Transaction.RawTxInfo.SelectedUtxos,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package cardano
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("ADA")
+	adaToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "ADA", adaToken.tokenID)
+}
+
+// cardanoBody is the body of a mainnet transaction spending an output of the CIP-19 enterprise address to pay 5 ADA to
+// the CIP-19 base address, with the change back to the enterprise address, a fee of 170429 lovelace and ttl 150000000
+const cardanoBody = "a400d901028182582029efbc16fc407cd776a146f0ed4d9c6b0907df3b0259b30826b135fff3ebfaed010182825839019493315cd92eb5" +
+	"d8c4304e67b7e16ae36d61d34502694657811a2c8e337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c472511a004c4b40a200581d619493315cd9" +
+	"2eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e011a00e24807021a000299bd031a08f0d180"
+
+func TestToken_BuildTransaction(t *testing.T) {
+	const txID = "0x0545b398660132710c3cc2788d856fd036cfc1089b0a0430776aaf00a6f325ee"
+	payment := []token_adapter.Transfer{{
+		Chain:  defaultChainID,
+		To:     "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x",
+		Amount: big.NewInt(5000000),
+	}}
+
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:          "Transaction body",
+			rawTx:         cardanoBody,
+			wantHashes:    []string{txID},
+			wantTransfers: payment,
+		},
+		{
+			// the body, an empty witness set, the validity flag and no auxiliary data
+			name:          "Transaction array",
+			rawTx:         "84" + cardanoBody + "a0f5f6",
+			wantHashes:    []string{txID},
+			wantTransfers: payment,
+		},
+		{
+			name:      "Testnet network id",
+			rawTx:     "a5" + cardanoBody[2:] + "0f00",
+			wantError: true,
+		},
+		{
+			name:      "Not a transaction",
+			rawTx:     "01",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewToken("ADA").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+				SourceAddresses: []coboWaaS2.AddressInfo{{Address: "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"}},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
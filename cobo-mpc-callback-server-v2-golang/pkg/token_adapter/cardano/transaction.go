@@ -0,0 +1,128 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/utils"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"golang.org/x/crypto/blake2b"
+)
+
+const defaultChainID = "ADA"
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx *TransactionBody
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx           []byte
+	chainID         string
+	sourceAddresses []string
+	// selectedUtxos are the UTXOs Cobo selected for the inputs, with the addresses the body does not carry
+	selectedUtxos []coboWaaS2.TransactionSelectedUtxo
+}
+
+// GetHashes implements Transaction interface for Cardano, the hash is the blake2b-256 of the transaction body
+func (t *Transaction) GetHashes() ([]string, error) {
+	if t.tx == nil || len(t.tx.raw) == 0 {
+		return nil, fmt.Errorf("transaction body is empty")
+	}
+
+	hash := blake2b.Sum256(t.tx.raw)
+	return []string{"0x" + hex.EncodeToString(hash[:])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for Cardano
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Cardano. The body does not mark change, an output to the bech32
+// of a source address is taken as the change back to the wallet and skipped.
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	change := make(map[string]bool)
+	for _, source := range t.sourceAddresses {
+		change[source] = true
+	}
+
+	var transfers []token_adapter.Transfer
+	for idx, output := range t.tx.outputs {
+		address, err := encodeAddress(output.address)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %w", idx, err)
+		}
+		if change[address] {
+			continue
+		}
+
+		transfers = append(transfers, token_adapter.Transfer{
+			Chain:  chainID,
+			To:     address,
+			Amount: new(big.Int).SetUint64(output.amount),
+		})
+	}
+
+	return transfers, nil
+}
+
+// GetSourceAddresses implements SourceTransaction interface for Cardano. An input only references the spent output
+// by transaction id and index, so its owner is the address of the selected UTXO, an input not selected is rejected.
+func (t *Transaction) GetSourceAddresses() ([]string, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	addresses := make([]string, 0, len(t.tx.inputs))
+	for idx, input := range t.tx.inputs {
+		utxo := t.findUtxo(input)
+		if utxo == nil || utxo.GetAddress() == "" {
+			return nil, fmt.Errorf("input %d %x#%d is not a selected utxo", idx, input.txID, input.index)
+		}
+		addresses = append(addresses, utxo.GetAddress())
+	}
+	return addresses, nil
+}
+
+func (t *Transaction) findUtxo(input Input) *coboWaaS2.TransactionSelectedUtxo {
+	for idx := range t.selectedUtxos {
+		utxo := &t.selectedUtxos[idx]
+		if utxo.VoutN == nil || *utxo.VoutN < 0 || uint64(*utxo.VoutN) != input.index {
+			continue
+		}
+		if strings.EqualFold(utils.Trim0xPrefix(utxo.GetTxHash()), hex.EncodeToString(input.txID)) {
+			return utxo
+		}
+	}
+	return nil
+}
+
+// GetFee implements FeeTransaction interface for Cardano, the fee limit is the fee in lovelace
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	return &token_adapter.Fee{FeeLimit: new(big.Int).SetUint64(t.tx.fee)}, nil
+}
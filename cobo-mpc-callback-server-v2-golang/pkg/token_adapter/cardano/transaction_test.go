@@ -0,0 +1,122 @@
+package cardano
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	base, err := encodeAddress(baseAddress)
+	assert.NoError(t, err)
+	enterprise, err := encodeAddress(enterpriseAddress)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		outputs         [][]byte
+		sourceAddresses []string
+		wantTransfers   []token_adapter.Transfer
+		wantError       bool
+	}{
+		{
+			name:    "All outputs",
+			outputs: [][]byte{legacyOutput(baseAddress, 1000000), mapOutput(enterpriseAddress, 2000000)},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, To: base, Amount: big.NewInt(1000000)},
+				{Chain: defaultChainID, To: enterprise, Amount: big.NewInt(2000000)},
+			},
+		},
+		{
+			name:            "Change output",
+			outputs:         [][]byte{legacyOutput(baseAddress, 1000000), mapOutput(enterpriseAddress, 2000000)},
+			sourceAddresses: []string{enterprise},
+			wantTransfers:   []token_adapter.Transfer{{Chain: defaultChainID, To: base, Amount: big.NewInt(1000000)}},
+		},
+		{
+			name:      "Testnet output",
+			outputs:   [][]byte{legacyOutput(testAddress(0x60, paymentKeyHash), 1000000)},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := ParseTransaction(encodeBody(tt.outputs))
+			assert.NoError(t, err)
+			tx := &Transaction{
+				tx:                     body,
+				PrepareTransactionData: &PrepareTransactionData{sourceAddresses: tt.sourceAddresses},
+				token:                  &Token{tokenID: "ADA"},
+			}
+
+			transfers, err := tx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			fee, err := tx.GetFee()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Fee{FeeLimit: big.NewInt(170000)}, fee)
+		})
+	}
+}
+
+func TestTransaction_GetSourceAddresses(t *testing.T) {
+	enterprise, err := encodeAddress(enterpriseAddress)
+	assert.NoError(t, err)
+	body, err := ParseTransaction(encodeBody([][]byte{legacyOutput(baseAddress, 1000000)}))
+	assert.NoError(t, err)
+
+	txHash := strings.Repeat("ab", 32)
+	tests := []struct {
+		name          string
+		utxos         []coboWaaS2.TransactionSelectedUtxo
+		wantAddresses []string
+		wantError     bool
+	}{
+		{
+			name: "Selected utxo",
+			utxos: []coboWaaS2.TransactionSelectedUtxo{
+				{TxHash: coboWaaS2.PtrString(txHash), VoutN: coboWaaS2.PtrInt32(0), Address: coboWaaS2.PtrString(enterprise)},
+			},
+			wantAddresses: []string{enterprise},
+		},
+		{
+			name: "Utxo of another index",
+			utxos: []coboWaaS2.TransactionSelectedUtxo{
+				{TxHash: coboWaaS2.PtrString(txHash), VoutN: coboWaaS2.PtrInt32(1), Address: coboWaaS2.PtrString(enterprise)},
+			},
+			wantError: true,
+		},
+		{
+			name:      "No selected utxo",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &Transaction{
+				tx:                     body,
+				PrepareTransactionData: &PrepareTransactionData{selectedUtxos: tt.utxos},
+				token:                  &Token{tokenID: "ADA"},
+			}
+
+			addresses, err := tx.GetSourceAddresses()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAddresses, addresses)
+		})
+	}
+}
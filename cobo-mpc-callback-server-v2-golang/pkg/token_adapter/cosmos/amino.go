@@ -0,0 +1,183 @@
+package cosmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// aminoSignDoc is the StdSignDoc of SIGN_MODE_LEGACY_AMINO_JSON
+type aminoSignDoc struct {
+	AccountNumber string     `json:"account_number"`
+	ChainID       string     `json:"chain_id"`
+	Fee           aminoFee   `json:"fee"`
+	Memo          string     `json:"memo"`
+	Msgs          []aminoMsg `json:"msgs"`
+	Sequence      string     `json:"sequence"`
+	TimeoutHeight string     `json:"timeout_height,omitempty"`
+}
+
+type aminoFee struct {
+	Amount  []aminoCoin `json:"amount"`
+	Gas     string      `json:"gas"`
+	Payer   string      `json:"payer,omitempty"`
+	Granter string      `json:"granter,omitempty"`
+}
+
+type aminoCoin struct {
+	Amount string `json:"amount"`
+	Denom  string `json:"denom"`
+}
+
+type aminoMsg struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type aminoMsgSend struct {
+	Amount      []aminoCoin `json:"amount"`
+	FromAddress string      `json:"from_address"`
+	ToAddress   string      `json:"to_address"`
+}
+
+type aminoMsgTransfer struct {
+	Memo          string `json:"memo,omitempty"`
+	Receiver      string `json:"receiver"`
+	Sender        string `json:"sender"`
+	SourceChannel string `json:"source_channel"`
+	SourcePort    string `json:"source_port"`
+	TimeoutHeight struct {
+		RevisionHeight string `json:"revision_height,omitempty"`
+		RevisionNumber string `json:"revision_number,omitempty"`
+	} `json:"timeout_height"`
+	TimeoutTimestamp string    `json:"timeout_timestamp,omitempty"`
+	Token            aminoCoin `json:"token"`
+}
+
+type aminoMsgDelegate struct {
+	Amount           aminoCoin `json:"amount"`
+	DelegatorAddress string    `json:"delegator_address"`
+	ValidatorAddress string    `json:"validator_address"`
+}
+
+// decodeAminoSignDoc decodes a StdSignDoc.
+// The sign bytes must be the sorted and compact JSON signed by the chain, so no field is hidden by a duplicate key.
+func decodeAminoSignDoc(signBytes []byte) (*signDoc, error) {
+	if err := checkCanonicalJSON(signBytes); err != nil {
+		return nil, err
+	}
+
+	var amino aminoSignDoc
+	if err := decodeStrictJSON(signBytes, &amino); err != nil {
+		return nil, fmt.Errorf("decode amino sign doc error: %w", err)
+	}
+
+	doc := &signDoc{chainID: amino.ChainID, memo: amino.Memo}
+	var err error
+	if doc.accountNumber, err = strconv.ParseUint(amino.AccountNumber, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid account number %q", amino.AccountNumber)
+	}
+	if doc.fee.gasLimit, err = strconv.ParseUint(amino.Fee.Gas, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid fee gas %q", amino.Fee.Gas)
+	}
+	if doc.fee.amount, err = aminoCoins(amino.Fee.Amount); err != nil {
+		return nil, fmt.Errorf("invalid fee amount: %w", err)
+	}
+	doc.fee.payer, doc.fee.granter = amino.Fee.Payer, amino.Fee.Granter
+
+	for idx, m := range amino.Msgs {
+		decoded, err := decodeAminoMsg(m)
+		if err != nil {
+			return nil, fmt.Errorf("decode amino message index %v: %w", idx, err)
+		}
+		doc.msgs = append(doc.msgs, decoded)
+	}
+	return doc, nil
+}
+
+func decodeAminoMsg(m aminoMsg) (msg, error) {
+	switch m.Type {
+	case msgSendAminoName:
+		var value aminoMsgSend
+		if err := decodeStrictJSON(m.Value, &value); err != nil {
+			return nil, fmt.Errorf("decode MsgSend: %w", err)
+		}
+		amount, err := aminoCoins(value.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("decode MsgSend: %w", err)
+		}
+		return &msgSend{fromAddress: value.FromAddress, toAddress: value.ToAddress, amount: amount}, nil
+	case msgTransferAminoName:
+		var value aminoMsgTransfer
+		if err := decodeStrictJSON(m.Value, &value); err != nil {
+			return nil, fmt.Errorf("decode MsgTransfer: %w", err)
+		}
+		token, err := newCoin(value.Token.Denom, value.Token.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("decode MsgTransfer: %w", err)
+		}
+		return &msgTransfer{
+			sourcePort:    value.SourcePort,
+			sourceChannel: value.SourceChannel,
+			token:         token,
+			sender:        value.Sender,
+			receiver:      value.Receiver,
+			memo:          value.Memo,
+		}, nil
+	case msgDelegateAminoName:
+		var value aminoMsgDelegate
+		if err := decodeStrictJSON(m.Value, &value); err != nil {
+			return nil, fmt.Errorf("decode MsgDelegate: %w", err)
+		}
+		amount, err := newCoin(value.Amount.Denom, value.Amount.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("decode MsgDelegate: %w", err)
+		}
+		return &msgDelegate{delegatorAddress: value.DelegatorAddress, validatorAddress: value.ValidatorAddress, amount: amount}, nil
+	default:
+		return nil, fmt.Errorf("unsupported message %q", m.Type)
+	}
+}
+
+func aminoCoins(coins []aminoCoin) ([]coin, error) {
+	var decoded []coin
+	for _, c := range coins {
+		value, err := newCoin(c.Denom, c.Amount)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, value)
+	}
+	return decoded, nil
+}
+
+// checkCanonicalJSON requires the JSON to equal its sorted and compact encoding, as the sign bytes of the SDK
+func checkCanonicalJSON(data []byte) error {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("invalid amino sign doc json: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid amino sign doc json: trailing data")
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal amino sign doc error: %w", err)
+	}
+	if !bytes.Equal(canonical, data) {
+		return fmt.Errorf("amino sign doc is not sorted compact json")
+	}
+	return nil
+}
+
+// decodeStrictJSON decodes JSON rejecting unknown fields
+func decodeStrictJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,72 @@
+package cosmos
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+)
+
+const (
+	TokenTypeNative = "native"
+	TokenTypeToken  = "token"
+)
+
+// TokenConfig declares a Cosmos SDK token registered with the cosmos adapter at startup
+type TokenConfig struct {
+	TokenID string `mapstructure:"token_id"`
+	// ChainID is the chain id of the SignDoc, a sign doc of another chain is rejected
+	ChainID string `mapstructure:"chain_id"`
+	// Type is native for the staking coin of the chain or token for another coin, e.g. an IBC coin
+	Type string `mapstructure:"type"`
+	// Denom is the bank denom of the coin, e.g. uatom or ibc/<hash>
+	Denom string `mapstructure:"denom"`
+	// FeeDenom is the denom the fee is paid in, the denom when empty
+	FeeDenom string `mapstructure:"fee_denom"`
+	// Bech32Prefix is the account address prefix of the chain, e.g. cosmos
+	Bech32Prefix string `mapstructure:"bech32_prefix"`
+	Decimals     int32  `mapstructure:"decimals"`
+}
+
+// NewTokenCreator returns the creator of a token declared in config
+func NewTokenCreator(cfg TokenConfig) (token_adapter.TokenCreator, error) {
+	if strings.TrimSpace(cfg.TokenID) == "" {
+		return nil, fmt.Errorf("cosmos token id is empty")
+	}
+	if cfg.ChainID == "" {
+		return nil, fmt.Errorf("chain id of cosmos token %v is empty", cfg.TokenID)
+	}
+	if cfg.Denom == "" {
+		return nil, fmt.Errorf("denom of cosmos token %v is empty", cfg.TokenID)
+	}
+	if cfg.Bech32Prefix == "" {
+		return nil, fmt.Errorf("bech32 prefix of cosmos token %v is empty", cfg.TokenID)
+	}
+	if cfg.Decimals < 0 {
+		return nil, fmt.Errorf("decimals of cosmos token %v is negative", cfg.TokenID)
+	}
+
+	var native bool
+	switch strings.ToLower(cfg.Type) {
+	case TokenTypeNative:
+		native = true
+	case TokenTypeToken:
+	default:
+		return nil, fmt.Errorf("unknown type %q of cosmos token %v", cfg.Type, cfg.TokenID)
+	}
+
+	feeDenom := cfg.FeeDenom
+	if feeDenom == "" {
+		feeDenom = cfg.Denom
+	}
+	return func(tokenID string) token_adapter.Token {
+		return &Token{
+			tokenID:      tokenID,
+			chainID:      cfg.ChainID,
+			native:       native,
+			denom:        cfg.Denom,
+			feeDenom:     feeDenom,
+			bech32Prefix: cfg.Bech32Prefix,
+		}
+	}, nil
+}
@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTokenCreator(t *testing.T) {
+	valid := TokenConfig{
+		TokenID:      "OSMO_USDC",
+		ChainID:      "osmosis-1",
+		Type:         TokenTypeToken,
+		Denom:        "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4",
+		FeeDenom:     "uosmo",
+		Bech32Prefix: "osmo",
+		Decimals:     6,
+	}
+
+	tests := []struct {
+		name      string
+		update    func(cfg *TokenConfig)
+		wantError bool
+	}{
+		{name: "Valid token", update: func(cfg *TokenConfig) {}},
+		{name: "Valid native token", update: func(cfg *TokenConfig) { cfg.Type, cfg.Denom, cfg.FeeDenom = TokenTypeNative, "uosmo", "" }},
+		{name: "Empty token id", update: func(cfg *TokenConfig) { cfg.TokenID = " " }, wantError: true},
+		{name: "Empty chain id", update: func(cfg *TokenConfig) { cfg.ChainID = "" }, wantError: true},
+		{name: "Empty denom", update: func(cfg *TokenConfig) { cfg.Denom = "" }, wantError: true},
+		{name: "Empty bech32 prefix", update: func(cfg *TokenConfig) { cfg.Bech32Prefix = "" }, wantError: true},
+		{name: "Negative decimals", update: func(cfg *TokenConfig) { cfg.Decimals = -1 }, wantError: true},
+		{name: "Unknown type", update: func(cfg *TokenConfig) { cfg.Type = "cw20" }, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.update(&cfg)
+			creator, err := NewTokenCreator(cfg)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			token, ok := creator(cfg.TokenID).(*Token)
+			assert.True(t, ok)
+			assert.Equal(t, cfg.ChainID, token.chainID)
+			assert.Equal(t, cfg.Denom, token.denom)
+			assert.Equal(t, cfg.Type == TokenTypeNative, token.native)
+			if cfg.FeeDenom == "" {
+				assert.Equal(t, cfg.Denom, token.feeDenom)
+			} else {
+				assert.Equal(t, cfg.FeeDenom, token.feeDenom)
+			}
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package cosmos
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Type urls of the protobuf messages and names of the amino JSON messages
+const (
+	msgSendTypeURL     = "/cosmos.bank.v1beta1.MsgSend"
+	msgTransferTypeURL = "/ibc.applications.transfer.v1.MsgTransfer"
+	msgDelegateTypeURL = "/cosmos.staking.v1beta1.MsgDelegate"
+
+	msgSendAminoName     = "cosmos-sdk/MsgSend"
+	msgTransferAminoName = "cosmos-sdk/MsgTransfer"
+	msgDelegateAminoName = "cosmos-sdk/MsgDelegate"
+)
+
+// signDoc is the content of a protobuf SignDoc or an amino JSON StdSignDoc
+type signDoc struct {
+	chainID       string
+	accountNumber uint64
+	memo          string
+	msgs          []msg
+	fee           fee
+}
+
+type fee struct {
+	amount   []coin
+	gasLimit uint64
+	payer    string
+	granter  string
+}
+
+type coin struct {
+	denom  string
+	amount *big.Int
+}
+
+// msg is one of msgSend, msgTransfer and msgDelegate
+type msg interface {
+	typeURL() string
+}
+
+type msgSend struct {
+	fromAddress string
+	toAddress   string
+	amount      []coin
+}
+
+type msgTransfer struct {
+	sourcePort    string
+	sourceChannel string
+	token         coin
+	sender        string
+	receiver      string
+	memo          string
+}
+
+type msgDelegate struct {
+	delegatorAddress string
+	validatorAddress string
+	amount           coin
+}
+
+func (m *msgSend) typeURL() string     { return msgSendTypeURL }
+func (m *msgTransfer) typeURL() string { return msgTransferTypeURL }
+func (m *msgDelegate) typeURL() string { return msgDelegateTypeURL }
+
+// decodeSignDoc decodes the sign bytes of an amino JSON StdSignDoc, which is a JSON object, or of a protobuf SignDoc
+func decodeSignDoc(signBytes []byte) (*signDoc, error) {
+	if len(signBytes) > 0 && signBytes[0] == '{' {
+		return decodeAminoSignDoc(signBytes)
+	}
+	return decodeProtoSignDoc(signBytes)
+}
+
+// protoField is a field of a protobuf message, the value is in varint or bytes by the wire type
+type protoField struct {
+	num    protowire.Number
+	typ    protowire.Type
+	varint uint64
+	bytes  []byte
+}
+
+// walkProto visits the fields of a protobuf message in order.
+// Only varint and length-delimited fields are supported, and a field other than the repeated ones must appear once.
+func walkProto(b []byte, visit func(field protoField) error, repeated ...protowire.Number) error {
+	seen := make(map[protowire.Number]bool)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("invalid field tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		field := protoField{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			return fmt.Errorf("unsupported wire type %v of field %v", typ, num)
+		}
+		if n < 0 {
+			return fmt.Errorf("invalid field %v: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if seen[num] && !slices.Contains(repeated, num) {
+			return fmt.Errorf("duplicate field %v", num)
+		}
+		seen[num] = true
+
+		if err := visit(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f protoField) uint64Value() (uint64, error) {
+	if f.typ != protowire.VarintType {
+		return 0, fmt.Errorf("field %v is not a varint", f.num)
+	}
+	return f.varint, nil
+}
+
+func (f protoField) bytesValue() ([]byte, error) {
+	if f.typ != protowire.BytesType {
+		return nil, fmt.Errorf("field %v is not length-delimited", f.num)
+	}
+	return f.bytes, nil
+}
+
+func (f protoField) stringValue() (string, error) {
+	b, err := f.bytesValue()
+	return string(b), err
+}
+
+// decodeProtoSignDoc decodes a SignDoc of SIGN_MODE_DIRECT and its TxBody and AuthInfo
+func decodeProtoSignDoc(signBytes []byte) (*signDoc, error) {
+	doc := &signDoc{}
+	var bodyBytes, authInfoBytes []byte
+	err := walkProto(signBytes, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			bodyBytes, err = f.bytesValue()
+		case 2:
+			authInfoBytes, err = f.bytesValue()
+		case 3:
+			doc.chainID, err = f.stringValue()
+		case 4:
+			doc.accountNumber, err = f.uint64Value()
+		default:
+			err = fmt.Errorf("unknown field %v", f.num)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode sign doc error: %w", err)
+	}
+
+	if err := decodeTxBody(bodyBytes, doc); err != nil {
+		return nil, fmt.Errorf("decode tx body error: %w", err)
+	}
+	if err := decodeAuthInfo(authInfoBytes, doc); err != nil {
+		return nil, fmt.Errorf("decode auth info error: %w", err)
+	}
+	return doc, nil
+}
+
+// decodeTxBody decodes the messages and memo of a TxBody.
+// Critical extension options change how the transaction is handled, so they are rejected.
+func decodeTxBody(b []byte, doc *signDoc) error {
+	return walkProto(b, func(f protoField) error {
+		switch f.num {
+		case 1:
+			value, err := f.bytesValue()
+			if err != nil {
+				return err
+			}
+			m, err := decodeAny(value)
+			if err != nil {
+				return fmt.Errorf("decode message index %v: %w", len(doc.msgs), err)
+			}
+			doc.msgs = append(doc.msgs, m)
+			return nil
+		case 2:
+			var err error
+			doc.memo, err = f.stringValue()
+			return err
+		case 3, 4, 5:
+			// timeout height, unordered and timeout timestamp move no value
+			return nil
+		case 1023:
+			return fmt.Errorf("extension options are not supported")
+		case 2047:
+			// non-critical extension options can be ignored
+			return nil
+		default:
+			return fmt.Errorf("unknown field %v", f.num)
+		}
+	}, 1, 1023, 2047)
+}
+
+// decodeAuthInfo decodes the fee of an AuthInfo, the signer infos are not decoded
+func decodeAuthInfo(b []byte, doc *signDoc) error {
+	return walkProto(b, func(f protoField) error {
+		switch f.num {
+		case 1:
+			return nil
+		case 2:
+			value, err := f.bytesValue()
+			if err != nil {
+				return err
+			}
+			return decodeFee(value, &doc.fee)
+		default:
+			return fmt.Errorf("unknown field %v", f.num)
+		}
+	}, 1)
+}
+
+func decodeFee(b []byte, fee *fee) error {
+	return walkProto(b, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			var value []byte
+			if value, err = f.bytesValue(); err != nil {
+				return err
+			}
+			c, err := decodeCoin(value)
+			if err != nil {
+				return fmt.Errorf("decode fee amount: %w", err)
+			}
+			fee.amount = append(fee.amount, c)
+		case 2:
+			fee.gasLimit, err = f.uint64Value()
+		case 3:
+			fee.payer, err = f.stringValue()
+		case 4:
+			fee.granter, err = f.stringValue()
+		default:
+			err = fmt.Errorf("unknown field %v", f.num)
+		}
+		return err
+	}, 1)
+}
+
+func decodeCoin(b []byte) (coin, error) {
+	var c coin
+	var amount string
+	err := walkProto(b, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			c.denom, err = f.stringValue()
+		case 2:
+			amount, err = f.stringValue()
+		default:
+			err = fmt.Errorf("unknown field %v", f.num)
+		}
+		return err
+	})
+	if err != nil {
+		return coin{}, err
+	}
+	return newCoin(c.denom, amount)
+}
+
+// newCoin parses the integer amount of a coin
+func newCoin(denom, amount string) (coin, error) {
+	if denom == "" {
+		return coin{}, fmt.Errorf("coin denom is empty")
+	}
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok || value.Sign() < 0 {
+		return coin{}, fmt.Errorf("invalid amount %q of coin %v", amount, denom)
+	}
+	return coin{denom: denom, amount: value}, nil
+}
+
+// decodeAny decodes a message packed in an Any by its type url
+func decodeAny(b []byte) (msg, error) {
+	var typeURL string
+	var value []byte
+	err := walkProto(b, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			typeURL, err = f.stringValue()
+		case 2:
+			value, err = f.bytesValue()
+		default:
+			err = fmt.Errorf("unknown field %v", f.num)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch typeURL {
+	case msgSendTypeURL:
+		return decodeMsgSend(value)
+	case msgTransferTypeURL:
+		return decodeMsgTransfer(value)
+	case msgDelegateTypeURL:
+		return decodeMsgDelegate(value)
+	default:
+		return nil, fmt.Errorf("unsupported message %q", typeURL)
+	}
+}
+
+func decodeMsgSend(b []byte) (*msgSend, error) {
+	m := &msgSend{}
+	err := walkProto(b, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			m.fromAddress, err = f.stringValue()
+		case 2:
+			m.toAddress, err = f.stringValue()
+		case 3:
+			var value []byte
+			if value, err = f.bytesValue(); err != nil {
+				return err
+			}
+			c, err := decodeCoin(value)
+			if err != nil {
+				return fmt.Errorf("decode amount: %w", err)
+			}
+			m.amount = append(m.amount, c)
+		default:
+			err = fmt.Errorf("unknown field %v", f.num)
+		}
+		return err
+	}, 3)
+	if err != nil {
+		return nil, fmt.Errorf("decode MsgSend: %w", err)
+	}
+	return m, nil
+}
+
+func decodeMsgTransfer(b []byte) (*msgTransfer, error) {
+	m := &msgTransfer{}
+	err := walkProto(b, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			m.sourcePort, err = f.stringValue()
+		case 2:
+			m.sourceChannel, err = f.stringValue()
+		case 3:
+			var value []byte
+			if value, err = f.bytesValue(); err != nil {
+				return err
+			}
+			if m.token, err = decodeCoin(value); err != nil {
+				return fmt.Errorf("decode token: %w", err)
+			}
+		case 4:
+			m.sender, err = f.stringValue()
+		case 5:
+			m.receiver, err = f.stringValue()
+		case 6, 7:
+			// timeout height and timeout timestamp move no value
+		case 8:
+			m.memo, err = f.stringValue()
+		default:
+			err = fmt.Errorf("unknown field %v", f.num)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode MsgTransfer: %w", err)
+	}
+	if m.token.amount == nil {
+		return nil, fmt.Errorf("decode MsgTransfer: token is empty")
+	}
+	return m, nil
+}
+
+func decodeMsgDelegate(b []byte) (*msgDelegate, error) {
+	m := &msgDelegate{}
+	err := walkProto(b, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			m.delegatorAddress, err = f.stringValue()
+		case 2:
+			m.validatorAddress, err = f.stringValue()
+		case 3:
+			var value []byte
+			if value, err = f.bytesValue(); err != nil {
+				return err
+			}
+			if m.amount, err = decodeCoin(value); err != nil {
+				return fmt.Errorf("decode amount: %w", err)
+			}
+		default:
+			err = fmt.Errorf("unknown field %v", f.num)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode MsgDelegate: %w", err)
+	}
+	if m.amount.amount == nil {
+		return nil, fmt.Errorf("decode MsgDelegate: amount is empty")
+	}
+	return m, nil
+}
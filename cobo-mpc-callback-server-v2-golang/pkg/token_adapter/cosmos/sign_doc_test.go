@@ -0,0 +1,210 @@
+package cosmos
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// testAddress returns a bech32 address of the prefix filled with the seed byte
+func testAddress(t *testing.T, prefix string, seed byte) string {
+	address, err := bech32.EncodeFromBase256(prefix, bytes.Repeat([]byte{seed}, 20))
+	assert.NoError(t, err)
+	return address
+}
+
+func protoBytes(num protowire.Number, value []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(nil, num, protowire.BytesType), value)
+}
+
+func protoVarint(num protowire.Number, value uint64) []byte {
+	return protowire.AppendVarint(protowire.AppendTag(nil, num, protowire.VarintType), value)
+}
+
+func encodeCoin(denom, amount string) []byte {
+	return bytes.Join([][]byte{protoBytes(1, []byte(denom)), protoBytes(2, []byte(amount))}, nil)
+}
+
+func encodeAny(typeURL string, fields ...[]byte) []byte {
+	return bytes.Join([][]byte{protoBytes(1, []byte(typeURL)), protoBytes(2, bytes.Join(fields, nil))}, nil)
+}
+
+func encodeMsgSend(from, to string, coins ...[]byte) []byte {
+	fields := [][]byte{protoBytes(1, []byte(from)), protoBytes(2, []byte(to))}
+	for _, c := range coins {
+		fields = append(fields, protoBytes(3, c))
+	}
+	return encodeAny(msgSendTypeURL, fields...)
+}
+
+func encodeMsgTransfer(sender, receiver, memo string, token []byte) []byte {
+	return encodeAny(msgTransferTypeURL,
+		protoBytes(1, []byte(ibcTransferPort)),
+		protoBytes(2, []byte("channel-141")),
+		protoBytes(3, token),
+		protoBytes(4, []byte(sender)),
+		protoBytes(5, []byte(receiver)),
+		protoBytes(6, protoVarint(2, 1000)),
+		protoVarint(7, 1700000000000000000),
+		protoBytes(8, []byte(memo)),
+	)
+}
+
+func encodeMsgDelegate(delegator, validator string, amount []byte) []byte {
+	return encodeAny(msgDelegateTypeURL,
+		protoBytes(1, []byte(delegator)),
+		protoBytes(2, []byte(validator)),
+		protoBytes(3, amount),
+	)
+}
+
+// encodeSignDoc returns a SignDoc with the messages, the memo and a fee of 5000uatom for 200000 gas
+func encodeSignDoc(chainID, memo string, msgs ...[]byte) []byte {
+	var body []byte
+	for _, m := range msgs {
+		body = append(body, protoBytes(1, m)...)
+	}
+	body = append(body, protoBytes(2, []byte(memo))...)
+
+	fee := bytes.Join([][]byte{protoBytes(1, encodeCoin("uatom", "5000")), protoVarint(2, 200000)}, nil)
+	authInfo := bytes.Join([][]byte{protoBytes(1, []byte{}), protoBytes(2, fee)}, nil)
+
+	return bytes.Join([][]byte{
+		protoBytes(1, body),
+		protoBytes(2, authInfo),
+		protoBytes(3, []byte(chainID)),
+		protoVarint(4, 12345),
+	}, nil)
+}
+
+func TestDecodeProtoSignDoc(t *testing.T) {
+	from := testAddress(t, "cosmos", 1)
+	to := testAddress(t, "cosmos", 2)
+	send := encodeMsgSend(from, to, encodeCoin("uatom", "1000000"))
+
+	tests := []struct {
+		name      string
+		signBytes []byte
+		wantError bool
+	}{
+		{
+			name:      "Valid sign doc",
+			signBytes: encodeSignDoc(cosmosHubChainID, "memo", send),
+		},
+		{
+			name:      "Duplicate chain id",
+			signBytes: append(encodeSignDoc(cosmosHubChainID, "", send), protoBytes(3, []byte("other-1"))...),
+			wantError: true,
+		},
+		{
+			name:      "Unknown sign doc field",
+			signBytes: append(encodeSignDoc(cosmosHubChainID, "", send), protoVarint(5, 1)...),
+			wantError: true,
+		},
+		{
+			name:      "Unsupported message",
+			signBytes: encodeSignDoc(cosmosHubChainID, "", encodeAny("/cosmos.gov.v1.MsgVote", protoVarint(1, 1))),
+			wantError: true,
+		},
+		{
+			name:      "Extension options",
+			signBytes: protoBytes(1, protoBytes(1023, encodeAny("/ext"))),
+			wantError: true,
+		},
+		{
+			name:      "Negative amount",
+			signBytes: encodeSignDoc(cosmosHubChainID, "", encodeMsgSend(from, to, encodeCoin("uatom", "-1"))),
+			wantError: true,
+		},
+		{
+			name:      "Truncated",
+			signBytes: encodeSignDoc(cosmosHubChainID, "", send)[:10],
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeSignDoc(tt.signBytes)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cosmosHubChainID, doc.chainID)
+			assert.Equal(t, uint64(12345), doc.accountNumber)
+			assert.Equal(t, "memo", doc.memo)
+			assert.Equal(t, uint64(200000), doc.fee.gasLimit)
+			assert.Equal(t, []coin{{denom: "uatom", amount: big.NewInt(5000)}}, doc.fee.amount)
+			assert.Equal(t, []msg{&msgSend{fromAddress: from, toAddress: to, amount: []coin{{denom: "uatom", amount: big.NewInt(1000000)}}}}, doc.msgs)
+		})
+	}
+}
+
+func TestDecodeAminoSignDoc(t *testing.T) {
+	from := testAddress(t, "cosmos", 1)
+	validator := testAddress(t, "cosmosvaloper", 3)
+
+	valid := `{"account_number":"12345","chain_id":"cosmoshub-4","fee":{"amount":[{"amount":"5000","denom":"uatom"}],"gas":"200000"},` +
+		`"memo":"memo","msgs":[{"type":"cosmos-sdk/MsgDelegate","value":{"amount":{"amount":"1000000","denom":"uatom"},` +
+		`"delegator_address":"` + from + `","validator_address":"` + validator + `"}}],"sequence":"7"}`
+
+	tests := []struct {
+		name      string
+		signBytes string
+		wantError bool
+	}{
+		{
+			name:      "Valid sign doc",
+			signBytes: valid,
+		},
+		{
+			name:      "Not compact",
+			signBytes: valid + "\n",
+			wantError: true,
+		},
+		{
+			name:      "Not sorted",
+			signBytes: `{"chain_id":"cosmoshub-4","account_number":"12345","fee":{"amount":[],"gas":"200000"},"memo":"","msgs":[],"sequence":"7"}`,
+			wantError: true,
+		},
+		{
+			name: "Duplicate key",
+			signBytes: `{"account_number":"12345","chain_id":"cosmoshub-4","chain_id":"other-1","fee":{"amount":[],"gas":"200000"},` +
+				`"memo":"","msgs":[],"sequence":"7"}`,
+			wantError: true,
+		},
+		{
+			name:      "Unknown field",
+			signBytes: `{"account_number":"12345","chain_id":"cosmoshub-4","fee":{"amount":[],"gas":"200000"},"memo":"","msgs":[],"other":"1","sequence":"7"}`,
+			wantError: true,
+		},
+		{
+			name: "Unsupported message",
+			signBytes: `{"account_number":"12345","chain_id":"cosmoshub-4","fee":{"amount":[],"gas":"200000"},"memo":"",` +
+				`"msgs":[{"type":"cosmos-sdk/MsgVote","value":{}}],"sequence":"7"}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeSignDoc([]byte(tt.signBytes))
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, cosmosHubChainID, doc.chainID)
+			assert.Equal(t, uint64(12345), doc.accountNumber)
+			assert.Equal(t, "memo", doc.memo)
+			assert.Equal(t, uint64(200000), doc.fee.gasLimit)
+			delegate := &msgDelegate{delegatorAddress: from, validatorAddress: validator, amount: coin{denom: "uatom", amount: big.NewInt(1000000)}}
+			assert.Equal(t, []msg{delegate}, doc.msgs)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cosmos
+
+import (
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	cosmosHubChainID      = "cosmoshub-4"
+	cosmosHubDenom        = "uatom"
+	cosmosHubBech32Prefix = "cosmos"
+)
+
+type Token struct {
+	tokenID string
+	// chainID is the chain id bound to the token, a sign doc of another chain is rejected
+	chainID string
+	// native flags the staking coin of the chain, whose transfers have no asset
+	native       bool
+	denom        string
+	feeDenom     string
+	bech32Prefix string
+}
+
+// NewToken returns the ATOM token of the Cosmos Hub
+func NewToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID:      tokenID,
+		chainID:      cosmosHubChainID,
+		native:       true,
+		denom:        cosmosHubDenom,
+		feeDenom:     cosmosHubDenom,
+		bech32Prefix: cosmosHubBech32Prefix,
+	}
+}
+
+func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	preTxData, err := prepareBuildTransactionData(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
+	}
+
+	doc, err := decodeSignDoc(preTxData.rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("prepare cosmos sign doc error: %w", err)
+	}
+	if doc.chainID != t.chainID {
+		return nil, fmt.Errorf("sign doc chain id %q mismatch chain id %q of token %v", doc.chainID, t.chainID, t.tokenID)
+	}
+
+	return &Transaction{doc: doc, PrepareTransactionData: preTxData, token: t}, nil
+}
+
+func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
+		return nil, fmt.Errorf("transaction info raw tx is nil")
+	}
+
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
+	if len(rawTxBytes) == 0 {
+		return nil, fmt.Errorf("transaction raw tx is empty")
+	}
+
+	return &PrepareTransactionData{rawTx: rawTxBytes, chainID: txInfo.Transaction.GetChainId()}, nil
+}
@@ -0,0 +1,102 @@
+package cosmos
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("ATOM")
+	atomToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "ATOM", atomToken.tokenID)
+	assert.Equal(t, cosmosHubChainID, atomToken.chainID)
+	assert.Equal(t, cosmosHubDenom, atomToken.denom)
+	assert.True(t, atomToken.native)
+}
+
+const (
+	// cosmosSignDoc is the SIGN_MODE_DIRECT sign doc on cosmoshub-4 of a MsgSend of 1234567uatom with memo "Cobo",
+	// signed by the secp256k1 key A08EGB7ro1ORuFhjOnZcSgwYlpe0DSFjVNUIkNNQxwKQ at account number 12345 and sequence 7
+	cosmosSignDoc = "0a99010a90010a1c2f636f736d6f732e62616e6b2e763162657461312e4d736753656e6412700a2d636f736d6f7331706b707472653766646b6c3667667" +
+		"27a6c65736a6a766878686c63337234676d6d6b38727336122d636f736d6f7331717970717870713971637273737a673270767871367273307a716733797963356c7a7637" +
+		"78751a100a057561746f6d1207313233343536371204436f626f12670a500a460a1f2f636f736d6f732e63727970746f2e736563703235366b312e5075624b657912230a21" +
+		"034f04181eeba35391b858633a765c4a0c189697b40d216354d50890d350c7029012040a020801180712130a0d0a057561746f6d12043530303010c09a0c1a0b636f736d6f" +
+		"736875622d3420b960"
+
+	// cosmosAminoSignDoc is the SIGN_MODE_LEGACY_AMINO_JSON sign doc of the same MsgSend
+	cosmosAminoSignDoc = `{"account_number":"12345","chain_id":"cosmoshub-4","fee":{"amount":[{"amount":"5000","denom":"uatom"}],` +
+		`"gas":"200000"},"memo":"Cobo","msgs":[{"type":"cosmos-sdk/MsgSend","value":{"amount":[{"amount":"1234567","denom":"uatom"}],` +
+		`"from_address":"cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6","to_address":"cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"}}],` +
+		`"sequence":"7"}`
+)
+
+func TestToken_BuildTransaction(t *testing.T) {
+	send := []token_adapter.Transfer{{
+		Chain:  cosmosHubChainID,
+		From:   "cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6",
+		To:     "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu",
+		Amount: big.NewInt(1234567),
+		Memo:   "Cobo",
+	}}
+
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:          "Protobuf sign doc",
+			rawTx:         cosmosSignDoc,
+			wantHashes:    []string{"0xa163c6a994d22ee797bbfbec3f43787f31097fde232070e6b7902fc9d60a389a"},
+			wantTransfers: send,
+		},
+		{
+			name:          "Amino sign doc",
+			rawTx:         hex.EncodeToString([]byte(cosmosAminoSignDoc)),
+			wantHashes:    []string{"0x6590a930a6239d1d6b8a6c4d3e6dff22389071d2e0195254f3175ee5bdebee83"},
+			wantTransfers: send,
+		},
+		{
+			name:      "Protobuf sign doc of another chain",
+			rawTx:     strings.Replace(cosmosSignDoc, "1a0b636f736d6f736875622d34", "1a11"+hex.EncodeToString([]byte("theta-testnet-001")), 1),
+			wantError: true,
+		},
+		{
+			name:      "Amino sign doc of another chain",
+			rawTx:     hex.EncodeToString([]byte(strings.Replace(cosmosAminoSignDoc, cosmosHubChainID, "theta-testnet-001", 1))),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewToken("ATOM").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
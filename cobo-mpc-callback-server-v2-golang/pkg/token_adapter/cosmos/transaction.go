@@ -0,0 +1,184 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+const (
+	// validatorPrefixSuffix is appended to the account prefix of a validator operator address
+	validatorPrefixSuffix = "valoper"
+	// ibcTransferPort is the port of ICS-20 fungible token transfers
+	ibcTransferPort = "transfer"
+
+	methodIbcTransfer = "ibcTransfer"
+	methodDelegate    = "delegate"
+)
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	doc *signDoc
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	// rawTx is the sign bytes of a protobuf SignDoc or an amino JSON StdSignDoc
+	rawTx   []byte
+	chainID string
+}
+
+// GetHashes implements Transaction interface for Cosmos, the hash is the sha256 of the sign bytes
+func (t *Transaction) GetHashes() ([]string, error) {
+	if len(t.rawTx) == 0 {
+		return nil, fmt.Errorf("transaction raw data is empty")
+	}
+
+	hash := sha256.Sum256(t.rawTx)
+	return []string{"0x" + hex.EncodeToString(hash[:])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for Cosmos
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Cosmos.
+// A MsgSend goes to the recipient, a MsgTransfer to the receiver on the counterparty chain and a MsgDelegate to the validator.
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.doc == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = t.token.chainID
+	}
+	asset := ""
+	if !t.token.native {
+		asset = t.token.denom
+	}
+
+	var transfers []token_adapter.Transfer
+	for idx, m := range t.doc.msgs {
+		transfer, err := t.decodeMsg(m)
+		if err != nil {
+			return nil, fmt.Errorf("parse message index %v %v: %w", idx, m.typeURL(), err)
+		}
+		transfer.Chain = chainID
+		transfer.Asset = asset
+		transfer.Memo = t.doc.memo
+		transfers = append(transfers, *transfer)
+	}
+
+	return transfers, nil
+}
+
+func (t *Transaction) decodeMsg(m msg) (*token_adapter.Transfer, error) {
+	switch m := m.(type) {
+	case *msgSend:
+		if err := checkAddress(m.fromAddress, t.token.bech32Prefix); err != nil {
+			return nil, err
+		}
+		if err := checkAddress(m.toAddress, t.token.bech32Prefix); err != nil {
+			return nil, err
+		}
+		if len(m.amount) != 1 {
+			return nil, fmt.Errorf("send of %d coins, only one is supported", len(m.amount))
+		}
+		if err := t.checkDenom(m.amount[0]); err != nil {
+			return nil, err
+		}
+		return &token_adapter.Transfer{From: m.fromAddress, To: m.toAddress, Amount: m.amount[0].amount}, nil
+	case *msgTransfer:
+		if err := checkAddress(m.sender, t.token.bech32Prefix); err != nil {
+			return nil, err
+		}
+		if m.receiver == "" {
+			return nil, fmt.Errorf("ibc receiver is empty")
+		}
+		if m.sourcePort != ibcTransferPort {
+			return nil, fmt.Errorf("unsupported ibc source port %q", m.sourcePort)
+		}
+		// a memo is executed by middleware on the counterparty chain, e.g. to forward the tokens past the receiver
+		if m.memo != "" {
+			return nil, fmt.Errorf("ibc transfer memo is not supported")
+		}
+		if err := t.checkDenom(m.token); err != nil {
+			return nil, err
+		}
+		return &token_adapter.Transfer{From: m.sender, To: m.receiver, Amount: m.token.amount, Method: methodIbcTransfer}, nil
+	case *msgDelegate:
+		if err := checkAddress(m.delegatorAddress, t.token.bech32Prefix); err != nil {
+			return nil, err
+		}
+		if err := checkAddress(m.validatorAddress, t.token.bech32Prefix+validatorPrefixSuffix); err != nil {
+			return nil, err
+		}
+		if err := t.checkDenom(m.amount); err != nil {
+			return nil, err
+		}
+		return &token_adapter.Transfer{From: m.delegatorAddress, To: m.validatorAddress, Amount: m.amount.amount, Method: methodDelegate}, nil
+	default:
+		return nil, fmt.Errorf("unsupported message %v", m.typeURL())
+	}
+}
+
+// checkDenom rejects a coin of another denom than the token
+func (t *Transaction) checkDenom(c coin) error {
+	if c.denom != t.token.denom {
+		return fmt.Errorf("coin denom %v mismatch denom %v of token %v", c.denom, t.token.denom, t.token.tokenID)
+	}
+	return nil
+}
+
+// GetFee implements FeeTransaction interface for Cosmos, the fee limit is the fee amount in the fee denom
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.doc == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	feeLimit := new(big.Int)
+	for _, c := range t.doc.fee.amount {
+		if c.denom != t.token.feeDenom {
+			return nil, fmt.Errorf("fee denom %v mismatch fee denom %v of token %v", c.denom, t.token.feeDenom, t.token.tokenID)
+		}
+		feeLimit.Add(feeLimit, c.amount)
+	}
+
+	return &token_adapter.Fee{
+		GasLimit: new(big.Int).SetUint64(t.doc.fee.gasLimit),
+		FeeLimit: feeLimit,
+	}, nil
+}
+
+// checkAddress requires a lowercase bech32 address of the prefix
+func checkAddress(address, prefix string) error {
+	hrp, data, err := bech32.DecodeToBase256(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	if hrp != prefix {
+		return fmt.Errorf("address %v prefix %v mismatch prefix %v", address, hrp, prefix)
+	}
+	// an uppercase address is valid bech32, but would not match the lowercase whitelist
+	if encoded, err := bech32.EncodeFromBase256(hrp, data); err != nil || encoded != address {
+		return fmt.Errorf("address %v is not lowercase bech32", address)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cosmos
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTransaction(t *testing.T, token *Token, signBytes []byte) *Transaction {
+	doc, err := decodeSignDoc(signBytes)
+	assert.NoError(t, err)
+	return &Transaction{token: token, PrepareTransactionData: &PrepareTransactionData{rawTx: signBytes}, doc: doc}
+}
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	from := testAddress(t, "cosmos", 1)
+	to := testAddress(t, "cosmos", 2)
+	validator := testAddress(t, "cosmosvaloper", 3)
+	receiver := testAddress(t, "osmo", 4)
+	atom := encodeCoin("uatom", "1000000")
+	ibcDenom := "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4"
+	ibcToken := &Token{tokenID: "ATOM_USDC", chainID: cosmosHubChainID, denom: ibcDenom, feeDenom: "uatom", bech32Prefix: "cosmos"}
+
+	tests := []struct {
+		name          string
+		token         *Token
+		msgs          [][]byte
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:  "MsgSend",
+			token: NewToken("ATOM").(*Token),
+			msgs:  [][]byte{encodeMsgSend(from, to, atom)},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: cosmosHubChainID, From: from, To: to, Amount: big.NewInt(1000000), Memo: "memo"},
+			},
+		},
+		{
+			name:  "MsgSend of an ibc coin",
+			token: ibcToken,
+			msgs:  [][]byte{encodeMsgSend(from, to, encodeCoin(ibcDenom, "25"))},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: cosmosHubChainID, Asset: ibcDenom, From: from, To: to, Amount: big.NewInt(25), Memo: "memo"},
+			},
+		},
+		{
+			name:  "MsgTransfer",
+			token: NewToken("ATOM").(*Token),
+			msgs:  [][]byte{encodeMsgTransfer(from, receiver, "", atom)},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: cosmosHubChainID, From: from, To: receiver, Amount: big.NewInt(1000000), Memo: "memo", Method: methodIbcTransfer},
+			},
+		},
+		{
+			name:  "MsgDelegate and MsgSend",
+			token: NewToken("ATOM").(*Token),
+			msgs:  [][]byte{encodeMsgDelegate(from, validator, atom), encodeMsgSend(from, to, atom)},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: cosmosHubChainID, From: from, To: validator, Amount: big.NewInt(1000000), Memo: "memo", Method: methodDelegate},
+				{Chain: cosmosHubChainID, From: from, To: to, Amount: big.NewInt(1000000), Memo: "memo"},
+			},
+		},
+		{
+			name:      "MsgSend of another denom",
+			token:     NewToken("ATOM").(*Token),
+			msgs:      [][]byte{encodeMsgSend(from, to, encodeCoin("uosmo", "1000000"))},
+			wantError: true,
+		},
+		{
+			name:      "MsgSend of two coins",
+			token:     NewToken("ATOM").(*Token),
+			msgs:      [][]byte{encodeMsgSend(from, to, atom, encodeCoin("uatom", "1"))},
+			wantError: true,
+		},
+		{
+			name:      "MsgSend to another chain prefix",
+			token:     NewToken("ATOM").(*Token),
+			msgs:      [][]byte{encodeMsgSend(from, receiver, atom)},
+			wantError: true,
+		},
+		{
+			name:      "MsgTransfer with memo",
+			token:     NewToken("ATOM").(*Token),
+			msgs:      [][]byte{encodeMsgTransfer(from, receiver, `{"forward":{"receiver":"osmo1"}}`, atom)},
+			wantError: true,
+		},
+		{
+			name:      "MsgDelegate to an account address",
+			token:     NewToken("ATOM").(*Token),
+			msgs:      [][]byte{encodeMsgDelegate(from, to, atom)},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTestTransaction(t, tt.token, encodeSignDoc(cosmosHubChainID, "memo", tt.msgs...))
+			transfers, err := tx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, token_adapter.DestinationAddresses(tt.wantTransfers), addresses)
+		})
+	}
+}
+
+func TestTransaction_GetFee(t *testing.T) {
+	signBytes := encodeSignDoc(cosmosHubChainID, "", encodeMsgSend(testAddress(t, "cosmos", 1), testAddress(t, "cosmos", 2)))
+
+	fee, err := newTestTransaction(t, NewToken("ATOM").(*Token), signBytes).GetFee()
+	assert.NoError(t, err)
+	assert.Equal(t, &token_adapter.Fee{GasLimit: big.NewInt(200000), FeeLimit: big.NewInt(5000)}, fee)
+
+	// the fee is paid in uatom, not in the denom of an osmosis token
+	_, err = newTestTransaction(t, &Token{tokenID: "OSMO", denom: "uosmo", feeDenom: "uosmo"}, signBytes).GetFee()
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package erc20
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// Methods of the ERC-20 token calls, TRC-20 shares the same ABI
+const (
+	MethodTransfer          = "transfer"
+	MethodTransferFrom      = "transferFrom"
+	MethodApprove           = "approve"
+	MethodIncreaseAllowance = "increaseAllowance"
+	MethodPermit            = "permit"
+)
+
+type method struct {
+	name string
+	// words is the number of 32 bytes arguments
+	words int
+}
+
+// methods maps the 4 bytes selector to the method
+var methods = map[string]method{
+	"a9059cbb": {MethodTransfer, 2},          // transfer(address,uint256)
+	"23b872dd": {MethodTransferFrom, 3},      // transferFrom(address,address,uint256)
+	"095ea7b3": {MethodApprove, 2},           // approve(address,uint256)
+	"39509351": {MethodIncreaseAllowance, 2}, // increaseAllowance(address,uint256)
+	"d505accf": {MethodPermit, 7},            // permit(address,address,uint256,uint256,uint8,bytes32,bytes32)
+}
+
+// Call is a decoded ERC-20 call
+type Call struct {
+	Method string
+	// Owner is the account whose tokens move or are approved, nil if it is the caller
+	Owner *common.Address
+	// To is the recipient of a transfer or the spender of an approval
+	To     common.Address
+	Amount *big.Int
+	// Deadline is the permit deadline, nil for other methods
+	Deadline *big.Int
+}
+
+// IsApproval reports whether the call grants an allowance instead of moving tokens
+func (c *Call) IsApproval() bool {
+	return IsApprovalMethod(c.Method)
+}
+
+// IsApprovalMethod reports whether the method grants an allowance instead of moving tokens
+func IsApprovalMethod(method string) bool {
+	switch method {
+	case MethodApprove, MethodIncreaseAllowance, MethodPermit:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsUnlimitedApproval reports whether the call grants the max uint256 allowance,
+// which token contracts treat as infinite and never decrease
+func (c *Call) IsUnlimitedApproval() bool {
+	return c.IsApproval() && c.Amount.Cmp(math.MaxBig256) == 0
+}
+
+// DecodeCall decodes the call data of an ERC-20 transfer, transferFrom, approve, increaseAllowance or permit
+func DecodeCall(input []byte) (*Call, error) {
+	if len(input) < 4 {
+		return nil, fmt.Errorf("invalid ERC20 call data length %d", len(input))
+	}
+
+	selector := hex.EncodeToString(input[:4])
+	m, ok := methods[selector]
+	if !ok {
+		return nil, fmt.Errorf("unsupported ERC20 method %v", selector)
+	}
+	if len(input) != 4+32*m.words {
+		return nil, fmt.Errorf("invalid ERC20 %v data length %d", m.name, len(input))
+	}
+
+	words := make([][]byte, m.words)
+	for i := range words {
+		words[i] = input[4+32*i : 4+32*(i+1)]
+	}
+
+	call := &Call{Method: m.name}
+	var err error
+	switch m.name {
+	case MethodTransfer, MethodApprove, MethodIncreaseAllowance:
+		if call.To, err = decodeAddress(words[0]); err != nil {
+			return nil, err
+		}
+		call.Amount = new(big.Int).SetBytes(words[1])
+	case MethodTransferFrom:
+		owner, err := decodeAddress(words[0])
+		if err != nil {
+			return nil, err
+		}
+		call.Owner = &owner
+		if call.To, err = decodeAddress(words[1]); err != nil {
+			return nil, err
+		}
+		call.Amount = new(big.Int).SetBytes(words[2])
+	case MethodPermit:
+		owner, err := decodeAddress(words[0])
+		if err != nil {
+			return nil, err
+		}
+		call.Owner = &owner
+		if call.To, err = decodeAddress(words[1]); err != nil {
+			return nil, err
+		}
+		call.Amount = new(big.Int).SetBytes(words[2])
+		call.Deadline = new(big.Int).SetBytes(words[3])
+	}
+
+	return call, nil
+}
+
+// decodeAddress decodes an address argument, whose high 12 bytes must be zero
+func decodeAddress(word []byte) (common.Address, error) {
+	if !bytes.Equal(word[:12], make([]byte, 12)) {
+		return common.Address{}, fmt.Errorf("invalid address argument %x", word)
+	}
+	return common.BytesToAddress(word[12:]), nil
+}
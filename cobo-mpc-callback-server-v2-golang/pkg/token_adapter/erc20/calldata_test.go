@@ -0,0 +1,116 @@
+package erc20
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	owner   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	spender = common.HexToAddress("0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd")
+)
+
+func callData(signature string, words ...[]byte) []byte {
+	data := crypto.Keccak256([]byte(signature))[:4]
+	for _, word := range words {
+		data = append(data, common.LeftPadBytes(word, 32)...)
+	}
+	return data
+}
+
+func TestDecodeCall(t *testing.T) {
+	amount := big.NewInt(50000000)
+	deadline := big.NewInt(1700000000)
+
+	tests := []struct {
+		name          string
+		input         []byte
+		wantCall      *Call
+		wantApproval  bool
+		wantUnlimited bool
+		wantError     bool
+	}{
+		{
+			name:     "transfer",
+			input:    callData("transfer(address,uint256)", spender.Bytes(), amount.Bytes()),
+			wantCall: &Call{Method: MethodTransfer, To: spender, Amount: amount},
+		},
+		{
+			name:     "transferFrom",
+			input:    callData("transferFrom(address,address,uint256)", owner.Bytes(), spender.Bytes(), amount.Bytes()),
+			wantCall: &Call{Method: MethodTransferFrom, Owner: &owner, To: spender, Amount: amount},
+		},
+		{
+			name:         "approve",
+			input:        callData("approve(address,uint256)", spender.Bytes(), amount.Bytes()),
+			wantCall:     &Call{Method: MethodApprove, To: spender, Amount: amount},
+			wantApproval: true,
+		},
+		{
+			name:          "unlimited approve",
+			input:         callData("approve(address,uint256)", spender.Bytes(), math.MaxBig256.Bytes()),
+			wantCall:      &Call{Method: MethodApprove, To: spender, Amount: math.MaxBig256},
+			wantApproval:  true,
+			wantUnlimited: true,
+		},
+		{
+			name:         "increaseAllowance",
+			input:        callData("increaseAllowance(address,uint256)", spender.Bytes(), amount.Bytes()),
+			wantCall:     &Call{Method: MethodIncreaseAllowance, To: spender, Amount: amount},
+			wantApproval: true,
+		},
+		{
+			name: "unlimited permit",
+			input: callData("permit(address,address,uint256,uint256,uint8,bytes32,bytes32)",
+				owner.Bytes(), spender.Bytes(), math.MaxBig256.Bytes(), deadline.Bytes(), []byte{27}, []byte{1}, []byte{2}),
+			wantCall:      &Call{Method: MethodPermit, Owner: &owner, To: spender, Amount: math.MaxBig256, Deadline: deadline},
+			wantApproval:  true,
+			wantUnlimited: true,
+		},
+		{
+			name:      "Unknown method",
+			input:     callData("mint(address,uint256)", spender.Bytes(), amount.Bytes()),
+			wantError: true,
+		},
+		{
+			name:      "Short data",
+			input:     callData("transfer(address,uint256)", spender.Bytes()),
+			wantError: true,
+		},
+		{
+			name:      "Trailing data",
+			input:     callData("transfer(address,uint256)", spender.Bytes(), amount.Bytes(), []byte{1}),
+			wantError: true,
+		},
+		{
+			name:      "Dirty address padding",
+			input:     callData("transfer(address,uint256)", append([]byte{1}, make([]byte, 31)...), amount.Bytes()),
+			wantError: true,
+		},
+		{
+			name:      "No selector",
+			input:     []byte{0xa9, 0x05},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, err := DecodeCall(tt.input)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, call)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCall, call)
+			assert.Equal(t, tt.wantApproval, call.IsApproval())
+			assert.Equal(t, tt.wantUnlimited, call.IsUnlimitedApproval())
+		})
+	}
+}
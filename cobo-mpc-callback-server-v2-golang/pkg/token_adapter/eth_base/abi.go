@@ -0,0 +1,200 @@
+package eth_base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type abiKey struct {
+	chainID uint64
+	address common.Address
+}
+
+var (
+	abiRegistryLock sync.RWMutex
+	// abiRegistry save the contract ABI by chain id and contract address
+	abiRegistry = make(map[abiKey]*abi.ABI)
+)
+
+// RegisterContractABI registers the ABI of a contract on a chain, used to decode the contract call data
+func RegisterContractABI(chainID uint64, address common.Address, contractABI *abi.ABI) error {
+	if chainID == 0 {
+		return fmt.Errorf("chain id of contract %v is empty", address.Hex())
+	}
+	if contractABI == nil {
+		return fmt.Errorf("abi of contract %v is nil", address.Hex())
+	}
+
+	abiRegistryLock.Lock()
+	defer abiRegistryLock.Unlock()
+
+	key := abiKey{chainID: chainID, address: address}
+	if _, exists := abiRegistry[key]; exists {
+		return fmt.Errorf("abi of contract %v on chain %v is already registered", address.Hex(), chainID)
+	}
+
+	abiRegistry[key] = contractABI
+	return nil
+}
+
+// GetContractABI returns the ABI registered for a contract on a chain
+func GetContractABI(chainID uint64, address common.Address) (*abi.ABI, bool) {
+	abiRegistryLock.RLock()
+	defer abiRegistryLock.RUnlock()
+
+	contractABI, ok := abiRegistry[abiKey{chainID: chainID, address: address}]
+	return contractABI, ok
+}
+
+// LoadContractABIs registers the contract ABIs of a directory laid out as <chain_id>/<contract_address>.json.
+// A file holds the JSON ABI, or a build artifact with the ABI under the "abi" field.
+func LoadContractABIs(dir string) error {
+	chainDirs, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read abi directory: %w", err)
+	}
+
+	for _, chainDir := range chainDirs {
+		if !chainDir.IsDir() {
+			continue
+		}
+		chainID, err := strconv.ParseUint(chainDir.Name(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("abi directory %v is not a chain id", chainDir.Name())
+		}
+
+		files, err := os.ReadDir(filepath.Join(dir, chainDir.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read abi directory of chain %v: %w", chainID, err)
+		}
+		for _, file := range files {
+			if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+				continue
+			}
+			address := strings.TrimSuffix(file.Name(), ".json")
+			if !common.IsHexAddress(address) {
+				return fmt.Errorf("abi file %v is not named by a contract address", file.Name())
+			}
+
+			contractABI, err := readABIFile(filepath.Join(dir, chainDir.Name(), file.Name()))
+			if err != nil {
+				return err
+			}
+			if err := RegisterContractABI(chainID, common.HexToAddress(address), contractABI); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func readABIFile(path string) (*abi.ABI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read abi file: %w", err)
+	}
+
+	// build artifacts of hardhat and foundry hold the ABI under the abi field
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var artifact struct {
+			ABI json.RawMessage `json:"abi"`
+		}
+		if err := json.Unmarshal(trimmed, &artifact); err != nil {
+			return nil, fmt.Errorf("failed to parse abi artifact %v: %w", path, err)
+		}
+		data = artifact.ABI
+	}
+
+	contractABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse abi file %v: %w", path, err)
+	}
+	return &contractABI, nil
+}
+
+// DecodeContractCall decodes call data with the ABI of the contract.
+// The arguments must be in the canonical encoding, so the decoded call is the only meaning of the data.
+func DecodeContractCall(contractABI *abi.ABI, contract common.Address, data []byte) (*token_adapter.ContractCall, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("invalid call data length %d", len(data))
+	}
+
+	method, err := contractABI.MethodById(data[:4])
+	if err != nil {
+		return nil, fmt.Errorf("method %x is not found in abi of contract %v", data[:4], contract.Hex())
+	}
+
+	values, err := unpackCanonical(*method, data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	call := &token_adapter.ContractCall{
+		Contract: contract.Hex(),
+		Method:   method.Name,
+		Args:     make([]token_adapter.ContractCallArg, 0, len(values)),
+	}
+	for i, input := range method.Inputs {
+		call.Args = append(call.Args, token_adapter.ContractCallArg{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: values[i],
+		})
+	}
+	return call, nil
+}
+
+// unpackCanonical unpacks the method arguments, which must be in the canonical encoding
+func unpackCanonical(method abi.Method, args []byte) ([]interface{}, error) {
+	values, err := method.Inputs.Unpack(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %v arguments: %w", method.Name, err)
+	}
+	packed, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %v arguments: %w", method.Name, err)
+	}
+	if !bytes.Equal(packed, args) {
+		return nil, fmt.Errorf("call data of %v is not in the canonical encoding", method.Name)
+	}
+	return values, nil
+}
+
+// GetContractCall implements ContractCallTransaction interface for Ethereum,
+// the call data is decoded with the ABI registered for the called contract
+func (t *Transaction) GetContractCall() (*token_adapter.ContractCall, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+	if t.tx.To() == nil || len(t.tx.Data()) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	chainID, err := TransactionChainID(t.tx)
+	if err != nil || !chainID.IsUint64() {
+		return nil, nil //nolint:nilnil
+	}
+	contractABI, ok := GetContractABI(chainID.Uint64(), *t.tx.To())
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	call, err := DecodeContractCall(contractABI, *t.tx.To(), t.tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	call.Value = new(big.Int).Set(t.tx.Value())
+	return call, nil
+}
@@ -0,0 +1,193 @@
+package eth_base
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	stakingRouterAddress = "0x1111111111111111111111111111111111111111"
+	stakingRouterABI     = `[
+		{"type":"function","name":"deposit","stateMutability":"payable",
+		 "inputs":[{"name":"amount","type":"uint256"},{"name":"referral","type":"address"}],"outputs":[]},
+		{"type":"function","name":"withdraw","stateMutability":"nonpayable",
+		 "inputs":[{"name":"amount","type":"uint256"}],"outputs":[]}
+	]`
+)
+
+// loadTestABIs registers the staking router ABI on chain 1 once
+func loadTestABIs(t *testing.T) {
+	if _, ok := GetContractABI(1, common.HexToAddress(stakingRouterAddress)); ok {
+		return
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "1"), 0o755))
+	artifact := `{"contractName":"StakingRouter","abi":` + stakingRouterABI + `}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "1", stakingRouterAddress+".json"), []byte(artifact), 0o600))
+	assert.NoError(t, LoadContractABIs(dir))
+}
+
+func depositCallData(amount int64, referral common.Address) []byte {
+	data := crypto.Keccak256([]byte("deposit(uint256,address)"))[:4]
+	data = append(data, common.LeftPadBytes(big.NewInt(amount).Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(referral.Bytes(), 32)...)
+}
+
+func TestLoadContractABIs(t *testing.T) {
+	// Clear registry before testing
+	abiRegistry = make(map[abiKey]*abi.ABI)
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantError bool
+	}{
+		{
+			name:  "ABI file",
+			files: map[string]string{"5/0x2222222222222222222222222222222222222222.json": stakingRouterABI},
+		},
+		{
+			name:  "Not a json file",
+			files: map[string]string{"5/README.md": "abi files"},
+		},
+		{
+			name:      "Not a chain id directory",
+			files:     map[string]string{"mainnet/0x2222222222222222222222222222222222222222.json": stakingRouterABI},
+			wantError: true,
+		},
+		{
+			name:      "Not an address file name",
+			files:     map[string]string{"5/router.json": stakingRouterABI},
+			wantError: true,
+		},
+		{
+			name:      "Invalid ABI",
+			files:     map[string]string{"5/0x3333333333333333333333333333333333333333.json": `[{"type":"function","name":`},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			}
+
+			err := LoadContractABIs(dir)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Error(t, LoadContractABIs(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestTransaction_GetContractCall(t *testing.T) {
+	loadTestABIs(t)
+
+	router := common.HexToAddress(stakingRouterAddress)
+	referral := common.HexToAddress("0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd")
+	unknown := common.HexToAddress("0x4444444444444444444444444444444444444444")
+
+	tests := []struct {
+		name      string
+		chainID   int64
+		to        *common.Address
+		data      []byte
+		wantCall  *token_adapter.ContractCall
+		wantError bool
+	}{
+		{
+			name:    "Registered contract",
+			chainID: 1,
+			to:      &router,
+			data:    depositCallData(100, referral),
+			wantCall: &token_adapter.ContractCall{
+				Contract: router.Hex(),
+				Method:   "deposit",
+				Args: []token_adapter.ContractCallArg{
+					{Name: "amount", Type: "uint256", Value: big.NewInt(100)},
+					{Name: "referral", Type: "address", Value: referral},
+				},
+				Value: big.NewInt(1000),
+			},
+		},
+		{
+			name:    "Contract registered on another chain",
+			chainID: 56,
+			to:      &router,
+			data:    depositCallData(100, referral),
+		},
+		{
+			name:    "Unknown contract",
+			chainID: 1,
+			to:      &unknown,
+			data:    depositCallData(100, referral),
+		},
+		{
+			name:    "Plain transfer",
+			chainID: 1,
+			to:      &router,
+		},
+		{
+			name:      "Method not in ABI",
+			chainID:   1,
+			to:        &router,
+			data:      crypto.Keccak256([]byte("claim()"))[:4],
+			wantError: true,
+		},
+		{
+			name:      "Non canonical address argument",
+			chainID:   1,
+			to:        &router,
+			data:      append(depositCallData(100, referral)[:36], append([]byte{1}, make([]byte, 31)...)...),
+			wantError: true,
+		},
+		{
+			name:      "Trailing data",
+			chainID:   1,
+			to:        &router,
+			data:      append(depositCallData(100, referral), 0x01),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := &Transaction{
+				token: &Token{tokenID: "ETH"},
+				tx: types.NewTx(&types.DynamicFeeTx{
+					ChainID: big.NewInt(tt.chainID),
+					To:      tt.to,
+					Value:   big.NewInt(1000),
+					Data:    tt.data,
+				}),
+			}
+
+			call, err := transaction.GetContractCall()
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, call)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCall, call)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package eth_base
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	TokenTypeNative = "native"
+	TokenTypeErc20  = "erc20"
+)
+
+// TokenConfig declares an EVM token registered with the eth_base adapter at startup
+type TokenConfig struct {
+	TokenID string `mapstructure:"token_id"`
+	// ChainID is the EIP-155 chain id, a raw transaction of another chain is rejected
+	ChainID uint64 `mapstructure:"chain_id"`
+	// Type is native or erc20
+	Type string `mapstructure:"type"`
+	// ContractAddress is the ERC-20 contract, required for erc20 tokens
+	ContractAddress string `mapstructure:"contract_address"`
+	Decimals        int32  `mapstructure:"decimals"`
+	// EntryPoint is the ERC-4337 EntryPoint of user operations, which are rejected when empty
+	EntryPoint string `mapstructure:"entry_point"`
+	// EntryPointVersion is 0.6 or 0.7, detected for the canonical EntryPoint deployments when empty
+	EntryPointVersion string `mapstructure:"entry_point_version"`
+}
+
+// NewTokenCreator returns the creator of a token declared in config
+func NewTokenCreator(cfg TokenConfig) (token_adapter.TokenCreator, error) {
+	if strings.TrimSpace(cfg.TokenID) == "" {
+		return nil, fmt.Errorf("evm token id is empty")
+	}
+	if cfg.ChainID == 0 {
+		return nil, fmt.Errorf("chain id of evm token %v is empty", cfg.TokenID)
+	}
+	if cfg.Decimals < 0 {
+		return nil, fmt.Errorf("decimals of evm token %v is negative", cfg.TokenID)
+	}
+
+	chainID := new(big.Int).SetUint64(cfg.ChainID)
+	var entryPoint *EntryPoint
+	if cfg.EntryPoint != "" {
+		var err error
+		if entryPoint, err = NewEntryPoint(cfg.EntryPoint, cfg.EntryPointVersion); err != nil {
+			return nil, fmt.Errorf("invalid entry point of evm token %v: %w", cfg.TokenID, err)
+		}
+	} else if cfg.EntryPointVersion != "" {
+		return nil, fmt.Errorf("evm token %v has entry point version without entry point", cfg.TokenID)
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case TokenTypeNative:
+		if cfg.ContractAddress != "" {
+			return nil, fmt.Errorf("native evm token %v has contract address", cfg.TokenID)
+		}
+		return func(tokenID string) token_adapter.Token {
+			return &Token{
+				tokenID:    tokenID,
+				chainID:    chainID,
+				entryPoint: entryPoint,
+			}
+		}, nil
+	case TokenTypeErc20:
+		if !common.IsHexAddress(cfg.ContractAddress) {
+			return nil, fmt.Errorf("invalid contract address %q of evm token %v", cfg.ContractAddress, cfg.TokenID)
+		}
+		contractAddress := common.HexToAddress(cfg.ContractAddress)
+		return func(tokenID string) token_adapter.Token {
+			return &Token{
+				tokenID:         tokenID,
+				erc20Token:      true,
+				chainID:         chainID,
+				contractAddress: &contractAddress,
+				entryPoint:      entryPoint,
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q of evm token %v", cfg.Type, cfg.TokenID)
+	}
+}
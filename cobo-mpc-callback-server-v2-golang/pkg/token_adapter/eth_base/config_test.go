@@ -0,0 +1,170 @@
+package eth_base
+
+import (
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+const usdtContractAddress = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+
+func TestNewTokenCreator(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       TokenConfig
+		wantErc20 bool
+		wantError bool
+	}{
+		{
+			name:      "Native token",
+			cfg:       TokenConfig{TokenID: "BSC_BNB", ChainID: 56, Type: "native", Decimals: 18},
+			wantErc20: false,
+		},
+		{
+			name:      "ERC20 token",
+			cfg:       TokenConfig{TokenID: "BASE_USDC", ChainID: 8453, Type: "erc20", ContractAddress: usdtContractAddress, Decimals: 6},
+			wantErc20: true,
+		},
+		{
+			name:      "Empty token id",
+			cfg:       TokenConfig{ChainID: 56, Type: "native"},
+			wantError: true,
+		},
+		{
+			name:      "Empty chain id",
+			cfg:       TokenConfig{TokenID: "BSC_BNB", Type: "native"},
+			wantError: true,
+		},
+		{
+			name:      "Native token with contract",
+			cfg:       TokenConfig{TokenID: "BSC_BNB", ChainID: 56, Type: "native", ContractAddress: usdtContractAddress},
+			wantError: true,
+		},
+		{
+			name:      "ERC20 token without contract",
+			cfg:       TokenConfig{TokenID: "BASE_USDC", ChainID: 8453, Type: "erc20"},
+			wantError: true,
+		},
+		{
+			name: "Canonical entry point",
+			cfg:  TokenConfig{TokenID: "BASE_ETH", ChainID: 8453, Type: "native", EntryPoint: "0x0000000071727De22E5E9d8BAf0edAc6f37da032"},
+		},
+		{
+			name:      "Entry point without version",
+			cfg:       TokenConfig{TokenID: "BASE_ETH", ChainID: 8453, Type: "native", EntryPoint: usdtContractAddress},
+			wantError: true,
+		},
+		{
+			name:      "Unknown entry point version",
+			cfg:       TokenConfig{TokenID: "BASE_ETH", ChainID: 8453, Type: "native", EntryPoint: usdtContractAddress, EntryPointVersion: "0.8"},
+			wantError: true,
+		},
+		{
+			name:      "Unknown type",
+			cfg:       TokenConfig{TokenID: "BSC_BNB", ChainID: 56, Type: "bep20"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creator, err := NewTokenCreator(tt.cfg)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, creator)
+				return
+			}
+			assert.NoError(t, err)
+
+			token, ok := creator(tt.cfg.TokenID).(*Token)
+			assert.True(t, ok)
+			assert.Equal(t, tt.cfg.TokenID, token.tokenID)
+			assert.Equal(t, tt.wantErc20, token.erc20Token)
+			assert.Equal(t, tt.cfg.ChainID, token.chainID.Uint64())
+		})
+	}
+}
+
+func TestConfiguredToken_BuildTransaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       TokenConfig
+		rawTx     string
+		wantError bool
+	}{
+		{
+			name:  "Legacy transaction of the chain",
+			cfg:   TokenConfig{TokenID: "ETH", ChainID: 1, Type: "native"},
+			rawTx: ethRawTx,
+		},
+		{
+			name:      "Legacy transaction of another chain",
+			cfg:       TokenConfig{TokenID: "BSC_BNB", ChainID: 56, Type: "native"},
+			rawTx:     ethRawTx,
+			wantError: true,
+		},
+		{
+			name:  "EIP-1559 transaction of the chain",
+			cfg:   TokenConfig{TokenID: "SEPOLIA_ETH", ChainID: 11155111, Type: "native"},
+			rawTx: ethEip1559RawTx,
+		},
+		{
+			name:      "EIP-1559 transaction of another chain",
+			cfg:       TokenConfig{TokenID: "ETH", ChainID: 1, Type: "native"},
+			rawTx:     ethEip1559RawTx,
+			wantError: true,
+		},
+		{
+			name:  "EIP-7702 transaction of the chain",
+			cfg:   TokenConfig{TokenID: "ETH", ChainID: 1, Type: "native"},
+			rawTx: ethSetCodeRawTx,
+		},
+		{
+			name:      "EIP-7702 authorization of another chain",
+			cfg:       TokenConfig{TokenID: "ETH", ChainID: 1, Type: "native"},
+			rawTx:     ethSetCodeAuthChain,
+			wantError: true,
+		},
+		{
+			name:      "Legacy transaction without chain id",
+			cfg:       TokenConfig{TokenID: "ETH", ChainID: 1, Type: "native"},
+			rawTx:     ethPreEip155RawTx,
+			wantError: true,
+		},
+		{
+			name:  "ERC20 transaction to the contract",
+			cfg:   TokenConfig{TokenID: "USDT", ChainID: 1, Type: "erc20", ContractAddress: usdtContractAddress},
+			rawTx: erc20RawTx,
+		},
+		{
+			name:      "ERC20 transaction to another contract",
+			cfg:       TokenConfig{TokenID: "USDC", ChainID: 1, Type: "erc20", ContractAddress: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"},
+			rawTx:     erc20RawTx,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creator, err := NewTokenCreator(tt.cfg)
+			assert.NoError(t, err)
+
+			tx, err := creator(tt.cfg.TokenID).BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+						UnsignedRawTx: &tt.rawTx,
+					},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, tx)
+			}
+		})
+	}
+}
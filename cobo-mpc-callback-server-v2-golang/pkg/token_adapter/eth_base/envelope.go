@@ -0,0 +1,189 @@
+package eth_base
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+)
+
+// The unsigned payloads of the transaction envelopes, which are the RLP fields before the signature
+
+type unsignedLegacyTx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       *common.Address `rlp:"nil"`
+	Value    *big.Int
+	Data     []byte
+	// EIP-155 replay protection fields: chain id, 0, 0
+	ChainID *big.Int `rlp:"optional"`
+	R       *big.Int `rlp:"optional"`
+	S       *big.Int `rlp:"optional"`
+}
+
+type unsignedAccessListTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasPrice   *big.Int
+	Gas        uint64
+	To         *common.Address `rlp:"nil"`
+	Value      *big.Int
+	Data       []byte
+	AccessList types.AccessList
+}
+
+type unsignedDynamicFeeTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *common.Address `rlp:"nil"`
+	Value      *big.Int
+	Data       []byte
+	AccessList types.AccessList
+}
+
+type unsignedBlobTx struct {
+	ChainID    *uint256.Int
+	Nonce      uint64
+	GasTipCap  *uint256.Int
+	GasFeeCap  *uint256.Int
+	Gas        uint64
+	To         common.Address
+	Value      *uint256.Int
+	Data       []byte
+	AccessList types.AccessList
+	BlobFeeCap *uint256.Int
+	BlobHashes []common.Hash
+}
+
+type unsignedSetCodeTx struct {
+	ChainID    *uint256.Int
+	Nonce      uint64
+	GasTipCap  *uint256.Int
+	GasFeeCap  *uint256.Int
+	Gas        uint64
+	To         common.Address
+	Value      *uint256.Int
+	Data       []byte
+	AccessList types.AccessList
+	AuthList   []types.SetCodeAuthorization
+}
+
+// decodeUnsignedTx decodes the unsigned payload of a legacy or typed transaction envelope
+func decodeUnsignedTx(rawTx []byte) (types.TxData, error) {
+	if rawTx[0] >= 0xc0 {
+		return decodeUnsignedLegacyTx(rawTx)
+	}
+
+	payload := rawTx[1:]
+	switch rawTx[0] {
+	case types.AccessListTxType:
+		var tx unsignedAccessListTx
+		if err := rlp.DecodeBytes(payload, &tx); err != nil {
+			return nil, err
+		}
+		return &types.AccessListTx{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasPrice:   tx.GasPrice,
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      tx.Value,
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+		}, nil
+	case types.DynamicFeeTxType:
+		var tx unsignedDynamicFeeTx
+		if err := rlp.DecodeBytes(payload, &tx); err != nil {
+			return nil, err
+		}
+		return &types.DynamicFeeTx{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasTipCap:  tx.GasTipCap,
+			GasFeeCap:  tx.GasFeeCap,
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      tx.Value,
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+		}, nil
+	case types.BlobTxType:
+		var tx unsignedBlobTx
+		if err := rlp.DecodeBytes(payload, &tx); err != nil {
+			return nil, err
+		}
+		return &types.BlobTx{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasTipCap:  tx.GasTipCap,
+			GasFeeCap:  tx.GasFeeCap,
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      tx.Value,
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+			BlobFeeCap: tx.BlobFeeCap,
+			BlobHashes: tx.BlobHashes,
+		}, nil
+	case types.SetCodeTxType:
+		var tx unsignedSetCodeTx
+		if err := rlp.DecodeBytes(payload, &tx); err != nil {
+			return nil, err
+		}
+		return &types.SetCodeTx{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasTipCap:  tx.GasTipCap,
+			GasFeeCap:  tx.GasFeeCap,
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      tx.Value,
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+			AuthList:   tx.AuthList,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %#x", rawTx[0])
+	}
+}
+
+// decodeUnsignedLegacyTx decodes a legacy payload, with or without the EIP-155 fields.
+// The EIP-155 chain id is kept in V as a signed transaction would, so the chain id can be derived.
+func decodeUnsignedLegacyTx(rawTx []byte) (types.TxData, error) {
+	var tx unsignedLegacyTx
+	if err := rlp.DecodeBytes(rawTx, &tx); err != nil {
+		return nil, err
+	}
+
+	legacy := &types.LegacyTx{
+		Nonce:    tx.Nonce,
+		GasPrice: tx.GasPrice,
+		Gas:      tx.Gas,
+		To:       tx.To,
+		Value:    tx.Value,
+		Data:     tx.Data,
+		R:        new(big.Int),
+		S:        new(big.Int),
+	}
+
+	switch {
+	case tx.ChainID == nil:
+		// pre EIP-155 payload
+		legacy.V = big.NewInt(27)
+	case tx.R == nil || tx.S == nil:
+		return nil, fmt.Errorf("incomplete EIP-155 fields")
+	case tx.ChainID.Sign() <= 0 || tx.R.Sign() != 0 || tx.S.Sign() != 0:
+		return nil, fmt.Errorf("invalid EIP-155 fields, chain id %v r %v s %v", tx.ChainID, tx.R, tx.S)
+	default:
+		legacy.V = new(big.Int).Add(new(big.Int).Lsh(tx.ChainID, 1), big.NewInt(35))
+	}
+
+	return legacy, nil
+}
@@ -0,0 +1,204 @@
+package eth_base
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/erc20"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// permitPrimaryType is the EIP-712 primary type of an EIP-2612 permit
+const permitPrimaryType = "Permit"
+
+// Message is an EIP-191 or EIP-712 message signed by the source address
+type Message struct {
+	token     *Token
+	hash      common.Hash
+	message   *token_adapter.Message
+	transfers []token_adapter.Transfer
+}
+
+// GetHashes implements Transaction interface for a message, the digest is recomputed from the message
+func (m *Message) GetHashes() ([]string, error) {
+	return []string{m.hash.String()}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for a message
+func (m *Message) GetDestinationAddresses() ([]string, error) {
+	return token_adapter.DestinationAddresses(m.transfers), nil
+}
+
+// GetTransfers implements Transaction interface for a message, only a permit grants a transfer
+func (m *Message) GetTransfers() ([]token_adapter.Transfer, error) {
+	return m.transfers, nil
+}
+
+// GetMessage implements MessageTransaction interface for a message
+func (m *Message) GetMessage() (*token_adapter.Message, error) {
+	return m.message, nil
+}
+
+// GetMessage implements MessageTransaction interface for a Safe message
+func (m *SafeMessage) GetMessage() (*token_adapter.Message, error) {
+	return &token_adapter.Message{
+		Standard:    token_adapter.MessageStandardEIP712,
+		PrimaryType: safeTxPrimaryType,
+		Domain:      &token_adapter.TypedDataDomain{ChainID: m.chainID, VerifyingContract: m.safe.Hex()},
+		Decoded:     true,
+	}, nil
+}
+
+// buildMessage builds the message of an EIP-191 or EIP-712 message sign request, nil for another request
+func (t *Token) buildMessage(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	if txInfo == nil || txInfo.Transaction == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	destination := txInfo.Transaction.Destination
+	switch {
+	case destination.TransactionMessageSignEIP191Destination != nil:
+		if t.erc20Token {
+			return nil, fmt.Errorf("eip191 message is not supported by erc20 token %v", t.tokenID)
+		}
+		return t.buildPersonalMessage(destination.TransactionMessageSignEIP191Destination)
+	case destination.TransactionMessageSignEIP712Destination != nil:
+		return t.buildTypedDataMessage(txInfo, destination.TransactionMessageSignEIP712Destination)
+	default:
+		return nil, nil //nolint:nilnil
+	}
+}
+
+// buildPersonalMessage recomputes the EIP-191 personal_sign digest of the base64 message
+func (t *Token) buildPersonalMessage(destination *coboWaaS2.TransactionMessageSignEIP191Destination) (*Message, error) {
+	data, err := base64.StdEncoding.DecodeString(destination.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode eip191 message: %w", err)
+	}
+
+	return &Message{
+		token:     t,
+		hash:      common.BytesToHash(accounts.TextHash(data)),
+		message:   &token_adapter.Message{Standard: token_adapter.MessageStandardEIP191, Content: token_adapter.MessageContent(data)},
+		transfers: []token_adapter.Transfer{},
+	}, nil
+}
+
+// buildTypedDataMessage recomputes the EIP-712 digest of the typed data, a SafeTx is verified as a Safe message
+func (t *Token) buildTypedDataMessage(
+	txInfo *token_adapter.TransactionInfo,
+	destination *coboWaaS2.TransactionMessageSignEIP712Destination,
+) (token_adapter.Transaction, error) {
+	data, err := parseTypedData(destination)
+	if err != nil {
+		return nil, err
+	}
+	if data.PrimaryType == safeTxPrimaryType {
+		if t.erc20Token {
+			return nil, fmt.Errorf("safe transaction is not supported by erc20 token %v", t.tokenID)
+		}
+		return t.buildSafeMessage(txInfo, data)
+	}
+
+	raw, err := typedDataJSON(destination)
+	if err != nil {
+		return nil, err
+	}
+	var typed apitypes.TypedData
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return nil, fmt.Errorf("failed to parse structured data: %w", err)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	domain := &token_adapter.TypedDataDomain{}
+	if name, ok := data.Domain["name"].(string); ok {
+		domain.Name = name
+	}
+	if _, ok := data.Domain["chainId"]; ok {
+		if domain.ChainID, err = typedBigInt(data.Domain, "chainId"); err != nil {
+			return nil, fmt.Errorf("invalid typed data domain: %w", err)
+		}
+		if t.chainID != nil && domain.ChainID.Cmp(t.chainID) != 0 {
+			return nil, fmt.Errorf("typed data domain chain id %v mismatch chain id %v of token %v", domain.ChainID, t.chainID, t.tokenID)
+		}
+	}
+	if _, ok := data.Domain["verifyingContract"]; ok {
+		verifyingContract, err := typedAddress(data.Domain, "verifyingContract")
+		if err != nil {
+			return nil, fmt.Errorf("invalid typed data domain: %w", err)
+		}
+		domain.VerifyingContract = verifyingContract.Hex()
+	}
+
+	// an erc20 token only signs typed data of its contract
+	if t.erc20Token {
+		contractAddress, err := t.expectedContract()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(domain.VerifyingContract, contractAddress.Hex()) {
+			return nil, fmt.Errorf("typed data contract %v mismatch contract %v of token %v", domain.VerifyingContract, contractAddress.Hex(), t.tokenID)
+		}
+	}
+
+	message := &Message{
+		token: t,
+		hash:  common.BytesToHash(hash),
+		message: &token_adapter.Message{
+			Standard:    token_adapter.MessageStandardEIP712,
+			PrimaryType: data.PrimaryType,
+			Domain:      domain,
+		},
+		transfers: []token_adapter.Transfer{},
+	}
+
+	// an EIP-2612 permit approves the spender like an approve call
+	if data.PrimaryType == permitPrimaryType && domain.VerifyingContract != "" {
+		transfer, err := decodeTypedPermit(data.Message)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permit: %w", err)
+		}
+		transfer.Chain = txInfo.Transaction.GetChainId()
+		if transfer.Chain == "" {
+			transfer.Chain = defaultChainID
+		}
+		transfer.Asset = domain.VerifyingContract
+		message.transfers = append(message.transfers, *transfer)
+		message.message.Decoded = true
+	}
+
+	return message, nil
+}
+
+func decodeTypedPermit(message map[string]interface{}) (*token_adapter.Transfer, error) {
+	owner, err := typedAddress(message, "owner")
+	if err != nil {
+		return nil, err
+	}
+	spender, err := typedAddress(message, "spender")
+	if err != nil {
+		return nil, err
+	}
+	value, err := typedBigInt(message, "value")
+	if err != nil {
+		return nil, err
+	}
+
+	call := &erc20.Call{Method: erc20.MethodPermit, Owner: &owner, To: spender, Amount: value}
+	return &token_adapter.Transfer{
+		From:              owner.Hex(),
+		To:                spender.Hex(),
+		Amount:            value,
+		Method:            call.Method,
+		UnlimitedApproval: call.IsUnlimitedApproval(),
+	}, nil
+}
@@ -0,0 +1,231 @@
+package eth_base
+
+import (
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// mailTypedData is the example of EIP-712
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"}, {"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"}, {"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+		"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+	},
+	"primaryType": "Mail",
+	"domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+const mailTypedDataHash = "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+
+func permitTypedData(chainID, value string) string {
+	return `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"}, {"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"}, {"name": "verifyingContract", "type": "address"}
+		],
+		"Permit": [
+			{"name": "owner", "type": "address"}, {"name": "spender", "type": "address"}, {"name": "value", "type": "uint256"},
+			{"name": "nonce", "type": "uint256"}, {"name": "deadline", "type": "uint256"}
+		]
+	},
+	"primaryType": "Permit",
+	"domain": {"name": "Tether USD", "version": "1", "chainId": ` + chainID + `, "verifyingContract": "` + usdtContractAddress + `"},
+	"message": {
+		"owner": "0x1111111111111111111111111111111111111111",
+		"spender": "` + safeRecipient.Hex() + `",
+		"value": "` + value + `",
+		"nonce": "0",
+		"deadline": "1893456000"
+	}
+}`
+}
+
+func eip712TransactionInfo(raw string) *token_adapter.TransactionInfo {
+	chainID := "ETH"
+	return &token_adapter.TransactionInfo{
+		Transaction: &coboWaaS2.Transaction{
+			ChainId: &chainID,
+			Destination: coboWaaS2.TransactionMessageSignEIP712DestinationAsTransactionDestination(
+				&coboWaaS2.TransactionMessageSignEIP712Destination{RawStructuredData: &raw},
+			),
+		},
+	}
+}
+
+func TestToken_BuildTransaction_EIP191Message(t *testing.T) {
+	message := []byte("Sign in to example.com")
+	wantHash := crypto.Keccak256Hash([]byte("\x19Ethereum Signed Message:\n22"), message)
+
+	tests := []struct {
+		name      string
+		token     *Token
+		message   string
+		wantError bool
+	}{
+		{
+			name:    "Personal message",
+			token:   &Token{tokenID: "ETH"},
+			message: base64.StdEncoding.EncodeToString(message),
+		},
+		{
+			name:      "Message not in base64",
+			token:     &Token{tokenID: "ETH"},
+			message:   "not base64!",
+			wantError: true,
+		},
+		{
+			name:      "ERC20 token",
+			token:     &Token{tokenID: "ETH_USDT", erc20Token: true},
+			message:   base64.StdEncoding.EncodeToString(message),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := tt.token.BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					Destination: coboWaaS2.TransactionMessageSignEIP191DestinationAsTransactionDestination(
+						&coboWaaS2.TransactionMessageSignEIP191Destination{Message: tt.message},
+					),
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{wantHash.String()}, hashes)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Empty(t, addresses)
+
+			msgTx, ok := tx.(token_adapter.MessageTransaction)
+			assert.True(t, ok)
+			msg, err := msgTx.GetMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Message{Standard: token_adapter.MessageStandardEIP191, Content: string(message)}, msg)
+		})
+	}
+}
+
+func TestToken_BuildTransaction_EIP712Message(t *testing.T) {
+	usdt := common.HexToAddress(usdtContractAddress)
+
+	tests := []struct {
+		name          string
+		token         *Token
+		typedData     string
+		wantHash      string
+		wantMessage   *token_adapter.Message
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:      "Typed data",
+			token:     &Token{tokenID: "ETH", chainID: big.NewInt(1)},
+			typedData: mailTypedData,
+			wantHash:  mailTypedDataHash,
+			wantMessage: &token_adapter.Message{
+				Standard:    token_adapter.MessageStandardEIP712,
+				PrimaryType: "Mail",
+				Domain: &token_adapter.TypedDataDomain{
+					Name:              "Ether Mail",
+					ChainID:           big.NewInt(1),
+					VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+				},
+			},
+			wantTransfers: []token_adapter.Transfer{},
+		},
+		{
+			name:      "Unlimited permit",
+			token:     &Token{tokenID: "ETH_USDT", erc20Token: true, contractAddress: &usdt},
+			typedData: permitTypedData("1", math.MaxBig256.String()),
+			wantMessage: &token_adapter.Message{
+				Standard:    token_adapter.MessageStandardEIP712,
+				PrimaryType: "Permit",
+				Domain:      &token_adapter.TypedDataDomain{Name: "Tether USD", ChainID: big.NewInt(1), VerifyingContract: usdtContractAddress},
+				Decoded:     true,
+			},
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:             "ETH",
+				Asset:             usdtContractAddress,
+				From:              "0x1111111111111111111111111111111111111111",
+				To:                safeRecipient.Hex(),
+				Amount:            math.MaxBig256,
+				Method:            "permit",
+				UnlimitedApproval: true,
+			}},
+		},
+		{
+			name:      "Domain of another chain",
+			token:     &Token{tokenID: "BSC_BNB", chainID: big.NewInt(56)},
+			typedData: mailTypedData,
+			wantError: true,
+		},
+		{
+			name:      "ERC20 token signing typed data of another contract",
+			token:     &Token{tokenID: "ETH_USDT", erc20Token: true, contractAddress: &usdt},
+			typedData: mailTypedData,
+			wantError: true,
+		},
+		{
+			name:      "Field out of the types",
+			token:     &Token{tokenID: "ETH"},
+			typedData: permitTypedData("1", "1\", \"extra\": \"1"),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := tt.token.BuildTransaction(eip712TransactionInfo(tt.typedData))
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Len(t, hashes, 1)
+			if tt.wantHash != "" {
+				assert.Equal(t, tt.wantHash, hashes[0])
+			}
+			assert.Len(t, hexutil.MustDecode(hashes[0]), 32)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			message, err := tx.(token_adapter.MessageTransaction).GetMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package eth_base
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/erc20"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Safe operations of a SafeTx and a MultiSend transaction
+const (
+	SafeOperationCall         uint8 = 0
+	SafeOperationDelegateCall uint8 = 1
+)
+
+// maxSafeCallDepth bounds the nesting of Safe and MultiSend calls
+const maxSafeCallDepth = 8
+
+const safeABIJSON = `[
+	{"type":"function","name":"execTransaction","stateMutability":"payable","inputs":[
+		{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},
+		{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},
+		{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},
+		{"name":"signatures","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]},
+	{"type":"function","name":"multiSend","stateMutability":"payable","inputs":[
+		{"name":"transactions","type":"bytes"}],"outputs":[]}
+]`
+
+var (
+	safeABI = mustParseABI(safeABIJSON)
+
+	// safeTxTypeHash is the EIP-712 type hash of SafeTx
+	safeTxTypeHash = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation," +
+		"uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+	// safeDomainTypeHash is the EIP-712 domain type hash of Safe 1.3.0 and later
+	safeDomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+)
+
+// SafeTx is the transaction executed by a Safe
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	// Nonce is the Safe nonce, nil when decoded from execTransaction
+	Nonce *big.Int
+}
+
+func mustParseABI(abiJSON string) *abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	return &parsed
+}
+
+// SafeTxHash returns the EIP-712 hash of a SafeTx signed by the Safe owners
+func SafeTxHash(chainID *big.Int, safe common.Address, tx *SafeTx) common.Hash {
+	domainSeparator := crypto.Keccak256Hash(
+		safeDomainTypeHash.Bytes(),
+		math.U256Bytes(new(big.Int).Set(chainID)),
+		common.LeftPadBytes(safe.Bytes(), 32),
+	)
+	structHash := crypto.Keccak256Hash(
+		safeTxTypeHash.Bytes(),
+		common.LeftPadBytes(tx.To.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(tx.Value)),
+		crypto.Keccak256(tx.Data),
+		common.LeftPadBytes([]byte{tx.Operation}, 32),
+		math.U256Bytes(new(big.Int).Set(tx.SafeTxGas)),
+		math.U256Bytes(new(big.Int).Set(tx.BaseGas)),
+		math.U256Bytes(new(big.Int).Set(tx.GasPrice)),
+		common.LeftPadBytes(tx.GasToken.Bytes(), 32),
+		common.LeftPadBytes(tx.RefundReceiver.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(tx.Nonce)),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator.Bytes(), structHash.Bytes())
+}
+
+// decodeExecTransaction decodes the SafeTx of execTransaction call data, nil if the data calls another method
+func decodeExecTransaction(data []byte) (*SafeTx, error) {
+	method := safeABI.Methods["execTransaction"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, nil //nolint:nilnil
+	}
+
+	values, err := unpackCanonical(method, data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	var args struct {
+		To             common.Address
+		Value          *big.Int
+		Data           []byte
+		Operation      uint8
+		SafeTxGas      *big.Int
+		BaseGas        *big.Int
+		GasPrice       *big.Int
+		GasToken       common.Address
+		RefundReceiver common.Address
+		Signatures     []byte
+	}
+	if err := method.Inputs.Copy(&args, values); err != nil {
+		return nil, fmt.Errorf("failed to copy execTransaction arguments: %w", err)
+	}
+
+	return &SafeTx{
+		To:             args.To,
+		Value:          args.Value,
+		Data:           args.Data,
+		Operation:      args.Operation,
+		SafeTxGas:      args.SafeTxGas,
+		BaseGas:        args.BaseGas,
+		GasPrice:       args.GasPrice,
+		GasToken:       args.GasToken,
+		RefundReceiver: args.RefundReceiver,
+	}, nil
+}
+
+// decodeMultiSend decodes the packed transactions of multiSend call data, nil if the data calls another method
+func decodeMultiSend(data []byte) ([]*SafeTx, error) {
+	method := safeABI.Methods["multiSend"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, nil
+	}
+
+	values, err := unpackCanonical(method, data[4:])
+	if err != nil {
+		return nil, err
+	}
+	packed, ok := values[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid multiSend transactions")
+	}
+
+	// every transaction is packed as operation(1) + to(20) + value(32) + data length(32) + data
+	var txs []*SafeTx
+	for offset := 0; offset < len(packed); {
+		if len(packed)-offset < 1+20+32+32 {
+			return nil, fmt.Errorf("multiSend transaction %d is truncated", len(txs))
+		}
+		tx := &SafeTx{
+			Operation: packed[offset],
+			To:        common.BytesToAddress(packed[offset+1 : offset+21]),
+			Value:     new(big.Int).SetBytes(packed[offset+21 : offset+53]),
+		}
+		dataLength := new(big.Int).SetBytes(packed[offset+53 : offset+85])
+		offset += 85
+		if !dataLength.IsUint64() || dataLength.Uint64() > uint64(len(packed)-offset) {
+			return nil, fmt.Errorf("multiSend transaction %d data length %v exceeds the batch", len(txs), dataLength)
+		}
+		tx.Data = packed[offset : offset+int(dataLength.Uint64())]
+		offset += len(tx.Data)
+		txs = append(txs, tx)
+	}
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("multiSend batch is empty")
+	}
+
+	return txs, nil
+}
+
+// safeCallTransfers unwraps the calls executed by a Safe, recursing into nested Safe and MultiSend calls,
+// and returns the transfers of the innermost calls
+func safeCallTransfers(chain string, safe common.Address, tx *SafeTx, depth int) ([]token_adapter.Transfer, error) {
+	if depth > maxSafeCallDepth {
+		return nil, fmt.Errorf("safe calls nested deeper than %d", maxSafeCallDepth)
+	}
+	// a gas refund pays the refund receiver, or the executor when it is zero, out of the Safe
+	if tx.GasPrice != nil && tx.GasPrice.Sign() != 0 {
+		return nil, fmt.Errorf("safe transaction refunds gas at price %v to %v", tx.GasPrice, tx.RefundReceiver.Hex())
+	}
+
+	switch tx.Operation {
+	case SafeOperationCall:
+	case SafeOperationDelegateCall:
+		// a delegate call runs code in the context of the Safe, only MultiSend batches are allowed
+		batch, err := decodeMultiSend(tx.Data)
+		if err != nil {
+			return nil, err
+		}
+		if batch == nil {
+			return nil, fmt.Errorf("delegate call to %v is not a multiSend", tx.To.Hex())
+		}
+		if tx.Value.Sign() != 0 {
+			return nil, fmt.Errorf("delegate call to %v with value %v", tx.To.Hex(), tx.Value)
+		}
+		// the MultiSend contract is a destination, as its code runs with the Safe permissions
+		transfers := []token_adapter.Transfer{{
+			Chain:  chain,
+			From:   safe.Hex(),
+			To:     tx.To.Hex(),
+			Amount: new(big.Int),
+		}}
+		for _, inner := range batch {
+			innerTransfers, err := safeCallTransfers(chain, safe, inner, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			transfers = append(transfers, innerTransfers...)
+		}
+		return transfers, nil
+	default:
+		return nil, fmt.Errorf("unknown safe operation %d", tx.Operation)
+	}
+
+	return callTransfers(chain, safe, tx.To, tx.Value, tx.Data, depth)
+}
+
+// callTransfers returns the transfers of a call from an account: the calls of a nested Safe,
+// an ERC-20 token call or a native transfer to the called address. The called address is always a destination,
+// so a nested Safe or a token contract is checked by the whitelist besides the recipients of its calls.
+func callTransfers(chain string, from, to common.Address, value *big.Int, data []byte, depth int) ([]token_adapter.Transfer, error) {
+	native := token_adapter.Transfer{
+		Chain:  chain,
+		From:   from.Hex(),
+		To:     to.Hex(),
+		Amount: new(big.Int).Set(value),
+	}
+
+	// a call to a nested Safe executes its own SafeTx
+	innerSafeTx, err := decodeExecTransaction(data)
+	if err != nil {
+		return nil, err
+	}
+	if innerSafeTx != nil {
+		innerTransfers, err := safeCallTransfers(chain, to, innerSafeTx, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return append([]token_adapter.Transfer{native}, innerTransfers...), nil
+	}
+
+	call, err := erc20.DecodeCall(data)
+	if err != nil {
+		// a plain value transfer or another contract call
+		return []token_adapter.Transfer{native}, nil
+	}
+	transfer := token_adapter.Transfer{
+		Chain:             chain,
+		Asset:             to.Hex(),
+		From:              from.Hex(),
+		To:                call.To.Hex(),
+		Amount:            call.Amount,
+		Method:            call.Method,
+		UnlimitedApproval: call.IsUnlimitedApproval(),
+	}
+	if call.Owner != nil {
+		transfer.From = call.Owner.Hex()
+	}
+	return []token_adapter.Transfer{native, transfer}, nil
+}
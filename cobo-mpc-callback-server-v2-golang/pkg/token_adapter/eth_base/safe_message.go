@@ -0,0 +1,116 @@
+package eth_base
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// safeTxPrimaryType is the EIP-712 primary type of a Safe transaction
+const safeTxPrimaryType = "SafeTx"
+
+// SafeMessage is a SafeTx signed by a Safe owner as an EIP-712 message
+type SafeMessage struct {
+	token   *Token
+	chain   string
+	chainID *big.Int
+	safe    common.Address
+	safeTx  *SafeTx
+}
+
+// GetHashes implements Transaction interface for a Safe message, the SafeTx hash is recomputed from the typed data
+func (m *SafeMessage) GetHashes() ([]string, error) {
+	return []string{SafeTxHash(m.chainID, m.safe, m.safeTx).String()}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for a Safe message
+func (m *SafeMessage) GetDestinationAddresses() ([]string, error) {
+	transfers, err := m.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for a Safe message, the calls executed by the Safe are unwrapped
+func (m *SafeMessage) GetTransfers() ([]token_adapter.Transfer, error) {
+	return safeCallTransfers(m.chain, m.safe, m.safeTx, 0)
+}
+
+// buildSafeMessage decodes the SafeTx and the Safe domain of the typed data
+func (t *Token) buildSafeMessage(txInfo *token_adapter.TransactionInfo, data *typedData) (*SafeMessage, error) {
+	chainID, err := typedBigInt(data.Domain, "chainId")
+	if err != nil {
+		return nil, fmt.Errorf("invalid safe domain: %w", err)
+	}
+	if t.chainID != nil && chainID.Cmp(t.chainID) != 0 {
+		return nil, fmt.Errorf("safe domain chain id %v mismatch chain id %v of token %v", chainID, t.chainID, t.tokenID)
+	}
+	safe, err := typedAddress(data.Domain, "verifyingContract")
+	if err != nil {
+		return nil, fmt.Errorf("invalid safe domain: %w", err)
+	}
+
+	safeTx, err := decodeTypedSafeTx(data.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid safe transaction: %w", err)
+	}
+
+	chain := txInfo.Transaction.GetChainId()
+	if chain == "" {
+		chain = defaultChainID
+	}
+	return &SafeMessage{
+		token:   t,
+		chain:   chain,
+		chainID: chainID,
+		safe:    safe,
+		safeTx:  safeTx,
+	}, nil
+}
+
+func decodeTypedSafeTx(message map[string]interface{}) (*SafeTx, error) {
+	var (
+		tx  SafeTx
+		err error
+	)
+	if tx.To, err = typedAddress(message, "to"); err != nil {
+		return nil, err
+	}
+	if tx.Value, err = typedBigInt(message, "value"); err != nil {
+		return nil, err
+	}
+	if tx.Data, err = typedBytes(message, "data"); err != nil {
+		return nil, err
+	}
+	operation, err := typedBigInt(message, "operation")
+	if err != nil {
+		return nil, err
+	}
+	if !operation.IsUint64() || operation.Uint64() > uint64(SafeOperationDelegateCall) {
+		return nil, fmt.Errorf("unknown safe operation %v", operation)
+	}
+	tx.Operation = uint8(operation.Uint64())
+	if tx.SafeTxGas, err = typedBigInt(message, "safeTxGas"); err != nil {
+		return nil, err
+	}
+	if tx.BaseGas, err = typedBigInt(message, "baseGas"); err != nil {
+		return nil, err
+	}
+	if tx.GasPrice, err = typedBigInt(message, "gasPrice"); err != nil {
+		return nil, err
+	}
+	if tx.GasToken, err = typedAddress(message, "gasToken"); err != nil {
+		return nil, err
+	}
+	if tx.RefundReceiver, err = typedAddress(message, "refundReceiver"); err != nil {
+		return nil, err
+	}
+	if tx.Nonce, err = typedBigInt(message, "nonce"); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
@@ -0,0 +1,327 @@
+package eth_base
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	safeAddress      = common.HexToAddress("0x5555555555555555555555555555555555555555")
+	multiSendAddress = common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D")
+	safeRecipient    = common.HexToAddress("0x0f76F604fd7762Bd94B48CA2523F69ab9665c97f")
+	usdtAddress      = common.HexToAddress(usdtContractAddress)
+)
+
+func erc20TransferData(to common.Address, amount int64) []byte {
+	data := crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(big.NewInt(amount).Bytes(), 32)...)
+}
+
+func multiSendData(t *testing.T, txs ...*SafeTx) []byte {
+	var packed []byte
+	for _, tx := range txs {
+		packed = append(packed, tx.Operation)
+		packed = append(packed, tx.To.Bytes()...)
+		packed = append(packed, math.U256Bytes(new(big.Int).Set(tx.Value))...)
+		packed = append(packed, math.U256Bytes(big.NewInt(int64(len(tx.Data))))...)
+		packed = append(packed, tx.Data...)
+	}
+	data, err := safeABI.Pack("multiSend", packed)
+	assert.NoError(t, err)
+	return data
+}
+
+func execTransactionData(t *testing.T, tx *SafeTx) []byte {
+	gasPrice := tx.GasPrice
+	if gasPrice == nil {
+		gasPrice = big.NewInt(0)
+	}
+	data, err := safeABI.Pack("execTransaction", tx.To, tx.Value, tx.Data, tx.Operation,
+		big.NewInt(0), big.NewInt(0), gasPrice, common.Address{}, tx.RefundReceiver, []byte{0x01})
+	assert.NoError(t, err)
+	return data
+}
+
+// batchSafeTx is a MultiSend batch of a native transfer and a USDT transfer
+func batchSafeTx(t *testing.T) *SafeTx {
+	return &SafeTx{
+		To:        multiSendAddress,
+		Value:     big.NewInt(0),
+		Operation: SafeOperationDelegateCall,
+		Data: multiSendData(t,
+			&SafeTx{Operation: SafeOperationCall, To: safeRecipient, Value: big.NewInt(1000)},
+			&SafeTx{Operation: SafeOperationCall, To: usdtAddress, Value: big.NewInt(0), Data: erc20TransferData(safeRecipient, 50000000)},
+		),
+	}
+}
+
+func batchTransfers(chain string) []token_adapter.Transfer {
+	return []token_adapter.Transfer{
+		{Chain: chain, From: safeAddress.Hex(), To: multiSendAddress.Hex(), Amount: big.NewInt(0)},
+		{Chain: chain, From: safeAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(1000)},
+		{Chain: chain, From: safeAddress.Hex(), To: usdtAddress.Hex(), Amount: big.NewInt(0)},
+		{Chain: chain, Asset: usdtAddress.Hex(), From: safeAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(50000000), Method: "transfer"},
+	}
+}
+
+func TestTransaction_GetTransfers_Safe(t *testing.T) {
+	nestedSafe := common.HexToAddress("0x6666666666666666666666666666666666666666")
+
+	tests := []struct {
+		name          string
+		safeTx        *SafeTx
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:   "Call",
+			safeTx: &SafeTx{To: safeRecipient, Value: big.NewInt(1000), Operation: SafeOperationCall},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "ETH", From: safeAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(1000)},
+			},
+		},
+		{
+			name:          "MultiSend batch",
+			safeTx:        batchSafeTx(t),
+			wantTransfers: batchTransfers("ETH"),
+		},
+		{
+			name: "Nested safe",
+			safeTx: &SafeTx{
+				To:        nestedSafe,
+				Value:     big.NewInt(0),
+				Operation: SafeOperationCall,
+				Data:      execTransactionData(t, &SafeTx{To: safeRecipient, Value: big.NewInt(7), Operation: SafeOperationCall}),
+			},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "ETH", From: safeAddress.Hex(), To: nestedSafe.Hex(), Amount: big.NewInt(0)},
+				{Chain: "ETH", From: nestedSafe.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(7)},
+			},
+		},
+		{
+			name: "Gas refund",
+			safeTx: &SafeTx{
+				To:             safeRecipient,
+				Value:          big.NewInt(1000),
+				Operation:      SafeOperationCall,
+				GasPrice:       big.NewInt(1),
+				RefundReceiver: common.HexToAddress("0x7777777777777777777777777777777777777777"),
+			},
+			wantError: true,
+		},
+		{
+			name: "Delegate call to another contract",
+			safeTx: &SafeTx{
+				To:        multiSendAddress,
+				Value:     big.NewInt(0),
+				Operation: SafeOperationDelegateCall,
+				Data:      erc20TransferData(safeRecipient, 1),
+			},
+			wantError: true,
+		},
+		{
+			name: "Truncated MultiSend batch",
+			safeTx: &SafeTx{
+				To:        multiSendAddress,
+				Value:     big.NewInt(0),
+				Operation: SafeOperationDelegateCall,
+				Data:      func() []byte { data, _ := safeABI.Pack("multiSend", []byte{0, 1, 2}); return data }(),
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := &Transaction{
+				token:                  &Token{tokenID: "ETH"},
+				PrepareTransactionData: &PrepareTransactionData{sourceAddress: "0x1111111111111111111111111111111111111111"},
+				tx: types.NewTx(&types.DynamicFeeTx{
+					ChainID: big.NewInt(1),
+					To:      &safeAddress,
+					Value:   big.NewInt(0),
+					Data:    execTransactionData(t, tt.safeTx),
+				}),
+			}
+
+			transfers, err := transaction.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, transfers)
+			} else {
+				// the Safe itself is a destination as well as the calls it executes
+				safeCall := token_adapter.Transfer{
+					Chain: "ETH", From: "0x1111111111111111111111111111111111111111", To: safeAddress.Hex(), Amount: big.NewInt(0),
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, append([]token_adapter.Transfer{safeCall}, tt.wantTransfers...), transfers)
+			}
+
+			// the call is the execTransaction of the Safe whatever the Safe executes
+			call, err := transaction.GetCall()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Transfer{
+				Chain:  "ETH",
+				From:   "0x1111111111111111111111111111111111111111",
+				To:     safeAddress.Hex(),
+				Amount: big.NewInt(0),
+			}, call)
+		})
+	}
+}
+
+func TestTotalAmount_MultiSend(t *testing.T) {
+	approveData := crypto.Keccak256([]byte("approve(address,uint256)"))[:4]
+	approveData = append(approveData, common.LeftPadBytes(safeRecipient.Bytes(), 32)...)
+	approveData = append(approveData, math.U256Bytes(big.NewInt(900000000))...)
+
+	// a MultiSend batch of ETH, a USDT transfer and a USDT approval
+	safeTx := &SafeTx{
+		To:        multiSendAddress,
+		Value:     big.NewInt(0),
+		Operation: SafeOperationDelegateCall,
+		Data: multiSendData(t,
+			&SafeTx{Operation: SafeOperationCall, To: safeRecipient, Value: big.NewInt(1000)},
+			&SafeTx{Operation: SafeOperationCall, To: usdtAddress, Value: big.NewInt(0), Data: erc20TransferData(safeRecipient, 50000000)},
+			&SafeTx{Operation: SafeOperationCall, To: usdtAddress, Value: big.NewInt(0), Data: approveData},
+		),
+	}
+	transaction := &Transaction{
+		token:                  &Token{tokenID: "ETH"},
+		PrepareTransactionData: &PrepareTransactionData{},
+		tx: types.NewTx(&types.DynamicFeeTx{
+			ChainID: big.NewInt(1),
+			To:      &safeAddress,
+			Value:   big.NewInt(0),
+			Data:    execTransactionData(t, safeTx),
+		}),
+	}
+
+	transfers, err := transaction.GetTransfers()
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 7)
+
+	// ETH is not summed with the USDT amounts, and the approval moves no USDT
+	assert.Equal(t, big.NewInt(1000), token_adapter.TotalAmount(transfers, ""))
+	assert.Equal(t, big.NewInt(50000000), token_adapter.TotalAmount(transfers, usdtContractAddress))
+	assert.Equal(t, big.NewInt(50000000), token_adapter.TotalAmount(transfers, strings.ToLower(usdtContractAddress)))
+}
+
+func safeTypedData(chainID int64, safeTx *SafeTx) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}},
+			"SafeTx": {
+				{Name: "to", Type: "address"}, {Name: "value", Type: "uint256"}, {Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"}, {Name: "safeTxGas", Type: "uint256"}, {Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"}, {Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"}, {Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain: apitypes.TypedDataDomain{
+			ChainId:           math.NewHexOrDecimal256(chainID),
+			VerifyingContract: safeAddress.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to":             safeTx.To.Hex(),
+			"value":          safeTx.Value.String(),
+			"data":           hexutil.Encode(safeTx.Data),
+			"operation":      float64(safeTx.Operation),
+			"safeTxGas":      "0",
+			"baseGas":        "0",
+			"gasPrice":       "0",
+			"gasToken":       common.Address{}.Hex(),
+			"refundReceiver": common.Address{}.Hex(),
+			"nonce":          "42",
+		},
+	}
+}
+
+func TestToken_BuildTransaction_SafeMessage(t *testing.T) {
+	safeTx := batchSafeTx(t)
+	typedData := safeTypedData(1, safeTx)
+	wantHash, _, err := apitypes.TypedDataAndHash(typedData)
+	assert.NoError(t, err)
+
+	raw, err := json.Marshal(typedData)
+	assert.NoError(t, err)
+	rawStructuredData := string(raw)
+	var structuredData map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw, &structuredData))
+
+	tests := []struct {
+		name      string
+		token     *Token
+		raw       *string
+		wantError bool
+	}{
+		{
+			name:  "Raw structured data",
+			token: &Token{tokenID: "ETH"},
+			raw:   &rawStructuredData,
+		},
+		{
+			name:  "Structured data",
+			token: &Token{tokenID: "ETH", chainID: big.NewInt(1)},
+		},
+		{
+			name:      "Domain of another chain",
+			token:     &Token{tokenID: "BSC_BNB", chainID: big.NewInt(56)},
+			wantError: true,
+		},
+		{
+			name:      "ERC20 token",
+			token:     &Token{tokenID: "ETH_USDT", erc20Token: true},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chainID := "ETH"
+			tx, err := tt.token.BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					ChainId: &chainID,
+					Destination: coboWaaS2.TransactionMessageSignEIP712DestinationAsTransactionDestination(
+						&coboWaaS2.TransactionMessageSignEIP712Destination{
+							RawStructuredData: tt.raw,
+							StructuredData:    structuredData,
+						},
+					),
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{hexutil.Encode(wantHash)}, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, batchTransfers("ETH"), transfers)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{multiSendAddress.Hex(), safeRecipient.Hex(), usdtAddress.Hex(), safeRecipient.Hex()}, addresses)
+		})
+	}
+}
@@ -2,44 +2,126 @@ package eth_base
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type Token struct {
 	tokenID    string
 	erc20Token bool
+	// chainID is enforced on the raw transaction when set
+	chainID *big.Int
+	// contractAddress is the expected ERC-20 contract, the contract registered for the token id when nil
+	contractAddress *common.Address
+	// entryPoint verifies ERC-4337 user operations when set
+	entryPoint *EntryPoint
 }
 
+// mainnetChainID is the chain id of the built-in ETH and ETH_USDT tokens, the tokens of other chains are declared in config
+const mainnetChainID = 1
+
+// NewToken returns the ETH token of the Ethereum mainnet
 func NewToken(tokenID string) token_adapter.Token {
 	return &Token{
 		tokenID:    tokenID,
 		erc20Token: false,
+		chainID:    big.NewInt(mainnetChainID),
 	}
 }
 
+// NewErc20Token returns an ERC-20 token of the Ethereum mainnet, bound to the contract registered for the token id
 func NewErc20Token(tokenID string) token_adapter.Token {
 	return &Token{
 		tokenID:    tokenID,
 		erc20Token: true,
+		chainID:    big.NewInt(mainnetChainID),
 	}
 }
 
 func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	message, err := t.buildMessage(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare message error: %w", err)
+	}
+	if message != nil {
+		return message, nil
+	}
+
 	preTxData, err := prepareBuildTransactionData(txInfo)
 	if err != nil {
 		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
 	}
 
+	// a user operation of an ERC-4337 account is sent as JSON instead of an RLP transaction
+	if isUserOperation(preTxData.rawTx) {
+		userOpTx, err := t.buildUserOperation(preTxData)
+		if err != nil {
+			return nil, fmt.Errorf("prepare user operation error: %w", err)
+		}
+		return userOpTx, nil
+	}
+
 	tx, err := ParseEthTransaction(preTxData.rawTx)
 	if err != nil {
 		return nil, fmt.Errorf("prepare eth transaction error: %w", err)
 	}
 
+	if err := t.checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
 	return &Transaction{tx: tx, PrepareTransactionData: preTxData, token: t}, nil
 }
 
+// checkTransaction rejects a raw transaction of another chain or to another ERC-20 contract than the token
+func (t *Token) checkTransaction(tx *types.Transaction) error {
+	if t.chainID != nil {
+		chainID, err := TransactionChainID(tx)
+		if err != nil {
+			return err
+		}
+		if chainID.Cmp(t.chainID) != 0 {
+			return fmt.Errorf("transaction chain id %v mismatch chain id %v of token %v", chainID, t.chainID, t.tokenID)
+		}
+		// an authorization of chain id 0 is valid on every chain
+		for _, auth := range tx.SetCodeAuthorizations() {
+			if !auth.ChainID.IsZero() && auth.ChainID.ToBig().Cmp(t.chainID) != 0 {
+				return fmt.Errorf("authorization chain id %v mismatch chain id %v of token %v", auth.ChainID.ToBig(), t.chainID, t.tokenID)
+			}
+		}
+	}
+
+	if t.erc20Token {
+		contractAddress, err := t.expectedContract()
+		if err != nil {
+			return err
+		}
+		if tx.To() == nil || *tx.To() != contractAddress {
+			return fmt.Errorf("transaction contract %v mismatch contract %v of token %v", tx.To(), contractAddress.Hex(), t.tokenID)
+		}
+	}
+
+	return nil
+}
+
+// expectedContract returns the ERC-20 contract bound to the token, a token without a contract is rejected
+func (t *Token) expectedContract() (common.Address, error) {
+	if t.contractAddress != nil {
+		return *t.contractAddress, nil
+	}
+	contract, ok := token_adapter.GetTokenContract(t.tokenID)
+	if !ok {
+		return common.Address{}, fmt.Errorf("contract of token %v is not registered", t.tokenID)
+	}
+	if !common.IsHexAddress(contract) {
+		return common.Address{}, fmt.Errorf("invalid contract address %q of token %v", contract, t.tokenID)
+	}
+	return common.HexToAddress(contract), nil
+}
+
 func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
 	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
 		return nil, fmt.Errorf("transaction info raw tx is nil")
@@ -47,6 +129,14 @@ func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *P
 
 	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
 	rawTxBytes := common.FromHex(rawTx)
+	if isUserOperation([]byte(rawTx)) {
+		rawTxBytes = []byte(rawTx)
+	}
+
+	data = &PrepareTransactionData{rawTx: rawTxBytes, chainID: txInfo.Transaction.GetChainId()}
+	if len(txInfo.SourceAddresses) > 0 {
+		data.sourceAddress = txInfo.SourceAddresses[0].Address
+	}
 
-	return &PrepareTransactionData{rawTx: rawTxBytes}, nil
+	return data, nil
 }
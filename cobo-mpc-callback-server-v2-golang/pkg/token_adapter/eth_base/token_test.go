@@ -1,9 +1,11 @@
 package eth_base
 
 import (
-	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"math/big"
 	"testing"
 
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,6 +16,7 @@ func TestNewEthToken(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "ETH", ethToken.tokenID)
 	assert.False(t, ethToken.erc20Token)
+	assert.Equal(t, big.NewInt(1), ethToken.chainID)
 }
 
 func TestNewErc20Token(t *testing.T) {
@@ -22,9 +25,13 @@ func TestNewErc20Token(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "ETH_USDT", erc20Token.tokenID)
 	assert.True(t, erc20Token.erc20Token)
+	assert.Equal(t, big.NewInt(1), erc20Token.chainID)
 }
 
 func TestEthBaseToken_BuildTransaction(t *testing.T) {
+	// ethRawTx signed for chain id 56
+	bscRawTx := "0xe980842ffee68f825208940f76f604fd7762bd94b48ca2523f69ab9665c97f865af3107a400080388080"
+
 	tests := []struct {
 		name      string
 		txInfo    *token_adapter.TransactionInfo
@@ -42,7 +49,7 @@ func TestEthBaseToken_BuildTransaction(t *testing.T) {
 			wantError: false,
 		},
 		{
-			name: "Valid eip1559 ETH transaction",
+			name: "Sepolia eip1559 transaction",
 			txInfo: &token_adapter.TransactionInfo{
 				Transaction: &coboWaaS2.Transaction{
 					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
@@ -50,7 +57,18 @@ func TestEthBaseToken_BuildTransaction(t *testing.T) {
 					},
 				},
 			},
-			wantError: false,
+			wantError: true,
+		},
+		{
+			name: "BSC transaction",
+			txInfo: &token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+						UnsignedRawTx: &bscRawTx,
+					},
+				},
+			},
+			wantError: true,
 		},
 		{
 			name: "Valid erc20 ETH transaction",
@@ -95,3 +113,44 @@ func TestEthBaseToken_BuildTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestErc20Token_ContractBinding(t *testing.T) {
+	if _, ok := token_adapter.GetTokenContract("ETH_USDT"); !ok {
+		assert.NoError(t, token_adapter.RegisterTokenContract("ETH_USDT", usdtContractAddress))
+	}
+
+	tests := []struct {
+		name      string
+		tokenID   string
+		wantError bool
+	}{
+		{
+			name:    "Registered contract",
+			tokenID: "ETH_USDT",
+		},
+		{
+			name:      "Unregistered contract",
+			tokenID:   "ETH_UNKNOWN",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewErc20Token(tt.tokenID).BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{
+						UnsignedRawTx: &erc20RawTx,
+					},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, tx)
+			}
+		})
+	}
+}
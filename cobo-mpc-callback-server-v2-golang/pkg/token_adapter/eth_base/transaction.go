@@ -5,13 +5,16 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/erc20"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/rlp"
 	"golang.org/x/crypto/sha3"
 )
 
+const defaultChainID = "ETH"
+
 // hashPool holds LegacyKeccak256 hash for rlpHash.
 var hashPool = sync.Pool{
 	New: func() interface{} { return sha3.NewLegacyKeccak256() },
@@ -24,86 +27,158 @@ type Transaction struct {
 }
 
 type PrepareTransactionData struct {
-	rawTx []byte
+	rawTx         []byte
+	chainID       string
+	sourceAddress string
 }
 
 // GetHashes implements Transaction interface for Ethereum
 func (t *Transaction) GetHashes() ([]string, error) {
-	var hashes []string
-	h, err := EthHash(t.rawTx)
-	if err != nil {
-		return nil, fmt.Errorf("calc eth hash error: %w", err)
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction is nil")
 	}
-	hashes = append(hashes, h.String())
-	return hashes, nil
+
+	return []string{SigningHash(t.tx).String()}, nil
 }
 
 // GetDestinationAddresses implements Transaction interface for Ethereum
 func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := token_adapter.DestinationAddresses(transfers)
+	// an EIP-7702 authorization delegates the signer account to the contract code
+	for _, auth := range t.tx.SetCodeAuthorizations() {
+		addresses = append(addresses, auth.Address.Hex())
+	}
+	return addresses, nil
+}
+
+// GetTransfers implements Transaction interface for Ethereum
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
 	if t.tx == nil {
 		return nil, fmt.Errorf("transaction is nil")
 	}
 
-	var addresses []string
+	transfer := token_adapter.Transfer{
+		Chain: t.chainID,
+		From:  t.sourceAddress,
+	}
+	if transfer.Chain == "" {
+		transfer.Chain = defaultChainID
+	}
 
 	if t.token.erc20Token {
-		// parse ERC20 transfer destination addresses
-		// ERC20 transfer method ID: 0xa9059cbb
-		input := t.tx.Data()
-		if len(input) < 4+32 { // method(4) + address(32)
-			return nil, fmt.Errorf("invalid ERC20 transfer data length")
+		if t.tx.To() == nil {
+			return nil, fmt.Errorf("ERC20 transfer contract address is nil")
 		}
-
-		// check method ID: transfer
-		methodID := input[:4]
-		if common.Bytes2Hex(methodID) != "a9059cbb" {
-			return nil, fmt.Errorf("not an ERC20 transfer method")
+		call, err := erc20.DecodeCall(t.tx.Data())
+		if err != nil {
+			return nil, err
 		}
-
-		// parse destination addresses
-		addressBytes := input[4:36]
-		address := common.BytesToAddress(addressBytes)
-		addresses = append(addresses, address.Hex())
-
+		transfer.Asset = t.tx.To().Hex()
+		if call.Owner != nil {
+			transfer.From = call.Owner.Hex()
+		}
+		transfer.To = call.To.Hex()
+		transfer.Amount = call.Amount
+		transfer.Method = call.Method
+		transfer.UnlimitedApproval = call.IsUnlimitedApproval()
 	} else {
 		// eth transfer
-		if to := t.tx.To(); to != nil {
-			addresses = append(addresses, to.Hex())
-		} else {
-			return []string{}, nil
+		to := t.tx.To()
+		if to == nil {
+			return []token_adapter.Transfer{}, nil
+		}
+		transfer.To = to.Hex()
+		transfer.Amount = new(big.Int).Set(t.tx.Value())
+
+		// a Safe execTransaction is unwrapped to the calls executed by the Safe, the called address is kept as a
+		// destination as nothing but the whitelist tells it is a Safe
+		safeTx, err := decodeExecTransaction(t.tx.Data())
+		if err != nil {
+			return nil, err
+		}
+		if safeTx != nil {
+			innerTransfers, err := safeCallTransfers(transfer.Chain, *to, safeTx, 0)
+			if err != nil {
+				return nil, err
+			}
+			return append([]token_adapter.Transfer{transfer}, innerTransfers...), nil
 		}
 	}
 
-	return addresses, nil
+	return []token_adapter.Transfer{transfer}, nil
+}
+
+// GetCall implements CallTransaction interface for Ethereum, the call is the transaction itself
+// while GetTransfers unwraps a Safe execTransaction or an ERC-20 call
+func (t *Transaction) GetCall() (*token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+	if t.tx.To() == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	chain := t.chainID
+	if chain == "" {
+		chain = defaultChainID
+	}
+	return &token_adapter.Transfer{
+		Chain:  chain,
+		From:   t.sourceAddress,
+		To:     t.tx.To().Hex(),
+		Amount: new(big.Int).Set(t.tx.Value()),
+	}, nil
 }
 
+// GetFee implements FeeTransaction interface for Ethereum
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+
+	fee := &token_adapter.Fee{
+		GasLimit: new(big.Int).SetUint64(t.tx.Gas()),
+		GasPrice: new(big.Int).Set(t.tx.GasFeeCap()),
+	}
+	if t.tx.Type() != types.LegacyTxType && t.tx.Type() != types.AccessListTxType {
+		fee.GasTipCap = new(big.Int).Set(t.tx.GasTipCap())
+	}
+
+	return fee, nil
+}
+
+// ParseEthTransaction decodes an unsigned legacy, EIP-2930, EIP-1559, EIP-4844 or EIP-7702 transaction
 func ParseEthTransaction(rawTx []byte) (*types.Transaction, error) {
 	if len(rawTx) < 2 {
 		return nil, fmt.Errorf("parse raw tx length too short")
 	}
-	newRawTx := rawTx
-	if rawTx[0] == 0x02 {
-		// eip25519
-		firstByte := rawTx[0]
 
-		var fields []interface{}
-		err := rlp.DecodeBytes(rawTx[1:], &fields)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode Bytes raw tx %x : %w", rawTx[1:], err)
-		}
-		fields = append(fields, big.NewInt(0), big.NewInt(0), big.NewInt(0))
-		newRawTx, err = rlp.EncodeToBytes(fields)
-		if err != nil {
-			return nil, fmt.Errorf("failed to encode Bytes to raw tx: %w", err)
-		}
-		newRawTx = append([]byte{firstByte}, newRawTx...)
+	inner, err := decodeUnsignedTx(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode raw tx %x : %w", rawTx, err)
+	}
+	return types.NewTx(inner), nil
+}
+
+// TransactionChainID returns the chain id signed by the transaction
+func TransactionChainID(tx *types.Transaction) (*big.Int, error) {
+	if tx.Type() == types.LegacyTxType && !tx.Protected() {
+		return nil, fmt.Errorf("legacy transaction without EIP-155 chain id")
 	}
+	return tx.ChainId(), nil
+}
 
-	tx := &types.Transaction{}
-	if err := tx.UnmarshalBinary(newRawTx); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal raw tx %x : %w", rawTx, err)
+// SigningHash recomputes the signing hash of the decoded transaction by its envelope type
+func SigningHash(tx *types.Transaction) common.Hash {
+	if tx.Type() == types.LegacyTxType && !tx.Protected() {
+		return types.HomesteadSigner{}.Hash(tx)
 	}
-	return tx, nil
+	return types.LatestSignerForChainID(tx.ChainId()).Hash(tx)
 }
 
 func EthHash(x []byte) (h common.Hash, err error) {
@@ -1,9 +1,12 @@
 package eth_base
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +25,25 @@ var (
 	erc20RawTx        = "0xf86a04850127efef2283016f5b94dac17f958d2ee523a2206206994597c13d831ec780b844a9059cbb0000000000000000000000008b45b84e2cf29e5f826797df7e1aa93fc71a2bfd0000000000000000000000000000000000000000000000000000000002faf080018080"
 	erc20RawTxHash    = "0xf8d61123554ede2f342c56b4da018ec640c7ded1574a7417f0a0396aba664007"
 	erc20RawTxDesAddr = "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd"
+
+	// ERC20 unlimited approve raw transaction data (method ID: 0x095ea7b3), the spender is erc20RawTxDesAddr
+	erc20ApproveRawTx = "0xf86a05850127efef2283016f5b94dac17f958d2ee523a2206206994597c13d831ec780b844095ea7b30000000000000000000000008b45b84e2cf29e5f826797df7e1aa93fc71a2bfdffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff018080"
+
+	// ERC20 transferFrom raw transaction data (method ID: 0x23b872dd), from 0x2222222222222222222222222222222222222222
+	erc20TransferFromRawTx = "0xf88a05850127efef2283016f5b94dac17f958d2ee523a2206206994597c13d831ec780b86423b872dd00000000000000000000000022222222222222222222222222222222222222220000000000000000000000008b45b84e2cf29e5f826797df7e1aa93fc71a2bfd0000000000000000000000000000000000000000000000000000000002faf080018080"
+
+	// EIP-2930 access list transfer raw transaction data
+	ethAccessListRawTx = "0x01f8620101843b9aca00827530940f76f604fd7762bd94b48ca2523f69ab9665c97f87038d7ea4c6800080f838f794dac17f958d2ee523a2206206994597c13d831ec7e1a00100000000000000000000000000000000000000000000000000000000000000"
+
+	// EIP-7702 set code raw transaction data, delegating to 0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B
+	ethSetCodeRawTx    = "0x04f8450102843b9aca008504a817c800830186a0940f76f604fd7762bd94b48ca2523f69ab9665c97f8080c0dbda019463c0c19a282a1b52b07dd5a65b58948a07dae32b03800102"
+	ethSetCodeDelegate = "0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B"
+
+	// EIP-7702 set code raw transaction data, with an authorization of chain id 5
+	ethSetCodeAuthChain = "0x04f8450102843b9aca008504a817c800830186a0940f76f604fd7762bd94b48ca2523f69ab9665c97f8080c0dbda059463c0c19a282a1b52b07dd5a65b58948a07dae32b03800102"
+
+	// pre EIP-155 legacy transfer raw transaction data
+	ethPreEip155RawTx = "0xe080843b9aca00825208940f76f604fd7762bd94b48ca2523f69ab9665c97f0180"
 )
 
 func TestEthBaseTransaction_GetHashes(t *testing.T) {
@@ -200,3 +222,207 @@ func TestEthHash(t *testing.T) {
 		})
 	}
 }
+
+func TestEthBaseTransaction_GetTransfers(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		isERC20       bool
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:    "ETH transfer",
+			rawTx:   ethRawTx,
+			isERC20: false,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "ETH",
+				From:   "0x1111111111111111111111111111111111111111",
+				To:     ethRawTxDesAddr,
+				Amount: big.NewInt(100000000000000),
+			}},
+		},
+		{
+			name:    "ERC20 transfer",
+			rawTx:   erc20RawTx,
+			isERC20: true,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "ETH",
+				Asset:  "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+				From:   "0x1111111111111111111111111111111111111111",
+				To:     erc20RawTxDesAddr,
+				Amount: big.NewInt(50000000),
+				Method: "transfer",
+			}},
+		},
+		{
+			name:    "ERC20 unlimited approve",
+			rawTx:   erc20ApproveRawTx,
+			isERC20: true,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:             "ETH",
+				Asset:             "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+				From:              "0x1111111111111111111111111111111111111111",
+				To:                erc20RawTxDesAddr,
+				Amount:            math.MaxBig256,
+				Method:            "approve",
+				UnlimitedApproval: true,
+			}},
+		},
+		{
+			name:    "ERC20 transferFrom",
+			rawTx:   erc20TransferFromRawTx,
+			isERC20: true,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "ETH",
+				Asset:  "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+				From:   "0x2222222222222222222222222222222222222222",
+				To:     erc20RawTxDesAddr,
+				Amount: big.NewInt(50000000),
+				Method: "transferFrom",
+			}},
+		},
+		{
+			name:      "ETH transfer as ERC20",
+			rawTx:     ethRawTx,
+			isERC20:   true,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTxBytes := common.FromHex(tt.rawTx)
+			tx, err := ParseEthTransaction(rawTxBytes)
+			assert.NoError(t, err)
+
+			transaction := &Transaction{
+				token: &Token{
+					tokenID:    "TEST",
+					erc20Token: tt.isERC20,
+				},
+				PrepareTransactionData: &PrepareTransactionData{
+					rawTx:         rawTxBytes,
+					sourceAddress: "0x1111111111111111111111111111111111111111",
+				},
+				tx: tx,
+			}
+
+			transfers, err := transaction.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTransfers, transfers)
+			}
+		})
+	}
+}
+
+func TestTransactionChainID(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawTx       []byte
+		wantChainID *big.Int
+	}{
+		{
+			name:        "Legacy EIP-155 transaction",
+			rawTx:       common.FromHex(ethRawTx),
+			wantChainID: big.NewInt(1),
+		},
+		{
+			name:        "EIP-1559 transaction",
+			rawTx:       common.FromHex(ethEip1559RawTx),
+			wantChainID: big.NewInt(11155111),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := ParseEthTransaction(tt.rawTx)
+			assert.NoError(t, err)
+
+			chainID, err := TransactionChainID(tx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantChainID, chainID)
+		})
+	}
+}
+
+func TestParseEthTransaction_Envelopes(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantType      uint8
+		wantAddresses []string
+		wantError     bool
+	}{
+		{
+			name:          "Legacy EIP-155 transaction",
+			rawTx:         ethRawTx,
+			wantType:      types.LegacyTxType,
+			wantAddresses: []string{ethRawTxDesAddr},
+		},
+		{
+			name:          "Pre EIP-155 legacy transaction",
+			rawTx:         ethPreEip155RawTx,
+			wantType:      types.LegacyTxType,
+			wantAddresses: []string{ethRawTxDesAddr},
+		},
+		{
+			name:          "EIP-2930 transaction",
+			rawTx:         ethAccessListRawTx,
+			wantType:      types.AccessListTxType,
+			wantAddresses: []string{ethRawTxDesAddr},
+		},
+		{
+			name:          "EIP-1559 transaction",
+			rawTx:         ethEip1559RawTx,
+			wantType:      types.DynamicFeeTxType,
+			wantAddresses: []string{"0x47f95183e513da1c599E76611DCB00304d07b6A0"},
+		},
+		{
+			name:          "EIP-7702 transaction",
+			rawTx:         ethSetCodeRawTx,
+			wantType:      types.SetCodeTxType,
+			wantAddresses: []string{ethRawTxDesAddr, ethSetCodeDelegate},
+		},
+		{
+			name:      "Signed legacy transaction",
+			rawTx:     "0xe380843b9aca00825208940f76f604fd7762bd94b48ca2523f69ab9665c97f0180010506",
+			wantError: true,
+		},
+		{
+			name:      "Unsupported transaction type",
+			rawTx:     "0x05c0",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTxBytes := common.FromHex(tt.rawTx)
+			tx, err := ParseEthTransaction(rawTxBytes)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantType, tx.Type())
+
+			// the signing hash recomputed from the decoded fields is the hash of the unsigned payload
+			rawTxHash, err := EthHash(rawTxBytes)
+			assert.NoError(t, err)
+			assert.Equal(t, rawTxHash, SigningHash(tx))
+
+			ethTx := &Transaction{
+				tx:                     tx,
+				PrepareTransactionData: &PrepareTransactionData{rawTx: rawTxBytes},
+				token:                  &Token{tokenID: "ETH"},
+			}
+			addresses, err := ethTx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAddresses, addresses)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package eth_base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// typedData is the EIP-712 typed data of a message sign request
+type typedData struct {
+	PrimaryType string                 `json:"primaryType"`
+	Domain      map[string]interface{} `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+// parseTypedData decodes the typed data of an EIP-712 destination, numbers are kept as json.Number
+func parseTypedData(destination *coboWaaS2.TransactionMessageSignEIP712Destination) (*typedData, error) {
+	raw, err := typedDataJSON(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	data := new(typedData)
+	if err := decoder.Decode(data); err != nil {
+		return nil, fmt.Errorf("failed to parse structured data: %w", err)
+	}
+	return data, nil
+}
+
+// typedDataJSON returns the JSON typed data of an EIP-712 destination, the raw structured data if present
+func typedDataJSON(destination *coboWaaS2.TransactionMessageSignEIP712Destination) ([]byte, error) {
+	if destination.RawStructuredData != nil && *destination.RawStructuredData != "" {
+		return []byte(*destination.RawStructuredData), nil
+	}
+	raw, err := json.Marshal(destination.StructuredData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal structured data: %w", err)
+	}
+	return raw, nil
+}
+
+// typedBigInt decodes an integer field given as a JSON number, a decimal string or a hex string
+func typedBigInt(fields map[string]interface{}, name string) (*big.Int, error) {
+	var text string
+	switch v := fields[name].(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = v
+	case nil:
+		return nil, fmt.Errorf("typed data field %v is missing", name)
+	default:
+		return nil, fmt.Errorf("typed data field %v is not an integer", name)
+	}
+
+	value, ok := new(big.Int).SetString(text, 0)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("typed data field %v %q is not an unsigned integer", name, text)
+	}
+	return value, nil
+}
+
+// typedAddress decodes an address field
+func typedAddress(fields map[string]interface{}, name string) (common.Address, error) {
+	text, ok := fields[name].(string)
+	if !ok || !common.IsHexAddress(text) {
+		return common.Address{}, fmt.Errorf("typed data field %v is not an address", name)
+	}
+	return common.HexToAddress(text), nil
+}
+
+// typedBytes decodes a hex bytes field
+func typedBytes(fields map[string]interface{}, name string) ([]byte, error) {
+	text, ok := fields[name].(string)
+	if !ok {
+		return nil, fmt.Errorf("typed data field %v is not bytes", name)
+	}
+	if text == "" || strings.EqualFold(text, "0x") {
+		return []byte{}, nil
+	}
+	value, err := hexutil.Decode(text)
+	if err != nil {
+		return nil, fmt.Errorf("typed data field %v is not hex bytes: %w", name, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,434 @@
+package eth_base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/erc20"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ERC-4337 EntryPoint versions
+const (
+	EntryPointV06 = "0.6"
+	EntryPointV07 = "0.7"
+)
+
+// entryPointVersions are the versions of the canonical EntryPoint deployments
+var entryPointVersions = map[common.Address]string{
+	common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"): EntryPointV06,
+	common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032"): EntryPointV07,
+}
+
+// accountABIJSON holds the execute methods of the SimpleAccount of v0.6 and v0.7
+const accountABIJSON = `[
+	{"type":"function","name":"execute","stateMutability":"nonpayable","inputs":[
+		{"name":"dest","type":"address"},{"name":"value","type":"uint256"},{"name":"func","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"executeBatch","stateMutability":"nonpayable","inputs":[
+		{"name":"dest","type":"address[]"},{"name":"func","type":"bytes[]"}],"outputs":[]},
+	{"type":"function","name":"executeBatch","stateMutability":"nonpayable","inputs":[
+		{"name":"dest","type":"address[]"},{"name":"value","type":"uint256[]"},{"name":"func","type":"bytes[]"}],"outputs":[]}
+]`
+
+var (
+	accountABI = mustParseABI(accountABIJSON)
+
+	// maxUint128 bounds the gas fields packed in pairs by a v0.7 EntryPoint
+	maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+)
+
+// EntryPoint is the ERC-4337 EntryPoint contract of a token
+type EntryPoint struct {
+	Address common.Address
+	// Version is EntryPointV06 or EntryPointV07
+	Version string
+}
+
+// NewEntryPoint returns the EntryPoint of an address, the version of a canonical deployment is used when version is empty
+func NewEntryPoint(address, version string) (*EntryPoint, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid entry point address %q", address)
+	}
+	entryPoint := &EntryPoint{Address: common.HexToAddress(address), Version: version}
+	if entryPoint.Version == "" {
+		entryPoint.Version = entryPointVersions[entryPoint.Address]
+	}
+
+	switch entryPoint.Version {
+	case EntryPointV06, EntryPointV07:
+		return entryPoint, nil
+	case "":
+		return nil, fmt.Errorf("version of entry point %v is empty", address)
+	default:
+		return nil, fmt.Errorf("unsupported version %q of entry point %v", entryPoint.Version, address)
+	}
+}
+
+// UserOperation is an ERC-4337 user operation, with the v0.7 fields packed as the v0.6 ones
+type UserOperation struct {
+	Sender   common.Address
+	Nonce    *big.Int
+	InitCode []byte
+	CallData []byte
+
+	CallGasLimit         *big.Int
+	VerificationGasLimit *big.Int
+	PreVerificationGas   *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+
+	PaymasterAndData []byte
+}
+
+// userOperationJSON is the JSON RPC encoding of a v0.6 or v0.7 user operation
+type userOperationJSON struct {
+	Sender               *common.Address `json:"sender"`
+	Nonce                *hexutil.Big    `json:"nonce"`
+	CallData             *hexutil.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big    `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big    `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big    `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Signature            *hexutil.Bytes  `json:"signature"`
+
+	// v0.6 fields
+	InitCode         *hexutil.Bytes `json:"initCode"`
+	PaymasterAndData *hexutil.Bytes `json:"paymasterAndData"`
+
+	// v0.7 fields
+	Factory                       *common.Address `json:"factory"`
+	FactoryData                   *hexutil.Bytes  `json:"factoryData"`
+	Paymaster                     *common.Address `json:"paymaster"`
+	PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit"`
+	PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit"`
+	PaymasterData                 *hexutil.Bytes  `json:"paymasterData"`
+}
+
+// isUserOperation reports whether the raw data is a JSON user operation instead of an RLP transaction
+func isUserOperation(raw []byte) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// ParseUserOperation decodes the JSON user operation of an EntryPoint version, unknown fields are rejected
+func ParseUserOperation(raw []byte, version string) (*UserOperation, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var op userOperationJSON
+	if err := decoder.Decode(&op); err != nil {
+		return nil, fmt.Errorf("failed to parse user operation: %w", err)
+	}
+
+	if op.Sender == nil || op.Nonce == nil || op.CallData == nil || op.CallGasLimit == nil || op.VerificationGasLimit == nil ||
+		op.PreVerificationGas == nil || op.MaxFeePerGas == nil || op.MaxPriorityFeePerGas == nil {
+		return nil, fmt.Errorf("user operation field is missing")
+	}
+	userOp := &UserOperation{
+		Sender:               *op.Sender,
+		Nonce:                op.Nonce.ToInt(),
+		CallData:             *op.CallData,
+		CallGasLimit:         op.CallGasLimit.ToInt(),
+		VerificationGasLimit: op.VerificationGasLimit.ToInt(),
+		PreVerificationGas:   op.PreVerificationGas.ToInt(),
+		MaxFeePerGas:         op.MaxFeePerGas.ToInt(),
+		MaxPriorityFeePerGas: op.MaxPriorityFeePerGas.ToInt(),
+	}
+
+	switch version {
+	case EntryPointV06:
+		if op.Factory != nil || op.FactoryData != nil || op.Paymaster != nil || op.PaymasterVerificationGasLimit != nil ||
+			op.PaymasterPostOpGasLimit != nil || op.PaymasterData != nil {
+			return nil, fmt.Errorf("user operation of entry point %v has v0.7 fields", version)
+		}
+		if op.InitCode != nil {
+			userOp.InitCode = *op.InitCode
+		}
+		if op.PaymasterAndData != nil {
+			userOp.PaymasterAndData = *op.PaymasterAndData
+		}
+	case EntryPointV07:
+		if op.InitCode != nil || op.PaymasterAndData != nil {
+			return nil, fmt.Errorf("user operation of entry point %v has v0.6 fields", version)
+		}
+		if err := packV07Fields(userOp, &op); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported entry point version %q", version)
+	}
+
+	if len(userOp.InitCode) > 0 && len(userOp.InitCode) < common.AddressLength {
+		return nil, fmt.Errorf("user operation init code is shorter than the factory address")
+	}
+	if len(userOp.PaymasterAndData) > 0 && len(userOp.PaymasterAndData) < common.AddressLength {
+		return nil, fmt.Errorf("user operation paymaster data is shorter than the paymaster address")
+	}
+	return userOp, nil
+}
+
+// packV07Fields packs the factory and paymaster fields of a v0.7 user operation as the EntryPoint does
+func packV07Fields(userOp *UserOperation, op *userOperationJSON) error {
+	for name, value := range map[string]*big.Int{
+		"callGasLimit":         userOp.CallGasLimit,
+		"verificationGasLimit": userOp.VerificationGasLimit,
+		"maxFeePerGas":         userOp.MaxFeePerGas,
+		"maxPriorityFeePerGas": userOp.MaxPriorityFeePerGas,
+	} {
+		if value.Cmp(maxUint128) > 0 {
+			return fmt.Errorf("user operation %v %v exceeds uint128", name, value)
+		}
+	}
+
+	if op.Factory != nil {
+		userOp.InitCode = append(userOp.InitCode, op.Factory.Bytes()...)
+		if op.FactoryData != nil {
+			userOp.InitCode = append(userOp.InitCode, *op.FactoryData...)
+		}
+	} else if op.FactoryData != nil && len(*op.FactoryData) > 0 {
+		return fmt.Errorf("user operation has factory data without factory")
+	}
+
+	if op.Paymaster == nil {
+		if op.PaymasterVerificationGasLimit != nil || op.PaymasterPostOpGasLimit != nil ||
+			(op.PaymasterData != nil && len(*op.PaymasterData) > 0) {
+			return fmt.Errorf("user operation has paymaster fields without paymaster")
+		}
+		return nil
+	}
+	verificationGasLimit, postOpGasLimit := new(big.Int), new(big.Int)
+	if op.PaymasterVerificationGasLimit != nil {
+		verificationGasLimit = op.PaymasterVerificationGasLimit.ToInt()
+	}
+	if op.PaymasterPostOpGasLimit != nil {
+		postOpGasLimit = op.PaymasterPostOpGasLimit.ToInt()
+	}
+	if verificationGasLimit.Cmp(maxUint128) > 0 || postOpGasLimit.Cmp(maxUint128) > 0 {
+		return fmt.Errorf("user operation paymaster gas limit exceeds uint128")
+	}
+	userOp.PaymasterAndData = append(userOp.PaymasterAndData, op.Paymaster.Bytes()...)
+	userOp.PaymasterAndData = append(userOp.PaymasterAndData, common.LeftPadBytes(verificationGasLimit.Bytes(), 16)...)
+	userOp.PaymasterAndData = append(userOp.PaymasterAndData, common.LeftPadBytes(postOpGasLimit.Bytes(), 16)...)
+	if op.PaymasterData != nil {
+		userOp.PaymasterAndData = append(userOp.PaymasterAndData, *op.PaymasterData...)
+	}
+	return nil
+}
+
+// Factory returns the account factory of the init code, nil if the account is deployed
+func (op *UserOperation) Factory() *common.Address {
+	if len(op.InitCode) == 0 {
+		return nil
+	}
+	factory := common.BytesToAddress(op.InitCode[:common.AddressLength])
+	return &factory
+}
+
+// Paymaster returns the paymaster paying the gas, nil if the account pays
+func (op *UserOperation) Paymaster() *common.Address {
+	if len(op.PaymasterAndData) == 0 {
+		return nil
+	}
+	paymaster := common.BytesToAddress(op.PaymasterAndData[:common.AddressLength])
+	return &paymaster
+}
+
+// UserOperationHash returns the userOpHash of an EntryPoint version on a chain
+func UserOperationHash(op *UserOperation, entryPoint *EntryPoint, chainID *big.Int) common.Hash {
+	words := [][]byte{
+		common.LeftPadBytes(op.Sender.Bytes(), 32),
+		u256(op.Nonce),
+		crypto.Keccak256(op.InitCode),
+		crypto.Keccak256(op.CallData),
+	}
+	if entryPoint.Version == EntryPointV06 {
+		words = append(words,
+			u256(op.CallGasLimit),
+			u256(op.VerificationGasLimit),
+			u256(op.PreVerificationGas),
+			u256(op.MaxFeePerGas),
+			u256(op.MaxPriorityFeePerGas),
+		)
+	} else {
+		// v0.7 packs the gas limits and the gas fees as two uint128 in a bytes32
+		words = append(words,
+			packUint128(op.VerificationGasLimit, op.CallGasLimit),
+			u256(op.PreVerificationGas),
+			packUint128(op.MaxPriorityFeePerGas, op.MaxFeePerGas),
+		)
+	}
+	words = append(words, crypto.Keccak256(op.PaymasterAndData))
+
+	return crypto.Keccak256Hash(
+		crypto.Keccak256(words...),
+		common.LeftPadBytes(entryPoint.Address.Bytes(), 32),
+		u256(chainID),
+	)
+}
+
+func u256(value *big.Int) []byte {
+	return math.U256Bytes(new(big.Int).Set(value))
+}
+
+func packUint128(high, low *big.Int) []byte {
+	return append(common.LeftPadBytes(high.Bytes(), 16), common.LeftPadBytes(low.Bytes(), 16)...)
+}
+
+// UserOperationTransaction is a user operation signed by the owner of an ERC-4337 account
+type UserOperationTransaction struct {
+	token      *Token
+	chain      string
+	entryPoint *EntryPoint
+	userOp     *UserOperation
+}
+
+// GetHashes implements Transaction interface for a user operation. The account owner signs the userOpHash,
+// or its EIP-191 message hash for the SimpleAccount
+func (t *UserOperationTransaction) GetHashes() ([]string, error) {
+	hash := UserOperationHash(t.userOp, t.entryPoint, t.token.chainID)
+	return []string{hash.String(), common.BytesToHash(accounts.TextHash(hash.Bytes())).String()}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for a user operation,
+// the factory and the paymaster run code during the operation so they are destinations too
+func (t *UserOperationTransaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := token_adapter.DestinationAddresses(transfers)
+	if factory := t.userOp.Factory(); factory != nil {
+		addresses = append(addresses, factory.Hex())
+	}
+	if paymaster := t.userOp.Paymaster(); paymaster != nil {
+		addresses = append(addresses, paymaster.Hex())
+	}
+	return addresses, nil
+}
+
+// GetTransfers implements Transaction interface for a user operation, the calls executed by the account are decoded
+func (t *UserOperationTransaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	calls, err := decodeAccountCalls(t.userOp.CallData)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := []token_adapter.Transfer{}
+	for _, call := range calls {
+		callTransfers, err := callTransfers(t.chain, t.userOp.Sender, call.To, call.Value, call.Data, 0)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, callTransfers...)
+	}
+	return transfers, nil
+}
+
+// accountCall is a call executed by an ERC-4337 account
+type accountCall struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// decodeAccountCalls decodes the execute and executeBatch call data of an account, other methods are rejected
+func decodeAccountCalls(callData []byte) ([]accountCall, error) {
+	if len(callData) == 0 {
+		return nil, nil
+	}
+	if len(callData) < 4 {
+		return nil, fmt.Errorf("invalid account call data length %d", len(callData))
+	}
+	method, err := accountABI.MethodById(callData[:4])
+	if err != nil {
+		return nil, fmt.Errorf("account method %x is not execute or executeBatch", callData[:4])
+	}
+	values, err := unpackCanonical(*method, callData[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case method.RawName == "execute":
+		// execute(address dest, uint256 value, bytes func)
+		return []accountCall{{To: values[0].(common.Address), Value: values[1].(*big.Int), Data: values[2].([]byte)}}, nil
+	case len(values) == 2:
+		// executeBatch(address[] dest, bytes[] func)
+		dests, data := values[0].([]common.Address), values[1].([][]byte)
+		if len(dests) != len(data) {
+			return nil, fmt.Errorf("executeBatch argument lengths mismatch")
+		}
+		calls := make([]accountCall, 0, len(dests))
+		for i := range dests {
+			calls = append(calls, accountCall{To: dests[i], Value: new(big.Int), Data: data[i]})
+		}
+		return calls, nil
+	default:
+		// executeBatch(address[] dest, uint256[] value, bytes[] func), an empty value list sends no value
+		dests, amounts, data := values[0].([]common.Address), values[1].([]*big.Int), values[2].([][]byte)
+		if len(dests) != len(data) || (len(amounts) != 0 && len(amounts) != len(data)) {
+			return nil, fmt.Errorf("executeBatch argument lengths mismatch")
+		}
+		calls := make([]accountCall, 0, len(dests))
+		for i := range dests {
+			call := accountCall{To: dests[i], Value: new(big.Int), Data: data[i]}
+			if len(amounts) != 0 {
+				call.Value = amounts[i]
+			}
+			calls = append(calls, call)
+		}
+		return calls, nil
+	}
+}
+
+// buildUserOperation decodes the user operation of a raw transaction for the EntryPoint of the token
+func (t *Token) buildUserOperation(preTxData *PrepareTransactionData) (*UserOperationTransaction, error) {
+	if t.entryPoint == nil || t.chainID == nil {
+		return nil, fmt.Errorf("entry point of token %v is not configured", t.tokenID)
+	}
+
+	userOp, err := ParseUserOperation(preTxData.rawTx, t.entryPoint.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &UserOperationTransaction{
+		token:      t,
+		chain:      preTxData.chainID,
+		entryPoint: t.entryPoint,
+		userOp:     userOp,
+	}
+	if tx.chain == "" {
+		tx.chain = defaultChainID
+	}
+
+	// an ERC-20 token only calls its contract
+	if t.erc20Token {
+		contractAddress, err := t.expectedContract()
+		if err != nil {
+			return nil, err
+		}
+		calls, err := decodeAccountCalls(userOp.CallData)
+		if err != nil {
+			return nil, err
+		}
+		for _, call := range calls {
+			if call.To != contractAddress || call.Value.Sign() != 0 {
+				return nil, fmt.Errorf("user operation call to %v mismatch contract %v of token %v", call.To.Hex(), contractAddress.Hex(), t.tokenID)
+			}
+			if _, err := erc20.DecodeCall(call.Data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return tx, nil
+}
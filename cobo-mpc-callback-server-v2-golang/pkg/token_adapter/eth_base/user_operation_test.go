@@ -0,0 +1,230 @@
+package eth_base
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	accountAddress   = common.HexToAddress("0x7777777777777777777777777777777777777777")
+	paymasterAddress = common.HexToAddress("0x8888888888888888888888888888888888888888")
+	factoryAddress   = common.HexToAddress("0x9999999999999999999999999999999999999999")
+	entryPointV06    = &EntryPoint{Address: common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"), Version: EntryPointV06}
+	entryPointV07    = &EntryPoint{Address: common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032"), Version: EntryPointV07}
+)
+
+func accountCallData(t *testing.T, method string, args ...interface{}) hexutil.Bytes {
+	data, err := accountABI.Pack(method, args...)
+	assert.NoError(t, err)
+	return data
+}
+
+func userOperationJSONString(t *testing.T, fields map[string]interface{}) string {
+	op := map[string]interface{}{
+		"sender":               accountAddress,
+		"nonce":                "0x5",
+		"callGasLimit":         "0x186a0",
+		"verificationGasLimit": "0x30d40",
+		"preVerificationGas":   "0xc350",
+		"maxFeePerGas":         "0x3b9aca00",
+		"maxPriorityFeePerGas": "0x5f5e100",
+		"signature":            "0x",
+	}
+	for name, value := range fields {
+		op[name] = value
+	}
+	raw, err := json.Marshal(op)
+	assert.NoError(t, err)
+	return string(raw)
+}
+
+// expectedUserOperationHash encodes the user operation with the ABI encoder, as the EntryPoint does
+func expectedUserOperationHash(t *testing.T, entryPoint *EntryPoint, chainID int64, callData, initCode, paymasterAndData []byte) []string {
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	addressType, _ := abi.NewType("address", "", nil)
+	bytes32Type, _ := abi.NewType("bytes32", "", nil)
+	word := func(high, low int64) [32]byte {
+		var packed [32]byte
+		copy(packed[:16], common.LeftPadBytes(big.NewInt(high).Bytes(), 16))
+		copy(packed[16:], common.LeftPadBytes(big.NewInt(low).Bytes(), 16))
+		return packed
+	}
+
+	var packed []byte
+	var err error
+	if entryPoint.Version == EntryPointV06 {
+		packed, err = abi.Arguments{
+			{Type: addressType}, {Type: uint256Type}, {Type: bytes32Type}, {Type: bytes32Type}, {Type: uint256Type},
+			{Type: uint256Type}, {Type: uint256Type}, {Type: uint256Type}, {Type: uint256Type}, {Type: bytes32Type},
+		}.Pack(accountAddress, big.NewInt(5), crypto.Keccak256Hash(initCode), crypto.Keccak256Hash(callData),
+			big.NewInt(100000), big.NewInt(200000), big.NewInt(50000), big.NewInt(1000000000), big.NewInt(100000000),
+			crypto.Keccak256Hash(paymasterAndData))
+	} else {
+		packed, err = abi.Arguments{
+			{Type: addressType}, {Type: uint256Type}, {Type: bytes32Type}, {Type: bytes32Type}, {Type: bytes32Type},
+			{Type: uint256Type}, {Type: bytes32Type}, {Type: bytes32Type},
+		}.Pack(accountAddress, big.NewInt(5), crypto.Keccak256Hash(initCode), crypto.Keccak256Hash(callData),
+			word(200000, 100000), big.NewInt(50000), word(100000000, 1000000000), crypto.Keccak256Hash(paymasterAndData))
+	}
+	assert.NoError(t, err)
+
+	encoded, err := abi.Arguments{{Type: bytes32Type}, {Type: addressType}, {Type: uint256Type}}.
+		Pack(crypto.Keccak256Hash(packed), entryPoint.Address, big.NewInt(chainID))
+	assert.NoError(t, err)
+	hash := crypto.Keccak256(encoded)
+	return []string{hexutil.Encode(hash), hexutil.Encode(accounts.TextHash(hash))}
+}
+
+func TestToken_BuildTransaction_UserOperation(t *testing.T) {
+	executeData := accountCallData(t, "execute", safeRecipient, big.NewInt(1000), []byte{})
+	batchData := accountCallData(t, "executeBatch0",
+		[]common.Address{safeRecipient, usdtAddress},
+		[]*big.Int{big.NewInt(1000), big.NewInt(0)},
+		[][]byte{{}, erc20TransferData(safeRecipient, 50000000)},
+	)
+	initCode := append(factoryAddress.Bytes(), 0x01, 0x02)
+	paymasterAndData := append(append(append(paymasterAddress.Bytes(),
+		common.LeftPadBytes(big.NewInt(30000).Bytes(), 16)...), common.LeftPadBytes(big.NewInt(20000).Bytes(), 16)...), 0xaa)
+
+	tests := []struct {
+		name          string
+		token         *Token
+		userOp        string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantAddresses []string
+		wantError     bool
+	}{
+		{
+			name:       "v0.6 execute",
+			token:      &Token{tokenID: "ETH", chainID: big.NewInt(1), entryPoint: entryPointV06},
+			userOp:     userOperationJSONString(t, map[string]interface{}{"callData": executeData, "initCode": "0x", "paymasterAndData": "0x"}),
+			wantHashes: expectedUserOperationHash(t, entryPointV06, 1, executeData, nil, nil),
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "ETH", From: accountAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(1000)},
+			},
+			wantAddresses: []string{safeRecipient.Hex()},
+		},
+		{
+			name:  "v0.6 executeBatch with init code",
+			token: &Token{tokenID: "ETH", chainID: big.NewInt(1), entryPoint: entryPointV06},
+			userOp: userOperationJSONString(t, map[string]interface{}{
+				"callData": accountCallData(t, "executeBatch", []common.Address{safeRecipient}, [][]byte{{}}),
+				"initCode": hexutil.Bytes(initCode), "paymasterAndData": "0x",
+			}),
+			wantHashes: expectedUserOperationHash(t, entryPointV06, 1,
+				accountCallData(t, "executeBatch", []common.Address{safeRecipient}, [][]byte{{}}), initCode, nil),
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "ETH", From: accountAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(0)},
+			},
+			wantAddresses: []string{safeRecipient.Hex(), factoryAddress.Hex()},
+		},
+		{
+			name:  "v0.7 executeBatch with paymaster",
+			token: &Token{tokenID: "ETH", chainID: big.NewInt(8453), entryPoint: entryPointV07},
+			userOp: userOperationJSONString(t, map[string]interface{}{
+				"callData": batchData, "paymaster": paymasterAddress, "paymasterVerificationGasLimit": "0x7530",
+				"paymasterPostOpGasLimit": "0x4e20", "paymasterData": "0xaa",
+			}),
+			wantHashes: expectedUserOperationHash(t, entryPointV07, 8453, batchData, nil, paymasterAndData),
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "ETH", From: accountAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(1000)},
+				{Chain: "ETH", From: accountAddress.Hex(), To: usdtAddress.Hex(), Amount: big.NewInt(0)},
+				{Chain: "ETH", Asset: usdtAddress.Hex(), From: accountAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(50000000), Method: "transfer"},
+			},
+			wantAddresses: []string{safeRecipient.Hex(), usdtAddress.Hex(), safeRecipient.Hex(), paymasterAddress.Hex()},
+		},
+		{
+			name:  "v0.7 ERC20 token",
+			token: &Token{tokenID: "ETH_USDT", erc20Token: true, chainID: big.NewInt(1), entryPoint: entryPointV07, contractAddress: &usdtAddress},
+			userOp: userOperationJSONString(t, map[string]interface{}{
+				"callData": accountCallData(t, "execute", usdtAddress, big.NewInt(0), erc20TransferData(safeRecipient, 7)),
+			}),
+			wantHashes: expectedUserOperationHash(t, entryPointV07, 1,
+				accountCallData(t, "execute", usdtAddress, big.NewInt(0), erc20TransferData(safeRecipient, 7)), nil, nil),
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "ETH", From: accountAddress.Hex(), To: usdtAddress.Hex(), Amount: big.NewInt(0)},
+				{Chain: "ETH", Asset: usdtAddress.Hex(), From: accountAddress.Hex(), To: safeRecipient.Hex(), Amount: big.NewInt(7), Method: "transfer"},
+			},
+			wantAddresses: []string{usdtAddress.Hex(), safeRecipient.Hex()},
+		},
+		{
+			name:      "ERC20 token calling another contract",
+			token:     &Token{tokenID: "ETH_USDT", erc20Token: true, chainID: big.NewInt(1), entryPoint: entryPointV07, contractAddress: &usdtAddress},
+			userOp:    userOperationJSONString(t, map[string]interface{}{"callData": executeData}),
+			wantError: true,
+		},
+		{
+			name:      "v0.7 fields for v0.6 entry point",
+			token:     &Token{tokenID: "ETH", chainID: big.NewInt(1), entryPoint: entryPointV06},
+			userOp:    userOperationJSONString(t, map[string]interface{}{"callData": executeData, "paymaster": paymasterAddress}),
+			wantError: true,
+		},
+		{
+			name:      "Unknown field",
+			token:     &Token{tokenID: "ETH", chainID: big.NewInt(1), entryPoint: entryPointV07},
+			userOp:    userOperationJSONString(t, map[string]interface{}{"callData": executeData, "target": safeRecipient}),
+			wantError: true,
+		},
+		{
+			name:      "Token without entry point",
+			token:     &Token{tokenID: "ETH", chainID: big.NewInt(1)},
+			userOp:    userOperationJSONString(t, map[string]interface{}{"callData": executeData}),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := tt.token.BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.userOp},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAddresses, addresses)
+		})
+	}
+}
+
+func TestUserOperationTransaction_UnknownAccountMethod(t *testing.T) {
+	token := &Token{tokenID: "ETH", chainID: big.NewInt(1), entryPoint: entryPointV07}
+	userOp := userOperationJSONString(t, map[string]interface{}{"callData": hexutil.Bytes(erc20TransferData(safeRecipient, 1))})
+
+	tx, err := token.BuildTransaction(&token_adapter.TransactionInfo{
+		Transaction: &coboWaaS2.Transaction{
+			RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &userOp},
+		},
+	})
+	assert.NoError(t, err)
+
+	transfers, err := tx.GetTransfers()
+	assert.Error(t, err)
+	assert.Nil(t, transfers)
+}
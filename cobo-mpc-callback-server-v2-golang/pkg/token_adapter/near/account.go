@@ -0,0 +1,26 @@
+package near
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	minAccountIDLength = 2
+	maxAccountIDLength = 64
+)
+
+// accountIDPattern matches the parts of an account id separated by dots, each of lowercase alphanumerics
+// separated by a single - or _
+var accountIDPattern = regexp.MustCompile(`^(([a-z\d]+[-_])*[a-z\d]+\.)*([a-z\d]+[-_])*[a-z\d]+$`)
+
+// validateAccountID checks a named account id, e.g. alice.near, or an implicit account id, e.g. 64 hex characters
+func validateAccountID(accountID string) error {
+	if len(accountID) < minAccountIDLength || len(accountID) > maxAccountIDLength {
+		return fmt.Errorf("account id %q length is not between %d and %d", accountID, minAccountIDLength, maxAccountIDLength)
+	}
+	if !accountIDPattern.MatchString(accountID) {
+		return fmt.Errorf("invalid account id %q", accountID)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package near
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAccountID(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		wantError bool
+	}{
+		{name: "Named account", accountID: "alice.near"},
+		{name: "Sub-account with separators", accountID: "bob_1.token-factory.near"},
+		{name: "Implicit account", accountID: strings.Repeat("ab", 32)},
+		{name: "Top-level account", accountID: "near"},
+		{name: "Too short", accountID: "a", wantError: true},
+		{name: "Too long", accountID: strings.Repeat("a", 65), wantError: true},
+		{name: "Uppercase", accountID: "Alice.near", wantError: true},
+		{name: "Consecutive separators", accountID: "alice--bob.near", wantError: true},
+		{name: "Leading dot", accountID: ".near", wantError: true},
+		{name: "Trailing separator", accountID: "alice-.near", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAccountID(tt.accountID)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package near
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// decoder reads the borsh serialization of a transaction
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.offset
+}
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > d.remaining() {
+		return nil, fmt.Errorf("read %d bytes at offset %d: out of range", n, d.offset)
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *decoder) readU8() (byte, error) {
+	b, err := d.readBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *decoder) readU32() (uint32, error) {
+	b, err := d.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (d *decoder) readU64() (uint64, error) {
+	b, err := d.readBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// readU128 reads a little endian u128, e.g. a deposit in yoctoNEAR
+func (d *decoder) readU128() (*big.Int, error) {
+	b, err := d.readBytes(16)
+	if err != nil {
+		return nil, err
+	}
+	be := make([]byte, len(b))
+	for i, c := range b {
+		be[len(b)-1-i] = c
+	}
+	return new(big.Int).SetBytes(be), nil
+}
+
+// readVecBytes reads a Vec<u8>, its length is a u32
+func (d *decoder) readVecBytes() ([]byte, error) {
+	length, err := d.readU32()
+	if err != nil {
+		return nil, err
+	}
+	return d.readBytes(int(length))
+}
+
+// readLength reads the length of a Vec, bounded by the bytes left since an item has at least one byte
+func (d *decoder) readLength() (int, error) {
+	length, err := d.readU32()
+	if err != nil {
+		return 0, err
+	}
+	if int(length) > d.remaining() {
+		return 0, fmt.Errorf("%d items at offset %d: out of range", length, d.offset)
+	}
+	return int(length), nil
+}
+
+func (d *decoder) readString() (string, error) {
+	b, err := d.readVecBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
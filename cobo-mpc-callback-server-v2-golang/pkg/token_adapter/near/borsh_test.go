@@ -0,0 +1,44 @@
+package near
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder_ReadU128(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		want      *big.Int
+		wantError bool
+	}{
+		{name: "One", data: []byte{1, 15: 0}, want: big.NewInt(1)},
+		{name: "Little endian", data: []byte{0x00, 0x01, 15: 0}, want: big.NewInt(256)},
+		{name: "Max", data: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			want: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))},
+		{name: "Truncated", data: make([]byte, 15), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := (&decoder{data: tt.data}).readU128()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestDecoder_ReadLength(t *testing.T) {
+	length, err := (&decoder{data: []byte{2, 0, 0, 0, 1, 2}}).readLength()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, length)
+
+	_, err = (&decoder{data: []byte{3, 0, 0, 0, 1, 2}}).readLength()
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package near
+
+import (
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Token struct {
+	tokenID string
+}
+
+// NewToken returns the NEAR token of the NEAR mainnet
+func NewToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+	}
+}
+
+func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	preTxData, err := prepareBuildTransactionData(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
+	}
+
+	data, err := ParseTransactionData(preTxData.rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("prepare near transaction data error: %w", err)
+	}
+
+	return &Transaction{tx: data, PrepareTransactionData: preTxData, token: t}, nil
+}
+
+func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
+		return nil, fmt.Errorf("transaction info raw tx is nil")
+	}
+
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
+	if len(rawTxBytes) == 0 {
+		return nil, fmt.Errorf("transaction raw tx is empty")
+	}
+
+	return &PrepareTransactionData{rawTx: rawTxBytes, chainID: txInfo.Transaction.GetChainId()}, nil
+}
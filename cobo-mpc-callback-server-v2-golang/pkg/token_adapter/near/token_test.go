@@ -0,0 +1,99 @@
+package near
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("NEAR")
+	nearToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "NEAR", nearToken.tokenID)
+}
+
+// nearTransfer is the transaction of the near-api-js transfer test vector, a transfer of 1 yoctoNEAR from test.near
+// to whatever.near with nonce 1, and nearTransferSignature is its signature by the access key of the transaction
+const (
+	nearTransfer = "09000000746573742e6e65617200917b3d268d4b58f7fec1b150bd68d69be3ee5d4cc39855e341538465bb77860d01000000000000000d00" +
+		"000077686174657665722e6e6561720fa473fd26901df296be6adc4cc4df34d040efa2435224b6986910e630c2fef6010000000301000000" +
+		"000000000000000000000000"
+
+	nearTransferSignature = "lpqDMyGG7pdV5IOTJVJYBuGJo9LSu0tHYOlEQ+l+HE8i3u7wBZqOlxMQDtpuGRRNp+ig735TmyBwi6HY0CG9AQ=="
+)
+
+func TestToken_BuildTransaction(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:       "Transfer",
+			rawTx:      nearTransfer,
+			wantHashes: []string{"0xeea6e680f3ea51a7f667e9a801d0bfadf66e03d41ed54975b3c6006351461b32"},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "NEAR", From: "test.near", To: "whatever.near", Amount: big.NewInt(1)},
+			},
+		},
+		{
+			// the transfer action replaced by a deletion of the account in favor of test.near
+			name:      "Delete account",
+			rawTx:     nearTransfer[:len(nearTransfer)-34] + "07" + "09000000746573742e6e656172",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewToken("NEAR").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
+
+func TestToken_BuildTransaction_Signature(t *testing.T) {
+	tx, err := NewToken("NEAR").BuildTransaction(&token_adapter.TransactionInfo{
+		Transaction: &coboWaaS2.Transaction{
+			RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: coboWaaS2.PtrString(nearTransfer)},
+		},
+	})
+	assert.NoError(t, err)
+	hashes, err := tx.GetHashes()
+	assert.NoError(t, err)
+
+	// the signature of near-api-js is over the hash, with the public key following the signer id
+	rawTx, err := hex.DecodeString(nearTransfer)
+	assert.NoError(t, err)
+	signature, err := base64.StdEncoding.DecodeString(nearTransferSignature)
+	assert.NoError(t, err)
+	hash, err := hex.DecodeString(strings.TrimPrefix(hashes[0], "0x"))
+	assert.NoError(t, err)
+	assert.True(t, ed25519.Verify(rawTx[14:46], hash, signature))
+}
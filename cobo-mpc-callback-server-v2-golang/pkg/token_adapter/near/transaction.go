@@ -0,0 +1,87 @@
+package near
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+)
+
+const defaultChainID = "NEAR"
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx *TransactionData
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx   []byte
+	chainID string
+}
+
+// GetHashes implements Transaction interface for NEAR, the hash is the sha256 of the borsh serialized transaction
+func (t *Transaction) GetHashes() ([]string, error) {
+	if len(t.rawTx) == 0 {
+		return nil, fmt.Errorf("transaction raw data is empty")
+	}
+
+	hash := sha256.Sum256(t.rawTx)
+	return []string{"0x" + hex.EncodeToString(hash[:])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for NEAR
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for NEAR. A transfer of NEAR goes to the receiver id, an ft_transfer
+// is a transfer of the token contract, the receiver id, to the receiver of its arguments.
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	var transfers []token_adapter.Transfer
+	for _, action := range t.tx.actions {
+		if action.ftTransfer == nil {
+			transfers = append(transfers, token_adapter.Transfer{
+				Chain:  chainID,
+				From:   t.tx.signerID,
+				To:     t.tx.receiverID,
+				Amount: action.deposit,
+			})
+			continue
+		}
+
+		transfers = append(transfers, token_adapter.Transfer{
+			Chain:  chainID,
+			Asset:  t.tx.receiverID,
+			From:   t.tx.signerID,
+			To:     action.ftTransfer.receiverID,
+			Amount: action.ftTransfer.amount,
+			Memo:   action.ftTransfer.memo,
+			Tag:    action.ftTransfer.memo,
+			Method: methodFtTransfer,
+		})
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,240 @@
+package near
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+const (
+	keyTypeEd25519   = 0
+	keyTypeSecp256k1 = 1
+
+	actionFunctionCall = 2
+	actionTransfer     = 3
+
+	methodFtTransfer = "ft_transfer"
+)
+
+// keyLengths are the lengths of the public keys by key type
+var keyLengths = map[byte]int{
+	keyTypeEd25519:   32,
+	keyTypeSecp256k1: 64,
+}
+
+// actionNames names the actions, the ones other than a transfer or a function call are rejected
+var actionNames = map[byte]string{
+	0:  "CreateAccount",
+	1:  "DeployContract",
+	2:  "FunctionCall",
+	3:  "Transfer",
+	4:  "Stake",
+	5:  "AddKey",
+	6:  "DeleteKey",
+	7:  "DeleteAccount",
+	8:  "Delegate",
+	9:  "DeployGlobalContract",
+	10: "UseGlobalContract",
+}
+
+// oneYocto is the deposit attached to an ft_transfer, which requires a full access key
+var oneYocto = big.NewInt(1)
+
+// Action is a transfer of NEAR, or an ft_transfer of the fungible token of the receiver contract
+type Action struct {
+	// deposit is the amount of yoctoNEAR transferred
+	deposit *big.Int
+	// ftTransfer is nil for a transfer of NEAR
+	ftTransfer *FtTransfer
+}
+
+// FtTransfer is a transfer of the fungible token of the receiver contract
+type FtTransfer struct {
+	receiverID string
+	amount     *big.Int
+	memo       string
+}
+
+// ftTransferArgs are the JSON arguments of an ft_transfer
+type ftTransferArgs struct {
+	ReceiverID string  `json:"receiver_id"`
+	Amount     string  `json:"amount"`
+	Memo       *string `json:"memo"`
+}
+
+// TransactionData is a decoded transaction of transfers and ft_transfers
+type TransactionData struct {
+	signerID   string
+	nonce      uint64
+	receiverID string
+	blockHash  [32]byte
+	actions    []Action
+}
+
+// ParseTransactionData decodes the borsh serialization of a transaction, any action other than a transfer or
+// an ft_transfer function call is rejected
+func ParseTransactionData(rawTx []byte) (*TransactionData, error) {
+	d := &decoder{data: rawTx}
+	data := &TransactionData{}
+
+	var err error
+	if data.signerID, err = readAccountID(d); err != nil {
+		return nil, fmt.Errorf("decode signer id: %w", err)
+	}
+	if err := readPublicKey(d); err != nil {
+		return nil, fmt.Errorf("decode public key: %w", err)
+	}
+	if data.nonce, err = d.readU64(); err != nil {
+		return nil, fmt.Errorf("decode nonce: %w", err)
+	}
+	if data.receiverID, err = readAccountID(d); err != nil {
+		return nil, fmt.Errorf("decode receiver id: %w", err)
+	}
+	blockHash, err := d.readBytes(32)
+	if err != nil {
+		return nil, fmt.Errorf("decode block hash: %w", err)
+	}
+	copy(data.blockHash[:], blockHash)
+
+	count, err := d.readLength()
+	if err != nil {
+		return nil, fmt.Errorf("decode actions: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("transaction has no action")
+	}
+	for i := 0; i < count; i++ {
+		action, err := readAction(d)
+		if err != nil {
+			return nil, fmt.Errorf("decode action %d: %w", i, err)
+		}
+		data.actions = append(data.actions, action)
+	}
+
+	if d.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after transaction", d.remaining())
+	}
+	return data, nil
+}
+
+func readAccountID(d *decoder) (string, error) {
+	accountID, err := d.readString()
+	if err != nil {
+		return "", err
+	}
+	if err := validateAccountID(accountID); err != nil {
+		return "", err
+	}
+	return accountID, nil
+}
+
+func readPublicKey(d *decoder) error {
+	keyType, err := d.readU8()
+	if err != nil {
+		return err
+	}
+	length, ok := keyLengths[keyType]
+	if !ok {
+		return fmt.Errorf("unsupported key type %d", keyType)
+	}
+	_, err = d.readBytes(length)
+	return err
+}
+
+func readAction(d *decoder) (Action, error) {
+	var action Action
+	kind, err := d.readU8()
+	if err != nil {
+		return action, err
+	}
+
+	switch kind {
+	case actionTransfer:
+		action.deposit, err = d.readU128()
+		return action, err
+	case actionFunctionCall:
+		return readFunctionCall(d)
+	default:
+		if name, ok := actionNames[kind]; ok {
+			return action, fmt.Errorf("unsupported action %v", name)
+		}
+		return action, fmt.Errorf("unsupported action %d", kind)
+	}
+}
+
+// readFunctionCall reads an ft_transfer with a deposit of one yoctoNEAR
+func readFunctionCall(d *decoder) (Action, error) {
+	var action Action
+	methodName, err := d.readString()
+	if err != nil {
+		return action, err
+	}
+	args, err := d.readVecBytes()
+	if err != nil {
+		return action, err
+	}
+	// the gas is paid at the gas price of the block, which is not part of the transaction
+	if _, err := d.readU64(); err != nil {
+		return action, err
+	}
+	if action.deposit, err = d.readU128(); err != nil {
+		return action, err
+	}
+
+	if methodName != methodFtTransfer {
+		return action, fmt.Errorf("unsupported method %q", methodName)
+	}
+	if action.deposit.Cmp(oneYocto) != 0 {
+		return action, fmt.Errorf("%v deposit %v is not one yoctoNEAR", methodName, action.deposit)
+	}
+	if action.ftTransfer, err = parseFtTransferArgs(args); err != nil {
+		return action, fmt.Errorf("decode %v args: %w", methodName, err)
+	}
+	return action, nil
+}
+
+// parseFtTransferArgs decodes the JSON arguments, an unknown field is rejected
+func parseFtTransferArgs(args []byte) (*FtTransfer, error) {
+	decoder := json.NewDecoder(bytes.NewReader(args))
+	decoder.DisallowUnknownFields()
+	var ftArgs ftTransferArgs
+	if err := decoder.Decode(&ftArgs); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("trailing data after args")
+	}
+
+	if err := validateAccountID(ftArgs.ReceiverID); err != nil {
+		return nil, fmt.Errorf("receiver id: %w", err)
+	}
+	amount, err := parseAmount(ftArgs.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	ftTransfer := &FtTransfer{receiverID: ftArgs.ReceiverID, amount: amount}
+	if ftArgs.Memo != nil {
+		ftTransfer.memo = *ftArgs.Memo
+	}
+	return ftTransfer, nil
+}
+
+// parseAmount parses a decimal u128 amount
+func parseAmount(s string) (*big.Int, error) {
+	if s == "" || len(s) > 1 && s[0] == '0' {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid amount %q", s)
+		}
+	}
+	amount, _ := new(big.Int).SetString(s, 10)
+	if amount.BitLen() > 128 {
+		return nil, fmt.Errorf("amount %v overflows u128", s)
+	}
+	return amount, nil
+}
@@ -0,0 +1,144 @@
+package near
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func borshString(s string) []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(s))), s...)
+}
+
+func borshU128(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, v), 0)
+}
+
+func transferAction(deposit uint64) []byte {
+	return append([]byte{actionTransfer}, borshU128(deposit)...)
+}
+
+func functionCallAction(method, args string, deposit uint64) []byte {
+	return bytes.Join([][]byte{
+		{actionFunctionCall}, borshString(method), borshString(args),
+		binary.LittleEndian.AppendUint64(nil, 30000000000000), borshU128(deposit),
+	}, nil)
+}
+
+// encodeTransaction returns a transaction signed by an ed25519 key with a nonce of 7
+func encodeTransaction(signerID, receiverID string, actions ...[]byte) []byte {
+	return bytes.Join([][]byte{
+		borshString(signerID), {keyTypeEd25519}, bytes.Repeat([]byte{0x11}, 32), binary.LittleEndian.AppendUint64(nil, 7),
+		borshString(receiverID), bytes.Repeat([]byte{0x22}, 32),
+		binary.LittleEndian.AppendUint32(nil, uint32(len(actions))), bytes.Join(actions, nil),
+	}, nil)
+}
+
+func TestParseTransactionData(t *testing.T) {
+	ftArgs := `{"receiver_id":"bob.near","amount":"1000000","memo":"123456"}`
+
+	tests := []struct {
+		name       string
+		rawTx      []byte
+		wantFt     *FtTransfer
+		wantAction int
+		wantError  bool
+	}{
+		{
+			name:       "Transfer",
+			rawTx:      encodeTransaction("alice.near", "bob.near", transferAction(1000)),
+			wantAction: 1,
+		},
+		{
+			name:       "ft_transfer",
+			rawTx:      encodeTransaction("alice.near", "usdt.tether-token.near", functionCallAction("ft_transfer", ftArgs, 1)),
+			wantFt:     &FtTransfer{receiverID: "bob.near", amount: big.NewInt(1000000), memo: "123456"},
+			wantAction: 1,
+		},
+		{
+			name: "ft_transfer without memo",
+			rawTx: encodeTransaction("alice.near", "usdt.tether-token.near",
+				functionCallAction("ft_transfer", `{"receiver_id":"bob.near","amount":"5","memo":null}`, 1)),
+			wantFt:     &FtTransfer{receiverID: "bob.near", amount: big.NewInt(5)},
+			wantAction: 1,
+		},
+		{
+			name: "Secp256k1 key",
+			rawTx: bytes.Join([][]byte{
+				borshString("alice.near"), {keyTypeSecp256k1}, make([]byte, 64), make([]byte, 8), borshString("bob.near"),
+				make([]byte, 32), {1, 0, 0, 0}, transferAction(1000),
+			}, nil),
+			wantAction: 1,
+		},
+		{
+			name:      "ft_transfer_call",
+			rawTx:     encodeTransaction("alice.near", "usdt.tether-token.near", functionCallAction("ft_transfer_call", ftArgs, 1)),
+			wantError: true,
+		},
+		{
+			name:      "ft_transfer without one yocto",
+			rawTx:     encodeTransaction("alice.near", "usdt.tether-token.near", functionCallAction("ft_transfer", ftArgs, 0)),
+			wantError: true,
+		},
+		{
+			name: "ft_transfer unknown field",
+			rawTx: encodeTransaction("alice.near", "usdt.tether-token.near",
+				functionCallAction("ft_transfer", `{"receiver_id":"bob.near","amount":"5","msg":""}`, 1)),
+			wantError: true,
+		},
+		{
+			name: "ft_transfer invalid amount",
+			rawTx: encodeTransaction("alice.near", "usdt.tether-token.near",
+				functionCallAction("ft_transfer", `{"receiver_id":"bob.near","amount":"-5"}`, 1)),
+			wantError: true,
+		},
+		{
+			name: "ft_transfer trailing data",
+			rawTx: encodeTransaction("alice.near", "usdt.tether-token.near",
+				functionCallAction("ft_transfer", ftArgs+`{}`, 1)),
+			wantError: true,
+		},
+		{
+			name:      "storage_deposit",
+			rawTx:     encodeTransaction("alice.near", "usdt.tether-token.near", functionCallAction("storage_deposit", `{}`, 1250000000000000000)),
+			wantError: true,
+		},
+		{
+			name:      "Unsupported action",
+			rawTx:     encodeTransaction("alice.near", "bob.near", []byte{7}, borshString("carol.near")),
+			wantError: true,
+		},
+		{
+			name:      "No action",
+			rawTx:     encodeTransaction("alice.near", "bob.near"),
+			wantError: true,
+		},
+		{
+			name:      "Invalid receiver id",
+			rawTx:     encodeTransaction("alice.near", "Bob.near", transferAction(1000)),
+			wantError: true,
+		},
+		{
+			name:      "Trailing bytes",
+			rawTx:     append(encodeTransaction("alice.near", "bob.near", transferAction(1000)), 0),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ParseTransactionData(tt.rawTx)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "alice.near", data.signerID)
+			assert.Len(t, data.actions, tt.wantAction)
+			assert.Equal(t, tt.wantFt, data.actions[0].ftTransfer)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package near
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         []byte
+		wantTransfers []token_adapter.Transfer
+	}{
+		{
+			name:  "Transfer",
+			rawTx: encodeTransaction("alice.near", "bob.near", transferAction(1000)),
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, From: "alice.near", To: "bob.near", Amount: big.NewInt(1000)},
+			},
+		},
+		{
+			name: "ft_transfers",
+			rawTx: encodeTransaction("alice.near", "usdt.tether-token.near",
+				functionCallAction("ft_transfer", `{"receiver_id":"bob.near","amount":"1000000","memo":"123456"}`, 1),
+				functionCallAction("ft_transfer", `{"amount":"5","receiver_id":"carol.near"}`, 1),
+			),
+			wantTransfers: []token_adapter.Transfer{
+				{
+					Chain: defaultChainID, Asset: "usdt.tether-token.near", From: "alice.near", To: "bob.near", Amount: big.NewInt(1000000),
+					Memo: "123456", Tag: "123456", Method: methodFtTransfer,
+				},
+				{
+					Chain: defaultChainID, Asset: "usdt.tether-token.near", From: "alice.near", To: "carol.near", Amount: big.NewInt(5),
+					Method: methodFtTransfer,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ParseTransactionData(tt.rawTx)
+			assert.NoError(t, err)
+			tx := &Transaction{tx: data, PrepareTransactionData: &PrepareTransactionData{rawTx: tt.rawTx}}
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, token_adapter.DestinationAddresses(tt.wantTransfers), addresses)
+		})
+	}
+}
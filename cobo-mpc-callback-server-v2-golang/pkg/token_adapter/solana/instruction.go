@@ -0,0 +1,307 @@
+package solana
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	// computeBudgetProgramID is the program setting the compute unit limit and price of a transaction
+	computeBudgetProgramID = solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+	// memoV1ProgramID is the first version of the memo program, still used by some wallets
+	memoV1ProgramID = solana.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+)
+
+// System program instructions, a u32 little endian discriminator
+const (
+	systemInstructionTransfer            uint32 = 2
+	systemInstructionAdvanceNonceAccount uint32 = 4
+	systemInstructionTransferWithSeed    uint32 = 11
+)
+
+// Instructions shared by the SPL Token and Token-2022 programs
+const (
+	tokenInstructionTransfer        byte = 3
+	tokenInstructionTransferChecked byte = 12
+)
+
+// Associated token account program instructions, an empty data is a Create
+const (
+	ataInstructionCreate           byte = 0
+	ataInstructionCreateIdempotent byte = 1
+)
+
+// Compute budget program instructions
+const (
+	computeBudgetRequestHeapFrame               byte = 1
+	computeBudgetSetComputeUnitLimit            byte = 2
+	computeBudgetSetComputeUnitPrice            byte = 3
+	computeBudgetSetLoadedAccountsDataSizeLimit byte = 4
+)
+
+const (
+	// defaultInstructionComputeUnits is the compute unit limit of an instruction without a SetComputeUnitLimit
+	defaultInstructionComputeUnits = 200_000
+	// maxComputeUnits is the max compute unit limit of a transaction
+	maxComputeUnits = 1_400_000
+	// lamportsPerSignature is the base fee of a signature
+	lamportsPerSignature = 5000
+	// microLamportsPerLamport is the unit of the compute unit price
+	microLamportsPerLamport = 1_000_000
+)
+
+// systemTransfer is a lamport transfer of the system program
+type systemTransfer struct {
+	// from is the signer of the transfer, the base account of a transfer with seed
+	from   solana.PublicKey
+	to     solana.PublicKey
+	amount uint64
+}
+
+// advanceNonce is the AdvanceNonceAccount of a durable nonce transaction, which replaces the recent blockhash
+type advanceNonce struct {
+	nonceAccount solana.PublicKey
+	authority    solana.PublicKey
+}
+
+// tokenTransfer is a transfer between token accounts of the SPL Token or Token-2022 program
+type tokenTransfer struct {
+	source      solana.PublicKey
+	destination solana.PublicKey
+	// owner is the owner or delegate of the source account
+	owner  solana.PublicKey
+	amount uint64
+	// mint and decimals are only set by a TransferChecked
+	mint     *solana.PublicKey
+	decimals *uint8
+}
+
+// createAssociatedTokenAccount is the creation of the associated token account of a wallet
+type createAssociatedTokenAccount struct {
+	payer        solana.PublicKey
+	account      solana.PublicKey
+	wallet       solana.PublicKey
+	mint         solana.PublicKey
+	tokenProgram solana.PublicKey
+}
+
+// computeBudget is the compute budget set by the compute budget instructions, nil if not set
+type computeBudget struct {
+	unitLimit *uint32
+	// unitPrice is in micro-lamports per compute unit
+	unitPrice *uint64
+	heapFrame *uint32
+	// loadedAccountsDataSizeLimit is in bytes
+	loadedAccountsDataSizeLimit *uint32
+}
+
+// instructionAccounts returns the accounts of an instruction, the indexes were checked by resolveAccountKeys
+func instructionAccounts(inst solana.CompiledInstruction, accountKeys solana.PublicKeySlice) solana.PublicKeySlice {
+	accounts := make(solana.PublicKeySlice, 0, len(inst.Accounts))
+	for _, idx := range inst.Accounts {
+		accounts = append(accounts, accountKeys[idx])
+	}
+	return accounts
+}
+
+// decodeSystemTransfer decodes a Transfer or TransferWithSeed of the system program
+func decodeSystemTransfer(data []byte, accounts solana.PublicKeySlice) (*systemTransfer, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("invalid system instruction data length %d", len(data))
+	}
+
+	switch discriminator := binary.LittleEndian.Uint32(data[:4]); discriminator {
+	case systemInstructionTransfer:
+		// u32 discriminator + u64 lamports, accounts: 0 funding, 1 recipient
+		if len(data) != 12 {
+			return nil, fmt.Errorf("invalid system transfer data length %d", len(data))
+		}
+		if len(accounts) < 2 {
+			return nil, fmt.Errorf("system transfer account length %v less than 2", len(accounts))
+		}
+		return &systemTransfer{from: accounts[0], to: accounts[1], amount: binary.LittleEndian.Uint64(data[4:12])}, nil
+	case systemInstructionTransferWithSeed:
+		// u32 discriminator + u64 lamports + u64 seed length + seed + owner,
+		// accounts: 0 funding account derived from the base, 1 base, 2 recipient
+		if len(data) < 20 {
+			return nil, fmt.Errorf("invalid system transfer with seed data length %d", len(data))
+		}
+		seedLength := binary.LittleEndian.Uint64(data[12:20])
+		if seedLength > solana.MaxSeedLength || uint64(len(data)) != 20+seedLength+32 {
+			return nil, fmt.Errorf("invalid system transfer with seed data length %d", len(data))
+		}
+		if len(accounts) < 3 {
+			return nil, fmt.Errorf("system transfer with seed account length %v less than 3", len(accounts))
+		}
+		seed := string(data[20 : 20+seedLength])
+		owner := solana.PublicKeyFromBytes(data[20+seedLength:])
+		funding, err := solana.CreateWithSeed(accounts[1], seed, owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive account with seed: %w", err)
+		}
+		if !funding.Equals(accounts[0]) {
+			return nil, fmt.Errorf("funding account %v is not derived from base %v with seed %q", accounts[0], accounts[1], seed)
+		}
+		return &systemTransfer{from: accounts[1], to: accounts[2], amount: binary.LittleEndian.Uint64(data[4:12])}, nil
+	default:
+		return nil, fmt.Errorf("unsupported system instruction %d", discriminator)
+	}
+}
+
+// isAdvanceNonce reports whether the instruction of a program is an AdvanceNonceAccount
+func isAdvanceNonce(programID solana.PublicKey, data []byte) bool {
+	return programID.Equals(solana.SystemProgramID) && len(data) >= 4 &&
+		binary.LittleEndian.Uint32(data[:4]) == systemInstructionAdvanceNonceAccount
+}
+
+// decodeAdvanceNonce decodes an AdvanceNonceAccount of the system program
+func decodeAdvanceNonce(data []byte, accounts solana.PublicKeySlice) (*advanceNonce, error) {
+	// u32 discriminator, accounts: 0 nonce account, 1 recent blockhashes sysvar, 2 nonce authority
+	if len(data) != 4 || binary.LittleEndian.Uint32(data) != systemInstructionAdvanceNonceAccount {
+		return nil, fmt.Errorf("instruction is not an advance nonce account")
+	}
+	if len(accounts) < 3 {
+		return nil, fmt.Errorf("advance nonce account account length %v less than 3", len(accounts))
+	}
+	if !accounts[1].Equals(solana.SysVarRecentBlockHashesPubkey) {
+		return nil, fmt.Errorf("advance nonce account sysvar %v is not the recent blockhashes", accounts[1])
+	}
+	return &advanceNonce{nonceAccount: accounts[0], authority: accounts[2]}, nil
+}
+
+// decodeTokenTransfer decodes a Transfer or TransferChecked of the SPL Token or Token-2022 program
+func decodeTokenTransfer(data []byte, accounts solana.PublicKeySlice) (*tokenTransfer, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("spl token instruction data is empty")
+	}
+
+	switch data[0] {
+	case tokenInstructionTransfer:
+		// u8 discriminator + u64 amount, accounts: 0 source, 1 destination, 2 owner
+		if len(data) != 9 {
+			return nil, fmt.Errorf("invalid spl token transfer data length %d", len(data))
+		}
+		if len(accounts) < 3 {
+			return nil, fmt.Errorf("spl token transfer account length %v less than 3", len(accounts))
+		}
+		return &tokenTransfer{
+			source:      accounts[0],
+			destination: accounts[1],
+			owner:       accounts[2],
+			amount:      binary.LittleEndian.Uint64(data[1:9]),
+		}, nil
+	case tokenInstructionTransferChecked:
+		// u8 discriminator + u64 amount + u8 decimals, accounts: 0 source, 1 mint, 2 destination, 3 owner
+		if len(data) != 10 {
+			return nil, fmt.Errorf("invalid spl token transfer checked data length %d", len(data))
+		}
+		if len(accounts) < 4 {
+			return nil, fmt.Errorf("spl token transfer checked account length %v less than 4", len(accounts))
+		}
+		mint := accounts[1]
+		decimals := data[9]
+		return &tokenTransfer{
+			source:      accounts[0],
+			destination: accounts[2],
+			owner:       accounts[3],
+			amount:      binary.LittleEndian.Uint64(data[1:9]),
+			mint:        &mint,
+			decimals:    &decimals,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported spl token instruction %d", data[0])
+	}
+}
+
+// decodeCreateAssociatedTokenAccount decodes a Create or CreateIdempotent of the associated token account program,
+// the created account must be the associated token account of the wallet
+func decodeCreateAssociatedTokenAccount(data []byte, accounts solana.PublicKeySlice) (*createAssociatedTokenAccount, error) {
+	if len(data) > 1 || (len(data) == 1 && data[0] != ataInstructionCreate && data[0] != ataInstructionCreateIdempotent) {
+		return nil, fmt.Errorf("unsupported associated token account instruction %x", data)
+	}
+	// accounts: 0 payer, 1 associated token account, 2 wallet, 3 mint, 4 system program, 5 token program
+	if len(accounts) < 6 {
+		return nil, fmt.Errorf("associated token account creation account length %v less than 6", len(accounts))
+	}
+
+	create := &createAssociatedTokenAccount{
+		payer:        accounts[0],
+		account:      accounts[1],
+		wallet:       accounts[2],
+		mint:         accounts[3],
+		tokenProgram: accounts[5],
+	}
+	if !create.tokenProgram.Equals(solana.TokenProgramID) && !create.tokenProgram.Equals(solana.Token2022ProgramID) {
+		return nil, fmt.Errorf("associated token account of unknown token program %v", create.tokenProgram)
+	}
+	expected, err := GetAssociatedTokenAddress(create.wallet, create.mint, create.tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	if !expected.Equals(create.account) {
+		return nil, fmt.Errorf("created account %v is not the associated token account %v", create.account, expected)
+	}
+	return create, nil
+}
+
+// decodeComputeBudget decodes a compute budget instruction into the budget, an instruction may only be set once
+func decodeComputeBudget(data []byte, budget *computeBudget) error {
+	if len(data) == 0 {
+		return fmt.Errorf("compute budget instruction data is empty")
+	}
+
+	setUint32 := func(name string, field **uint32) error {
+		if len(data) != 5 {
+			return fmt.Errorf("invalid compute budget %v data length %d", name, len(data))
+		}
+		if *field != nil {
+			return fmt.Errorf("duplicate compute budget %v", name)
+		}
+		value := binary.LittleEndian.Uint32(data[1:5])
+		*field = &value
+		return nil
+	}
+
+	switch data[0] {
+	case computeBudgetRequestHeapFrame:
+		return setUint32("heap frame", &budget.heapFrame)
+	case computeBudgetSetComputeUnitLimit:
+		return setUint32("compute unit limit", &budget.unitLimit)
+	case computeBudgetSetLoadedAccountsDataSizeLimit:
+		return setUint32("loaded accounts data size limit", &budget.loadedAccountsDataSizeLimit)
+	case computeBudgetSetComputeUnitPrice:
+		if len(data) != 9 {
+			return fmt.Errorf("invalid compute budget compute unit price data length %d", len(data))
+		}
+		if budget.unitPrice != nil {
+			return fmt.Errorf("duplicate compute budget compute unit price")
+		}
+		price := binary.LittleEndian.Uint64(data[1:9])
+		budget.unitPrice = &price
+		return nil
+	default:
+		return fmt.Errorf("unsupported compute budget instruction %d", data[0])
+	}
+}
+
+// computeUnitLimit returns the compute unit limit of a transaction with the budget and the count of other instructions
+func (b *computeBudget) computeUnitLimit(instructions int) uint64 {
+	limit := uint64(defaultInstructionComputeUnits) * uint64(instructions)
+	if b.unitLimit != nil {
+		limit = uint64(*b.unitLimit)
+	}
+	return min(limit, maxComputeUnits)
+}
+
+// priorityFee returns the priority fee in lamports, the compute unit limit times the price rounded up
+func (b *computeBudget) priorityFee(unitLimit uint64) *big.Int {
+	if b.unitPrice == nil {
+		return new(big.Int)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(unitLimit), new(big.Int).SetUint64(*b.unitPrice))
+	fee.Add(fee, big.NewInt(microLamportsPerLamport-1))
+	return fee.Div(fee, big.NewInt(microLamportsPerLamport))
+}
@@ -0,0 +1,331 @@
+package solana
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func systemTransferInstruction(from, to solana.PublicKey, lamports uint64) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, systemInstructionTransfer)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	return solana.NewInstruction(solana.SystemProgramID, solana.AccountMetaSlice{
+		solana.Meta(from).WRITE().SIGNER(),
+		solana.Meta(to).WRITE(),
+	}, data)
+}
+
+func systemTransferWithSeedInstruction(funding, base, to solana.PublicKey, seed string, lamports uint64) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, systemInstructionTransferWithSeed)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(seed)))
+	data = append(data, seed...)
+	data = append(data, solana.SystemProgramID.Bytes()...)
+	return solana.NewInstruction(solana.SystemProgramID, solana.AccountMetaSlice{
+		solana.Meta(funding).WRITE(),
+		solana.Meta(base).SIGNER(),
+		solana.Meta(to).WRITE(),
+	}, data)
+}
+
+func advanceNonceInstruction(nonceAccount, authority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(solana.SystemProgramID, solana.AccountMetaSlice{
+		solana.Meta(nonceAccount).WRITE(),
+		solana.Meta(solana.SysVarRecentBlockHashesPubkey),
+		solana.Meta(authority).SIGNER(),
+	}, binary.LittleEndian.AppendUint32(nil, systemInstructionAdvanceNonceAccount))
+}
+
+func tokenTransferInstruction(programID, source, destination, owner solana.PublicKey, amount uint64) solana.Instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{tokenInstructionTransfer}, amount)
+	return solana.NewInstruction(programID, solana.AccountMetaSlice{
+		solana.Meta(source).WRITE(),
+		solana.Meta(destination).WRITE(),
+		solana.Meta(owner).SIGNER(),
+	}, data)
+}
+
+func tokenTransferCheckedInstruction(programID, source, mint, destination, owner solana.PublicKey, amount uint64, decimals uint8) solana.Instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{tokenInstructionTransferChecked}, amount)
+	return solana.NewInstruction(programID, solana.AccountMetaSlice{
+		solana.Meta(source).WRITE(),
+		solana.Meta(mint),
+		solana.Meta(destination).WRITE(),
+		solana.Meta(owner).SIGNER(),
+	}, append(data, decimals))
+}
+
+func createAssociatedTokenAccountInstruction(payer, account, wallet, mint, programID solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(solana.SPLAssociatedTokenAccountProgramID, solana.AccountMetaSlice{
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(account).WRITE(),
+		solana.Meta(wallet),
+		solana.Meta(mint),
+		solana.Meta(solana.SystemProgramID),
+		solana.Meta(programID),
+	}, []byte{ataInstructionCreateIdempotent})
+}
+
+func computeUnitLimitInstruction(limit uint32) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32([]byte{computeBudgetSetComputeUnitLimit}, limit)
+	return solana.NewInstruction(computeBudgetProgramID, solana.AccountMetaSlice{}, data)
+}
+
+func computeUnitPriceInstruction(price uint64) solana.Instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{computeBudgetSetComputeUnitPrice}, price)
+	return solana.NewInstruction(computeBudgetProgramID, solana.AccountMetaSlice{}, data)
+}
+
+func newTestTransaction(t *testing.T, token *Token, payer solana.PublicKey, destination string, instructions ...solana.Instruction) *Transaction {
+	tx, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(payer))
+	assert.NoError(t, err)
+	return &Transaction{
+		tx:                     tx,
+		PrepareTransactionData: &PrepareTransactionData{destinationAddress: destination, sourceAddresses: []string{payer.String()}},
+		token:                  token,
+	}
+}
+
+func TestTransaction_GetTransfers_Instructions(t *testing.T) {
+	sender := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	mint := solana.MustPublicKeyFromBase58(usdcMintAddress)
+	otherMint := solana.NewWallet().PublicKey()
+	nonceAccount := solana.NewWallet().PublicKey()
+
+	seedAccount, err := solana.CreateWithSeed(sender, "stake:0", solana.SystemProgramID)
+	assert.NoError(t, err)
+	senderTokenAccount, err := GetAssociatedTokenAddress(sender, mint, solana.TokenProgramID)
+	assert.NoError(t, err)
+	recipientTokenAccount, err := GetAssociatedTokenAddress(recipient, mint, solana.TokenProgramID)
+	assert.NoError(t, err)
+	recipient2022Account, err := GetAssociatedTokenAddress(recipient, mint, solana.Token2022ProgramID)
+	assert.NoError(t, err)
+	recipientOtherAccount, err := GetAssociatedTokenAddress(recipient, otherMint, solana.TokenProgramID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, token_adapter.RegisterTokenDecimals("SOL_TEST_USDC", 6))
+	solToken := &Token{tokenID: "SOL"}
+	splToken := &Token{tokenID: "SOL_TEST_USDC", isSPLToken: true, mintAddress: usdcMintAddress}
+	memo := solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{}, []byte("invoice 42"))
+
+	tests := []struct {
+		name          string
+		token         *Token
+		instructions  []solana.Instruction
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:  "System transfer with compute budget and memo",
+			token: solToken,
+			instructions: []solana.Instruction{
+				computeUnitLimitInstruction(300_000),
+				computeUnitPriceInstruction(1000),
+				systemTransferInstruction(sender, recipient, 1_000_000),
+				memo,
+			},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "SOL", From: sender.String(), To: recipient.String(), Amount: big.NewInt(1_000_000), Memo: "invoice 42"},
+			},
+		},
+		{
+			name:         "System transfer with seed",
+			token:        solToken,
+			instructions: []solana.Instruction{systemTransferWithSeedInstruction(seedAccount, sender, recipient, "stake:0", 5)},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "SOL", From: sender.String(), To: recipient.String(), Amount: big.NewInt(5)},
+			},
+		},
+		{
+			name:         "System transfer with seed of another account",
+			token:        solToken,
+			instructions: []solana.Instruction{systemTransferWithSeedInstruction(recipient, sender, recipient, "stake:0", 5)},
+			wantError:    true,
+		},
+		{
+			name:  "Durable nonce transfer",
+			token: solToken,
+			instructions: []solana.Instruction{
+				advanceNonceInstruction(nonceAccount, sender),
+				systemTransferInstruction(sender, recipient, 3),
+			},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "SOL", From: sender.String(), To: recipient.String(), Amount: big.NewInt(3)},
+			},
+		},
+		{
+			name:  "Durable nonce of another authority",
+			token: solToken,
+			instructions: []solana.Instruction{
+				advanceNonceInstruction(nonceAccount, recipient),
+				systemTransferInstruction(sender, recipient, 3),
+			},
+			wantError: true,
+		},
+		{
+			name:  "Advance nonce after a transfer",
+			token: solToken,
+			instructions: []solana.Instruction{
+				systemTransferInstruction(sender, recipient, 3),
+				advanceNonceInstruction(nonceAccount, sender),
+			},
+			wantError: true,
+		},
+		{
+			name:  "Duplicate compute unit limit",
+			token: solToken,
+			instructions: []solana.Instruction{
+				computeUnitLimitInstruction(1), computeUnitLimitInstruction(2), systemTransferInstruction(sender, recipient, 1),
+			},
+			wantError: true,
+		},
+		{
+			name:  "Unknown program",
+			token: solToken,
+			instructions: []solana.Instruction{
+				systemTransferInstruction(sender, recipient, 1),
+				solana.NewInstruction(solana.NewWallet().PublicKey(), solana.AccountMetaSlice{solana.Meta(sender).SIGNER()}, []byte{1}),
+			},
+			wantError: true,
+		},
+		{
+			name:  "Token instruction in native transaction",
+			token: solToken,
+			instructions: []solana.Instruction{
+				tokenTransferInstruction(solana.TokenProgramID, senderTokenAccount, recipientTokenAccount, sender, 1),
+			},
+			wantError: true,
+		},
+		{
+			name:  "Token transfer",
+			token: splToken,
+			instructions: []solana.Instruction{
+				tokenTransferInstruction(solana.TokenProgramID, senderTokenAccount, recipientTokenAccount, sender, 7),
+			},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "SOL", Asset: usdcMintAddress, From: sender.String(), To: recipient.String(), Amount: big.NewInt(7)},
+			},
+		},
+		{
+			name:  "Token-2022 transfer checked with account creation",
+			token: splToken,
+			instructions: []solana.Instruction{
+				createAssociatedTokenAccountInstruction(sender, recipient2022Account, recipient, mint, solana.Token2022ProgramID),
+				tokenTransferCheckedInstruction(solana.Token2022ProgramID, senderTokenAccount, mint, recipient2022Account, sender, 8, 6),
+			},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "SOL", Asset: usdcMintAddress, From: sender.String(), To: recipient.String(), Amount: big.NewInt(8)},
+			},
+		},
+		{
+			name:  "Durable nonce token transfer",
+			token: splToken,
+			instructions: []solana.Instruction{
+				advanceNonceInstruction(nonceAccount, sender),
+				tokenTransferInstruction(solana.TokenProgramID, senderTokenAccount, recipientTokenAccount, sender, 7),
+			},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "SOL", Asset: usdcMintAddress, From: sender.String(), To: recipient.String(), Amount: big.NewInt(7)},
+			},
+		},
+		{
+			name:  "Token transfer checked of other decimals",
+			token: splToken,
+			instructions: []solana.Instruction{
+				tokenTransferCheckedInstruction(solana.TokenProgramID, senderTokenAccount, mint, recipientTokenAccount, sender, 8, 9),
+			},
+			wantError: true,
+		},
+		{
+			name:  "Token transfer to an account of another mint",
+			token: splToken,
+			instructions: []solana.Instruction{
+				tokenTransferInstruction(solana.TokenProgramID, senderTokenAccount, recipientOtherAccount, sender, 7),
+			},
+			wantError: true,
+		},
+		{
+			name:  "Account creation of another mint",
+			token: splToken,
+			instructions: []solana.Instruction{
+				createAssociatedTokenAccountInstruction(sender, recipientOtherAccount, recipient, otherMint, solana.TokenProgramID),
+				tokenTransferInstruction(solana.TokenProgramID, senderTokenAccount, recipientTokenAccount, sender, 7),
+			},
+			wantError: true,
+		},
+		{
+			name:  "System instruction in token transaction",
+			token: splToken,
+			instructions: []solana.Instruction{
+				tokenTransferInstruction(solana.TokenProgramID, senderTokenAccount, recipientTokenAccount, sender, 7),
+				systemTransferInstruction(sender, recipient, 1),
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTestTransaction(t, tt.token, sender, recipient.String(), tt.instructions...)
+			transfers, err := tx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, transfers)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
+
+func TestTransaction_GetFee(t *testing.T) {
+	sender := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+
+	tests := []struct {
+		name         string
+		instructions []solana.Instruction
+		wantFee      *token_adapter.Fee
+	}{
+		{
+			name: "Compute unit limit and price",
+			instructions: []solana.Instruction{
+				computeUnitLimitInstruction(300_000),
+				computeUnitPriceInstruction(1500),
+				systemTransferInstruction(sender, recipient, 1),
+			},
+			// 5000 lamports of the signature plus 300,000 units at 1500 micro-lamports
+			wantFee: &token_adapter.Fee{GasLimit: big.NewInt(300_000), GasPrice: big.NewInt(1500), FeeLimit: big.NewInt(5450)},
+		},
+		{
+			name: "Default compute unit limit",
+			instructions: []solana.Instruction{
+				computeUnitPriceInstruction(1),
+				systemTransferInstruction(sender, recipient, 1),
+				systemTransferInstruction(sender, recipient, 2),
+			},
+			// the priority fee of 400,000 units at 1 micro-lamport is rounded up
+			wantFee: &token_adapter.Fee{GasLimit: big.NewInt(400_000), GasPrice: big.NewInt(1), FeeLimit: big.NewInt(5001)},
+		},
+		{
+			name:         "Without compute budget",
+			instructions: []solana.Instruction{systemTransferInstruction(sender, recipient, 1)},
+			wantFee:      &token_adapter.Fee{GasLimit: big.NewInt(200_000), FeeLimit: big.NewInt(5000)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTestTransaction(t, &Token{tokenID: "SOL"}, sender, "", tt.instructions...)
+			fee, err := tx.GetFee()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFee, fee)
+		})
+	}
+}
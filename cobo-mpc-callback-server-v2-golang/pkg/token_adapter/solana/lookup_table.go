@@ -0,0 +1,153 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// lookupTablesExtraType is the extra_type of the transaction extra holding the address lookup tables of the transaction
+const lookupTablesExtraType = "SolanaAddressLookupTables"
+
+var (
+	lookupTableRegistryLock sync.RWMutex
+	// lookupTableRegistry save the snapshot of address lookup tables by table address
+	lookupTableRegistry = make(map[solana.PublicKey]solana.PublicKeySlice)
+)
+
+// RegisterAddressLookupTable registers the snapshot of an address lookup table, used to resolve the accounts of a v0 transaction
+func RegisterAddressLookupTable(table solana.PublicKey, addresses solana.PublicKeySlice) error {
+	if len(addresses) == 0 {
+		return fmt.Errorf("address lookup table %v is empty", table)
+	}
+
+	lookupTableRegistryLock.Lock()
+	defer lookupTableRegistryLock.Unlock()
+
+	if _, exists := lookupTableRegistry[table]; exists {
+		return fmt.Errorf("address lookup table %v is already registered", table)
+	}
+
+	lookupTableRegistry[table] = addresses
+	return nil
+}
+
+// GetAddressLookupTable returns the snapshot registered for an address lookup table
+func GetAddressLookupTable(table solana.PublicKey) (solana.PublicKeySlice, bool) {
+	lookupTableRegistryLock.RLock()
+	defer lookupTableRegistryLock.RUnlock()
+
+	addresses, ok := lookupTableRegistry[table]
+	return addresses, ok
+}
+
+// LoadAddressLookupTables registers the address lookup tables of a JSON snapshot file,
+// an object of the table address to the list of its addresses
+func LoadAddressLookupTables(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read address lookup tables: %w", err)
+	}
+
+	var tables map[solana.PublicKey]solana.PublicKeySlice
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return fmt.Errorf("failed to parse address lookup tables %v: %w", path, err)
+	}
+	for table, addresses := range tables {
+		if err := RegisterAddressLookupTable(table, addresses); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupTablesFromExtra returns the address lookup tables carried by the transaction extra, nil if there is none
+func lookupTablesFromExtra(extra []string) (map[solana.PublicKey]solana.PublicKeySlice, error) {
+	var tables map[solana.PublicKey]solana.PublicKeySlice
+	for _, item := range extra {
+		var info struct {
+			AddressLookupTables map[solana.PublicKey]solana.PublicKeySlice `json:"address_lookup_tables"`
+		}
+		// extra of other types may be in any format
+		var header struct {
+			ExtraType string `json:"extra_type"`
+		}
+		if err := json.Unmarshal([]byte(item), &header); err != nil || header.ExtraType != lookupTablesExtraType {
+			continue
+		}
+		if err := json.Unmarshal([]byte(item), &info); err != nil {
+			return nil, fmt.Errorf("failed to parse address lookup tables extra: %w", err)
+		}
+		if tables != nil {
+			return nil, fmt.Errorf("duplicate address lookup tables extra")
+		}
+		tables = info.AddressLookupTables
+	}
+	return tables, nil
+}
+
+// lookupTable returns the addresses of a table from the snapshot. A table of the request is not trusted, as the
+// upstream could point a lookup to any account, so it is only accepted when it equals the snapshot.
+func lookupTable(table solana.PublicKey, requestTables map[solana.PublicKey]solana.PublicKeySlice) (solana.PublicKeySlice, error) {
+	snapshot, ok := GetAddressLookupTable(table)
+	if !ok {
+		return nil, fmt.Errorf("address lookup table %v is not in the snapshot", table)
+	}
+
+	if requested, inRequest := requestTables[table]; inRequest {
+		if len(requested) != len(snapshot) {
+			return nil, fmt.Errorf("address lookup table %v of the request has %d addresses, the snapshot has %d",
+				table, len(requested), len(snapshot))
+		}
+		for i := range snapshot {
+			if !snapshot[i].Equals(requested[i]) {
+				return nil, fmt.Errorf("address lookup table %v of the request mismatch the snapshot at index %d", table, i)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// resolveAccountKeys returns the static accounts followed by the writable then the readonly accounts loaded from
+// the address lookup tables, the account list indexed by the instructions of the message
+func resolveAccountKeys(message *solana.Message, requestTables map[solana.PublicKey]solana.PublicKeySlice) (solana.PublicKeySlice, error) {
+	keys := append(solana.PublicKeySlice{}, message.AccountKeys...)
+
+	var writable, readonly solana.PublicKeySlice
+	for _, lookup := range message.AddressTableLookups {
+		addresses, err := lookupTable(lookup.AccountKey, requestTables)
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range lookup.WritableIndexes {
+			if int(idx) >= len(addresses) {
+				return nil, fmt.Errorf("address lookup table %v index %d out of range", lookup.AccountKey, idx)
+			}
+			writable = append(writable, addresses[idx])
+		}
+		for _, idx := range lookup.ReadonlyIndexes {
+			if int(idx) >= len(addresses) {
+				return nil, fmt.Errorf("address lookup table %v index %d out of range", lookup.AccountKey, idx)
+			}
+			readonly = append(readonly, addresses[idx])
+		}
+	}
+	keys = append(keys, writable...)
+	keys = append(keys, readonly...)
+
+	for idx, inst := range message.Instructions {
+		// a program is never loaded from an address lookup table
+		if int(inst.ProgramIDIndex) >= len(message.AccountKeys) {
+			return nil, fmt.Errorf("instruction index %v program index %d out of range", idx, inst.ProgramIDIndex)
+		}
+		for _, account := range inst.Accounts {
+			if int(account) >= len(keys) {
+				return nil, fmt.Errorf("instruction index %v account index %d out of range", idx, account)
+			}
+		}
+	}
+	return keys, nil
+}
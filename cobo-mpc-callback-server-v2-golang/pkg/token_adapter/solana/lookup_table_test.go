@@ -0,0 +1,165 @@
+package solana
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+)
+
+// v0TransferTransaction returns a v0 transfer whose recipient is loaded from an address lookup table
+func v0TransferTransaction(t *testing.T, sender, recipient, table solana.PublicKey) *solana.Transaction {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1000, sender, recipient).Build()},
+		solana.Hash{},
+		solana.TransactionPayer(sender),
+		solana.TransactionAddressTables(map[solana.PublicKey]solana.PublicKeySlice{
+			table: {solana.NewWallet().PublicKey(), recipient},
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, tx.Message.AddressTableLookups, 1)
+	return tx
+}
+
+func encodeRawTx(t *testing.T, tx *solana.Transaction) string {
+	data, err := tx.MarshalBinary()
+	assert.NoError(t, err)
+	return hex.EncodeToString([]byte(base64.StdEncoding.EncodeToString(data)))
+}
+
+func lookupTablesExtra(t *testing.T, tables map[solana.PublicKey]solana.PublicKeySlice) string {
+	extra, err := json.Marshal(map[string]interface{}{
+		"extra_type":            lookupTablesExtraType,
+		"address_lookup_tables": tables,
+	})
+	assert.NoError(t, err)
+	return string(extra)
+}
+
+func TestToken_BuildTransaction_AddressLookupTables(t *testing.T) {
+	sender := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	snapshotTable := solana.NewWallet().PublicKey()
+	requestTable := solana.NewWallet().PublicKey()
+	missingTable := solana.NewWallet().PublicKey()
+
+	assert.NoError(t, RegisterAddressLookupTable(snapshotTable, solana.PublicKeySlice{solana.NewWallet().PublicKey(), recipient}))
+
+	outOfRange := v0TransferTransaction(t, sender, recipient, snapshotTable)
+	outOfRange.Message.AddressTableLookups[0].WritableIndexes = []uint8{2}
+
+	tests := []struct {
+		name      string
+		tx        *solana.Transaction
+		extra     []string
+		wantError bool
+	}{
+		{
+			name: "Table in snapshot",
+			tx:   v0TransferTransaction(t, sender, recipient, snapshotTable),
+		},
+		{
+			name: "Request table matching the snapshot",
+			tx:   v0TransferTransaction(t, sender, recipient, snapshotTable),
+			extra: []string{
+				`{"extra_type": "BtcAddressInfo"}`,
+				lookupTablesExtra(t, map[solana.PublicKey]solana.PublicKeySlice{snapshotTable: mustGetLookupTable(t, snapshotTable)}),
+			},
+		},
+		{
+			name: "Table only in request",
+			tx:   v0TransferTransaction(t, sender, recipient, requestTable),
+			extra: []string{
+				lookupTablesExtra(t, map[solana.PublicKey]solana.PublicKeySlice{requestTable: {solana.NewWallet().PublicKey(), recipient}}),
+			},
+			wantError: true,
+		},
+		{
+			name: "Request table extending the snapshot",
+			tx:   v0TransferTransaction(t, sender, recipient, snapshotTable),
+			extra: []string{lookupTablesExtra(t, map[solana.PublicKey]solana.PublicKeySlice{
+				snapshotTable: append(append(solana.PublicKeySlice{}, mustGetLookupTable(t, snapshotTable)...), sender),
+			})},
+			wantError: true,
+		},
+		{
+			name: "Request table mismatch the snapshot",
+			tx:   v0TransferTransaction(t, sender, recipient, snapshotTable),
+			extra: []string{lookupTablesExtra(t, map[solana.PublicKey]solana.PublicKeySlice{
+				snapshotTable: {solana.NewWallet().PublicKey(), sender},
+			})},
+			wantError: true,
+		},
+		{
+			name:      "Table not found",
+			tx:        v0TransferTransaction(t, sender, recipient, missingTable),
+			wantError: true,
+		},
+		{
+			name:      "Lookup index out of range",
+			tx:        outOfRange,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTx := encodeRawTx(t, tt.tx)
+			tx, err := (&Token{tokenID: "SOL"}).BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &rawTx},
+					Extra:     tt.extra,
+				},
+			})
+			assert.NoError(t, err)
+
+			addresses, err := tx.GetDestinationAddresses()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{recipient.String()}, addresses)
+
+			// the hash is the message with the lookups, not the resolved accounts
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			message, err := tt.tx.Message.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"0x" + hex.EncodeToString(message)}, hashes)
+		})
+	}
+}
+
+func mustGetLookupTable(t *testing.T, table solana.PublicKey) solana.PublicKeySlice {
+	addresses, ok := GetAddressLookupTable(table)
+	assert.True(t, ok)
+	return addresses
+}
+
+func TestLoadAddressLookupTables(t *testing.T) {
+	table := solana.NewWallet().PublicKey()
+	address := solana.NewWallet().PublicKey()
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "tables.json")
+	assert.NoError(t, os.WriteFile(valid, []byte(`{"`+table.String()+`": ["`+address.String()+`"]}`), 0o600))
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"not an address": []}`), 0o600))
+
+	assert.NoError(t, LoadAddressLookupTables(valid))
+	assert.Equal(t, solana.PublicKeySlice{address}, mustGetLookupTable(t, table))
+
+	assert.Error(t, LoadAddressLookupTables(valid))
+	assert.Error(t, LoadAddressLookupTables(invalid))
+	assert.Error(t, LoadAddressLookupTables(filepath.Join(dir, "missing.json")))
+}
@@ -0,0 +1,155 @@
+package solana
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// offchainSigningDomain prefixes every off-chain message, so it can never be a transaction message
+var offchainSigningDomain = []byte("\xffsolana offchain")
+
+// Off-chain message formats of the version 0 header
+const (
+	offchainFormatRestrictedASCII byte = 0
+	offchainFormatLimitedUTF8     byte = 1
+	offchainFormatExtendedUTF8    byte = 2
+)
+
+const (
+	// offchainHeaderLength is the signing domain, the version, the format and the message length
+	offchainHeaderLength = 16 + 1 + 1 + 2
+	// offchainMaxLength is the max message length of the extended UTF-8 format
+	offchainMaxLength = 65535 - offchainHeaderLength
+	// offchainMaxLedgerLength is the max message length of the formats signed by a Ledger, a packet minus the header
+	offchainMaxLedgerLength = 1232 - offchainHeaderLength
+)
+
+// Message is a Solana off-chain message signed by the source address
+type Message struct {
+	token *Token
+	// data is the serialized off-chain message, which is signed as is
+	data    []byte
+	content []byte
+}
+
+// GetHashes implements Transaction interface for a Solana message, the serialized message is signed
+func (m *Message) GetHashes() ([]string, error) {
+	return []string{"0x" + hex.EncodeToString(m.data)}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for a Solana message
+func (m *Message) GetDestinationAddresses() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetTransfers implements Transaction interface for a Solana message
+func (m *Message) GetTransfers() ([]token_adapter.Transfer, error) {
+	return []token_adapter.Transfer{}, nil
+}
+
+// GetMessage implements MessageTransaction interface for a Solana message
+func (m *Message) GetMessage() (*token_adapter.Message, error) {
+	return &token_adapter.Message{
+		Standard: token_adapter.MessageStandardSolanaOffchain,
+		Content:  token_adapter.MessageContent(m.content),
+	}, nil
+}
+
+// EncodeOffchainMessage serializes a message with the version 0 off-chain message header,
+// in the most restrictive format of the message
+func EncodeOffchainMessage(message []byte) ([]byte, error) {
+	if len(message) == 0 {
+		return nil, fmt.Errorf("off-chain message is empty")
+	}
+
+	var format byte
+	switch {
+	case len(message) <= offchainMaxLedgerLength && isPrintableASCII(message):
+		format = offchainFormatRestrictedASCII
+	case len(message) <= offchainMaxLedgerLength && utf8.Valid(message):
+		format = offchainFormatLimitedUTF8
+	case len(message) <= offchainMaxLength && utf8.Valid(message):
+		format = offchainFormatExtendedUTF8
+	default:
+		return nil, fmt.Errorf("off-chain message is not UTF-8 text of at most %d bytes", offchainMaxLength)
+	}
+
+	data := make([]byte, 0, offchainHeaderLength+len(message))
+	data = append(data, offchainSigningDomain...)
+	data = append(data, 0, format)
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(message)))
+	return append(data, message...), nil
+}
+
+// DecodeOffchainMessage returns the message of a serialized off-chain message, which must be in the canonical encoding
+func DecodeOffchainMessage(data []byte) ([]byte, error) {
+	if len(data) < offchainHeaderLength || !bytes.HasPrefix(data, offchainSigningDomain) {
+		return nil, fmt.Errorf("data is not an off-chain message")
+	}
+	if version := data[len(offchainSigningDomain)]; version != 0 {
+		return nil, fmt.Errorf("unsupported off-chain message version %d", version)
+	}
+
+	message := data[offchainHeaderLength:]
+	encoded, err := EncodeOffchainMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(encoded, data) {
+		return nil, fmt.Errorf("off-chain message header mismatch the message")
+	}
+	return message, nil
+}
+
+func isPrintableASCII(message []byte) bool {
+	for _, b := range message {
+		if b < 0x20 || b > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// buildMessage builds the off-chain message of a message sign request, nil for another request.
+// The message is the base64 text of a message destination, or the serialized message of a raw message destination.
+func (t *Token) buildMessage(txInfo *token_adapter.TransactionInfo) (*Message, error) {
+	if txInfo == nil || txInfo.Transaction == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	var (
+		data    []byte
+		content []byte
+		err     error
+	)
+	destination := txInfo.Transaction.Destination
+	switch {
+	case destination.TransactionMessageSignEIP191Destination != nil:
+		if content, err = base64.StdEncoding.DecodeString(destination.TransactionMessageSignEIP191Destination.Message); err != nil {
+			return nil, fmt.Errorf("failed to decode message: %w", err)
+		}
+		if data, err = EncodeOffchainMessage(content); err != nil {
+			return nil, err
+		}
+	case destination.TransactionRawMessageSignDestination != nil:
+		data = common.FromHex(destination.TransactionRawMessageSignDestination.GetMsgHash())
+		// a raw message must be an off-chain message, so a transaction can not be signed as a message
+		if content, err = DecodeOffchainMessage(data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil //nolint:nilnil
+	}
+
+	if t.isSPLToken {
+		return nil, fmt.Errorf("message is not supported by spl token %v", t.tokenID)
+	}
+	return &Message{token: t, data: data, content: content}, nil
+}
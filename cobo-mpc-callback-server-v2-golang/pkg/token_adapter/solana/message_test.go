@@ -0,0 +1,143 @@
+package solana
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeOffchainMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		wantHeader string
+		wantError  bool
+	}{
+		{
+			name:       "Restricted ASCII",
+			message:    "Hello",
+			wantHeader: "ff736f6c616e61206f6666636861696e" + "00" + "00" + "0500",
+		},
+		{
+			name:       "Limited UTF-8",
+			message:    "Hello\nworld",
+			wantHeader: "ff736f6c616e61206f6666636861696e" + "00" + "01" + "0b00",
+		},
+		{
+			name:       "Extended UTF-8",
+			message:    strings.Repeat("a", 1213),
+			wantHeader: "ff736f6c616e61206f6666636861696e" + "00" + "02" + "bd04",
+		},
+		{
+			name:      "Empty message",
+			message:   "",
+			wantError: true,
+		},
+		{
+			name:      "Binary message",
+			message:   "\xff\xfe",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := EncodeOffchainMessage([]byte(tt.message))
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, data)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHeader, hex.EncodeToString(data[:offchainHeaderLength]))
+
+			message, err := DecodeOffchainMessage(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.message, string(message))
+		})
+	}
+}
+
+func TestToken_BuildTransaction_Message(t *testing.T) {
+	helloMessage, err := EncodeOffchainMessage([]byte("Hello"))
+	assert.NoError(t, err)
+	// the restricted ASCII message with the limited UTF-8 format
+	nonCanonical := append([]byte{}, helloMessage...)
+	nonCanonical[17] = offchainFormatLimitedUTF8
+
+	rawMessage := func(data []byte) coboWaaS2.TransactionDestination {
+		msgHash := "0x" + hex.EncodeToString(data)
+		return coboWaaS2.TransactionRawMessageSignDestinationAsTransactionDestination(
+			&coboWaaS2.TransactionRawMessageSignDestination{MsgHash: &msgHash},
+		)
+	}
+
+	tests := []struct {
+		name        string
+		token       *Token
+		destination coboWaaS2.TransactionDestination
+		wantError   bool
+	}{
+		{
+			name:  "Message text",
+			token: &Token{tokenID: "SOL"},
+			destination: coboWaaS2.TransactionMessageSignEIP191DestinationAsTransactionDestination(
+				&coboWaaS2.TransactionMessageSignEIP191Destination{Message: base64.StdEncoding.EncodeToString([]byte("Hello"))},
+			),
+		},
+		{
+			name:        "Raw off-chain message",
+			token:       &Token{tokenID: "SOL"},
+			destination: rawMessage(helloMessage),
+		},
+		{
+			name:        "Raw transaction message",
+			token:       &Token{tokenID: "SOL"},
+			destination: rawMessage([]byte{0x01, 0x00, 0x01, 0x03}),
+			wantError:   true,
+		},
+		{
+			name:        "Non canonical format",
+			token:       &Token{tokenID: "SOL"},
+			destination: rawMessage(nonCanonical),
+			wantError:   true,
+		},
+		{
+			name:        "SPL token",
+			token:       &Token{tokenID: "SOL_USDC", isSPLToken: true},
+			destination: rawMessage(helloMessage),
+			wantError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := tt.token.BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{Destination: tt.destination},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"0x" + hex.EncodeToString(helloMessage)}, hashes)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Empty(t, addresses)
+
+			message, err := tx.(token_adapter.MessageTransaction).GetMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Message{Standard: token_adapter.MessageStandardSolanaOffchain, Content: "Hello"}, message)
+		})
+	}
+}
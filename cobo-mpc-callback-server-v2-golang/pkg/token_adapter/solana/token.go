@@ -5,11 +5,14 @@ import (
 
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/gagliardetto/solana-go"
 )
 
 type Token struct {
 	tokenID    string
 	isSPLToken bool
+	// mintAddress is the expected SPL token mint, the mint registered for the token id when empty
+	mintAddress string
 }
 
 func NewToken(tokenID string) token_adapter.Token {
@@ -27,6 +30,14 @@ func NewSPLToken(tokenID string) token_adapter.Token {
 }
 
 func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	message, err := t.buildMessage(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare message error: %w", err)
+	}
+	if message != nil {
+		return message, nil
+	}
+
 	preTxData, err := prepareBuildTransactionData(txInfo)
 	if err != nil {
 		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
@@ -41,15 +52,12 @@ func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_a
 }
 
 func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
-	if txInfo == nil || txInfo.Transaction == nil {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
 		return nil, fmt.Errorf("transaction info raw tx is nil")
 	}
 
-	var rawTxBytes []byte
-	if txInfo.Transaction.RawTxInfo != nil && txInfo.Transaction.RawTxInfo.UnsignedRawTx != nil {
-		rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
-		rawTxBytes = common.FromHex(rawTx)
-	}
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
 
 	var destinationAddress string
 	if txInfo.Transaction.Destination.GetActualInstance() != nil {
@@ -59,5 +67,49 @@ func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *P
 		}
 	}
 
-	return &PrepareTransactionData{rawTx: rawTxBytes[:], destinationAddress: destinationAddress}, nil
+	lookupTables, err := lookupTablesFromExtra(txInfo.Transaction.GetExtra())
+	if err != nil {
+		return nil, err
+	}
+
+	sourceAddresses := make([]string, 0, len(txInfo.SourceAddresses))
+	for _, source := range txInfo.SourceAddresses {
+		sourceAddresses = append(sourceAddresses, source.Address)
+	}
+
+	return &PrepareTransactionData{
+		rawTx:              rawTxBytes,
+		chainID:            txInfo.Transaction.GetChainId(),
+		destinationAddress: destinationAddress,
+		lookupTables:       lookupTables,
+		sourceAddresses:    sourceAddresses,
+	}, nil
+}
+
+// expectedMint returns the SPL token mint bound to the token, the mint registered for the token id when not set
+func (t *Token) expectedMint() (solana.PublicKey, error) {
+	expected := t.mintAddress
+	if expected == "" {
+		var ok bool
+		if expected, ok = token_adapter.GetTokenContract(t.tokenID); !ok {
+			return solana.PublicKey{}, fmt.Errorf("mint of token %v is not registered", t.tokenID)
+		}
+	}
+	mint, err := solana.PublicKeyFromBase58(expected)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("invalid mint %v of token %v: %w", expected, t.tokenID, err)
+	}
+	return mint, nil
+}
+
+// checkMint rejects an SPL token mint other than the mint bound to the token
+func (t *Token) checkMint(mint solana.PublicKey) error {
+	expected, err := t.expectedMint()
+	if err != nil {
+		return err
+	}
+	if !mint.Equals(expected) {
+		return fmt.Errorf("transaction mint %v mismatch mint %v of token %v", mint, expected, t.tokenID)
+	}
+	return nil
 }
@@ -8,6 +8,9 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// usdcMintAddress is the mint of the SPL token transfer example
+const usdcMintAddress = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+
 // Sample transaction raw data, in real tests, replace with valid Solana transaction data
 var (
 	// Native SOL transfer transaction example
@@ -3,10 +3,15 @@ package solana
 import (
 	"encoding/hex"
 	"fmt"
+	"math/big"
+	"slices"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/gagliardetto/solana-go"
 )
 
+const defaultChainID = "SOL"
+
 // Transaction structure
 type Transaction struct {
 	token *Token
@@ -17,7 +22,12 @@ type Transaction struct {
 // PrepareTransactionData contains the raw transaction data
 type PrepareTransactionData struct {
 	rawTx              []byte
+	chainID            string
 	destinationAddress string
+	// lookupTables are the address lookup tables carried by the request, only accepted when equal to the snapshot
+	lookupTables map[solana.PublicKey]solana.PublicKeySlice
+	// sourceAddresses are the signing addresses of the request, one of them must be the nonce authority
+	sourceAddresses []string
 }
 
 // GetHashes implements Transaction interface for Solana
@@ -39,101 +49,198 @@ func (t *Transaction) GetHashes() ([]string, error) {
 
 // GetDestinationAddresses implements Transaction interface for Solana
 func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Solana.
+// Every instruction must be decoded, an instruction of an unknown program is rejected.
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
 	if t.tx == nil {
 		return nil, fmt.Errorf("transaction data is nil")
 	}
 
-	var addresses []string
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	// instructions index the static accounts and the accounts loaded from the address lookup tables
+	accountKeys, err := resolveAccountKeys(&t.tx.Message, t.lookupTables)
+	if err != nil {
+		return nil, fmt.Errorf("resolve transaction accounts error: %w", err)
+	}
+
+	var transfers []token_adapter.Transfer
+	var memo string
+	budget := &computeBudget{}
 
 	// Process instructions
 	for idx, inst := range t.tx.Message.Instructions {
-		programID := t.tx.Message.AccountKeys[inst.ProgramIDIndex]
-
-		// Check if this is a Solana System Program transfer
-		if programID.Equals(solana.SystemProgramID) && !t.token.isSPLToken {
-			// For system transfers,
-			// 1 source recipient
-			// 2 destination recipient
-			if len(inst.Accounts) < 2 {
-				return nil, fmt.Errorf("parse system instruction index %v account length %v less than 2", idx, len(inst.Accounts))
+		programID := accountKeys[inst.ProgramIDIndex]
+		accounts := instructionAccounts(inst, accountKeys)
+
+		// a durable nonce transaction starts with the AdvanceNonceAccount, which moves no value
+		if idx == 0 && isAdvanceNonce(programID, inst.Data) {
+			if err := t.checkAdvanceNonce(inst.Data, accounts); err != nil {
+				return nil, fmt.Errorf("parse nonce instruction index %v: %w", idx, err)
+			}
+			continue
+		}
+
+		switch {
+		case programID.Equals(solana.SystemProgramID):
+			if t.token.isSPLToken {
+				return nil, fmt.Errorf("system instruction index %v in spl token transaction", idx)
 			}
-			recipientIdx := inst.Accounts[1]
-			recipient := t.tx.Message.AccountKeys[recipientIdx]
-			addresses = append(addresses, recipient.String())
-
-			// parse inst.Data
-
-			// accounts := make([]*solana.AccountMeta, len(inst.Accounts))
-			// for i, idx := range inst.Accounts {
-			// 	if int(idx) >= len(t.tx.Message.AccountKeys) {
-			// 		continue
-			// 	}
-			// 	accounts[i] = &solana.AccountMeta{
-			// 		PublicKey: t.tx.Message.AccountKeys[idx],
-			// 		IsSigner:  false, // todo
-			// 		IsWritable: false, // todo
-			// 	}
-			// }
-			// decoded, err := system.DecodeInstruction(accounts, inst.Data)
-			// if err != nil {
-			// 	return nil, err
-			// }
-
-			// // check it is Transfer
-			// transfer, ok := decoded.Impl.(*system.Transfer)
-			// if !ok {
-			// 	return nil, err
-			// }
-			// _ = *transfer.Lamports
-
-		} else if programID.Equals(solana.TokenProgramID) && t.token.isSPLToken {
-			// For SPL token transfers,
-			// 0 - source
-			// 1 - mint = token address
-			// 2 - destination
-			// 3 - The source account's owner/delegate = wallet address
-			if len(inst.Accounts) < 4 {
-				return nil, fmt.Errorf("parse spl token instruction index %v account length %v less than 4", idx, len(inst.Accounts))
+			transfer, err := decodeSystemTransfer(inst.Data, accounts)
+			if err != nil {
+				return nil, fmt.Errorf("parse system instruction index %v: %w", idx, err)
 			}
-			// recipientIdx := inst.Accounts[0]
-			// sourceAccount := t.tx.Message.AccountKeys[recipientIdx]
-			recipientIdx := inst.Accounts[1]
-			mintAccount := t.tx.Message.AccountKeys[recipientIdx]
-			recipientIdx = inst.Accounts[2]
-			destinationAccount := t.tx.Message.AccountKeys[recipientIdx]
-			// recipientIdx = inst.Accounts[3]
-			//s ourceOwnerAccount := t.tx.Message.AccountKeys[recipientIdx]
-
-			if t.destinationAddress == "" {
-				return nil, fmt.Errorf("tx destination address is nil")
+			transfers = append(transfers, token_adapter.Transfer{
+				Chain:  chainID,
+				From:   transfer.from.String(),
+				To:     transfer.to.String(),
+				Amount: new(big.Int).SetUint64(transfer.amount),
+			})
+		case programID.Equals(solana.TokenProgramID) || programID.Equals(solana.Token2022ProgramID):
+			if !t.token.isSPLToken {
+				return nil, fmt.Errorf("spl token instruction index %v in native transaction", idx)
 			}
-			destinationOwnerAccount := solana.MustPublicKeyFromBase58(t.destinationAddress)
-
-			desTokenAccount, err := GetAssociatedTokenAddress(
-				destinationOwnerAccount,
-				mintAccount,
-				programID,
-			)
+			transfer, err := t.tokenTransfer(programID, inst.Data, accounts)
 			if err != nil {
-				return nil, fmt.Errorf("fail to get associated token address: %w", err)
+				return nil, fmt.Errorf("parse spl token instruction index %v: %w", idx, err)
+			}
+			transfer.Chain = chainID
+			transfers = append(transfers, *transfer)
+		case programID.Equals(solana.SPLAssociatedTokenAccountProgramID):
+			if !t.token.isSPLToken {
+				return nil, fmt.Errorf("associated token account instruction index %v in native transaction", idx)
+			}
+			create, err := decodeCreateAssociatedTokenAccount(inst.Data, accounts)
+			if err != nil {
+				return nil, fmt.Errorf("parse associated token account instruction index %v: %w", idx, err)
+			}
+			if err := t.token.checkMint(create.mint); err != nil {
+				return nil, fmt.Errorf("parse associated token account instruction index %v: %w", idx, err)
 			}
-			if desTokenAccount.Equals(destinationAccount) {
-				addresses = append(addresses, destinationOwnerAccount.String())
-			} else {
-				return nil, fmt.Errorf(
-					"parse spl token instruction index %v destination token address mismatch %s with %s",
-					idx, desTokenAccount, destinationAccount)
+		case programID.Equals(computeBudgetProgramID):
+			if err := decodeComputeBudget(inst.Data, budget); err != nil {
+				return nil, fmt.Errorf("parse compute budget instruction index %v: %w", idx, err)
 			}
+		case programID.Equals(solana.MemoProgramID) || programID.Equals(memoV1ProgramID):
+			memo = string(inst.Data)
+		default:
+			return nil, fmt.Errorf("instruction index %v of unknown program %v", idx, programID)
+		}
+	}
+
+	for i := range transfers {
+		transfers[i].Memo = memo
+	}
 
-			// parse inst.Data
+	return transfers, nil
+}
+
+// checkAdvanceNonce requires the nonce authority of a durable nonce transaction to be one of the source addresses
+func (t *Transaction) checkAdvanceNonce(data []byte, accounts solana.PublicKeySlice) error {
+	nonce, err := decodeAdvanceNonce(data, accounts)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(t.sourceAddresses, nonce.authority.String()) {
+		return fmt.Errorf("nonce authority %v of nonce account %v is not part of source addresses", nonce.authority, nonce.nonceAccount)
+	}
+	return nil
+}
+
+// tokenTransfer decodes an SPL token transfer of the token mint to the associated token account of the destination
+func (t *Transaction) tokenTransfer(programID solana.PublicKey, data []byte, accounts solana.PublicKeySlice) (*token_adapter.Transfer, error) {
+	transfer, err := decodeTokenTransfer(data, accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	mint, err := t.token.expectedMint()
+	if err != nil {
+		return nil, err
+	}
+	if transfer.mint != nil {
+		if err := t.token.checkMint(*transfer.mint); err != nil {
+			return nil, err
 		}
 	}
+	if decimals, ok := token_adapter.GetTokenDecimals(t.token.tokenID); ok && transfer.decimals != nil && int32(*transfer.decimals) != decimals {
+		return nil, fmt.Errorf("transfer decimals %v mismatch decimals %v of token %v", *transfer.decimals, decimals, t.token.tokenID)
+	}
 
-	if len(addresses) == 0 {
-		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	if t.destinationAddress == "" {
+		return nil, fmt.Errorf("tx destination address is nil")
+	}
+	destinationOwnerAccount, err := solana.PublicKeyFromBase58(t.destinationAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx destination address %v: %w", t.destinationAddress, err)
+	}
+
+	// the destination account of the mint also binds the mint of a Transfer, which has no mint account
+	desTokenAccount, err := GetAssociatedTokenAddress(destinationOwnerAccount, mint, programID)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get associated token address: %w", err)
+	}
+	if !desTokenAccount.Equals(transfer.destination) {
+		return nil, fmt.Errorf("destination token address mismatch %s with %s", desTokenAccount, transfer.destination)
 	}
 
-	return addresses, nil
+	return &token_adapter.Transfer{
+		Asset:  mint.String(),
+		From:   transfer.owner.String(),
+		To:     destinationOwnerAccount.String(),
+		Amount: new(big.Int).SetUint64(transfer.amount),
+	}, nil
+}
+
+// GetFee implements FeeTransaction interface for Solana, the fee limit is the signature fee plus the priority fee
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	budget := &computeBudget{}
+	instructions := 0
+	for idx, inst := range t.tx.Message.Instructions {
+		// a program is a static account
+		if int(inst.ProgramIDIndex) >= len(t.tx.Message.AccountKeys) {
+			return nil, fmt.Errorf("instruction index %v program index %d out of range", idx, inst.ProgramIDIndex)
+		}
+		if !t.tx.Message.AccountKeys[inst.ProgramIDIndex].Equals(computeBudgetProgramID) {
+			instructions++
+			continue
+		}
+		if err := decodeComputeBudget(inst.Data, budget); err != nil {
+			return nil, fmt.Errorf("parse compute budget instruction index %v: %w", idx, err)
+		}
+	}
+
+	unitLimit := budget.computeUnitLimit(instructions)
+	feeLimit := budget.priorityFee(unitLimit)
+	feeLimit.Add(feeLimit, big.NewInt(int64(t.tx.Message.Header.NumRequiredSignatures)*lamportsPerSignature))
+
+	fee := &token_adapter.Fee{
+		GasLimit: new(big.Int).SetUint64(unitLimit),
+		FeeLimit: feeLimit,
+	}
+	if budget.unitPrice != nil {
+		fee.GasPrice = new(big.Int).SetUint64(*budget.unitPrice)
+	}
+	return fee, nil
 }
 
 // ParseSolanaTransaction parses a raw transaction bytes into a Solana Transaction
@@ -1,8 +1,10 @@
 package solana
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gagliardetto/solana-go"
 	"github.com/stretchr/testify/assert"
@@ -98,8 +100,9 @@ func TestTransaction_GetDestinationAddresses_SPLToken(t *testing.T) {
 			destinationAddress: "8bJaa7p816rKnPSGTsZdWBDkAmsuKDnoEYzLRwrsuFV6",
 		},
 		token: &Token{
-			tokenID:    "USDC",
-			isSPLToken: true,
+			tokenID:     "USDC",
+			isSPLToken:  true,
+			mintAddress: usdcMintAddress,
 		},
 	}
 
@@ -174,3 +177,78 @@ func TestGetAssociatedTokenAddress(t *testing.T) {
 
 	assert.Equal(t, expectedAddress.String(), ataAddress.String(), "The computed ATA address should match the expected value")
 }
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		isSPLToken    bool
+		mintAddress   string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:       "SOL transfer",
+			rawTx:      solRawTx,
+			isSPLToken: false,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "SOL",
+				From:   "6ensJPEtJ24yZmTT9Vo4hVJT3k6hCGHWMMPgDbTdbhah",
+				To:     "C63eMJhWSxGhKEFFCSxnF7spphcPgsTnbYsZKjcwJ8Vp",
+				Amount: big.NewInt(11),
+			}},
+		},
+		{
+			name:        "SPL token transfer",
+			rawTx:       splTokenRawTx,
+			isSPLToken:  true,
+			mintAddress: usdcMintAddress,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "SOL",
+				Asset:  "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+				From:   "BuHGPDpMWLD8tH4NCsn71S6THNuAJGNXJ9P8pooULX2M",
+				To:     "8bJaa7p816rKnPSGTsZdWBDkAmsuKDnoEYzLRwrsuFV6",
+				Amount: big.NewInt(2000000),
+			}},
+		},
+		{
+			name:        "SPL token transfer of another mint",
+			rawTx:       splTokenRawTx,
+			isSPLToken:  true,
+			mintAddress: "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB",
+			wantError:   true,
+		},
+		{
+			name:       "SPL token transfer without mint",
+			rawTx:      splTokenRawTx,
+			isSPLToken: true,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTxBytes := common.FromHex(tt.rawTx)
+			tx, err := ParseSolanaTransaction(rawTxBytes)
+			assert.NoError(t, err)
+
+			solTx := &Transaction{
+				tx: tx,
+				PrepareTransactionData: &PrepareTransactionData{
+					rawTx:              rawTxBytes,
+					destinationAddress: "8bJaa7p816rKnPSGTsZdWBDkAmsuKDnoEYzLRwrsuFV6",
+				},
+				token: &Token{tokenID: "TEST", isSPLToken: tt.isSPLToken, mintAddress: tt.mintAddress},
+			}
+
+			transfers, err := solTx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, transfers)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTransfers, transfers)
+			}
+		})
+	}
+}
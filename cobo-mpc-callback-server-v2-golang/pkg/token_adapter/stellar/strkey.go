@@ -0,0 +1,35 @@
+package stellar
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+)
+
+// StrKey version bytes
+const (
+	versionAccountID    = 6 << 3
+	versionMuxedAccount = 12 << 3
+)
+
+// encodeStrKey returns the base32 of the version byte, the payload and their crc16, e.g. G... for an account
+func encodeStrKey(version byte, payload []byte) string {
+	b := append([]byte{version}, payload...)
+	b = binary.LittleEndian.AppendUint16(b, crc16(b))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+}
+
+// crc16 is the CRC-16/XMODEM checksum of a StrKey
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,39 @@
+package stellar
+
+import (
+	"encoding/base32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMuxedAccount_String(t *testing.T) {
+	// the accounts of SEP-23
+	b, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ")
+	assert.NoError(t, err)
+	var key [32]byte
+	copy(key[:], b[1:33])
+	zero := uint64(0)
+	large := uint64(1) << 63
+
+	tests := []struct {
+		name    string
+		account MuxedAccount
+		want    string
+	}{
+		{name: "Zero key", want: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"},
+		{name: "Account", account: MuxedAccount{key: key}, want: "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"},
+		{name: "Muxed account", account: MuxedAccount{key: key, id: &zero}, want: "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAAAAAAAACJUQ"},
+		{
+			name:    "Muxed account large id",
+			account: MuxedAccount{key: key, id: &large},
+			want:    "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.account.String())
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package stellar
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("XLM")
+	xlmToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "XLM", xlmToken.tokenID)
+}
+
+// stellarPayment is the unsigned public network envelope of a payment of 1 XLM with text memo "123456" from
+// GCKFBEIYV2U22IO2BJ4KVJOIP7XPWQGQFKKWXR6DOSJBV7STMAQSMTGG to GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7
+const stellarPayment = "000000020000000094509118aea9ad21da0a78aaa5c87feefb40d02a956bc7c374921afe53602126000000640301500813bac00100000001" +
+	"000000000000000000000000677485800000000100000006313233343536000000000001000000000000000100000000019472628ee78eb92714d22d0390963c24d9" +
+	"27ea009999486e891fac2daa9c210000000000000000009896800000000000000000"
+
+func TestToken_BuildTransaction(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:       "Payment",
+			rawTx:      stellarPayment,
+			wantHashes: []string{"0xe71225cfea263d7aec2d0b721f2f09b78f931b843119ffc360837dcc13e3b83d"},
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  defaultChainID,
+				From:   "GCKFBEIYV2U22IO2BJ4KVJOIP7XPWQGQFKKWXR6DOSJBV7STMAQSMTGG",
+				To:     "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7",
+				Amount: big.NewInt(10000000),
+				Memo:   "123456",
+				Tag:    "123456",
+			}},
+		},
+		{
+			name:      "Transaction without envelope",
+			rawTx:     stellarPayment[8 : len(stellarPayment)-8],
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction, err := NewToken("XLM").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := transaction.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := transaction.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package stellar
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+)
+
+const (
+	defaultChainID = "XLM"
+
+	// publicNetworkPassphrase identifies the Stellar public network, its hash prefixes the signature base
+	publicNetworkPassphrase = "Public Global Stellar Network ; September 2015"
+)
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx *TransactionEnvelope
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx   []byte
+	chainID string
+}
+
+// GetHashes implements Transaction interface for Stellar, the hash is the sha256 of the signature base:
+// the network id, the envelope type and the transaction
+func (t *Transaction) GetHashes() ([]string, error) {
+	if t.tx == nil || len(t.tx.tx) == 0 {
+		return nil, fmt.Errorf("transaction data is empty")
+	}
+
+	networkID := sha256.Sum256([]byte(publicNetworkPassphrase))
+	base := binary.BigEndian.AppendUint32(append([]byte{}, networkID[:]...), envelopeTypeTx)
+	hash := sha256.Sum256(append(base, t.tx.tx...))
+	return []string{"0x" + hex.EncodeToString(hash[:])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for Stellar
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Stellar, each operation is a transfer of the sent asset and
+// amount, the most spent by a path payment. The memo is both the memo and the tag, since exchanges tell their users
+// apart by memo.
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	var transfers []token_adapter.Transfer
+	for _, op := range t.tx.operations {
+		transfer := token_adapter.Transfer{
+			Chain:  chainID,
+			Asset:  op.sendAsset.String(),
+			From:   t.tx.source.String(),
+			To:     op.destination.String(),
+			Amount: big.NewInt(op.sendAmount),
+			Memo:   t.tx.memo,
+			Tag:    t.tx.memo,
+		}
+		if op.kind != operationPayment {
+			transfer.Method = operationNames[op.kind]
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
+
+// GetFee implements FeeTransaction interface for Stellar, the fee limit is the max fee of the transaction in stroops
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	return &token_adapter.Fee{FeeLimit: big.NewInt(int64(t.tx.fee))}, nil
+}
@@ -0,0 +1,390 @@
+package stellar
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	envelopeTypeTx = 2
+
+	keyTypeEd25519      = 0
+	keyTypeMuxedEd25519 = 0x100
+
+	preconditionNone = 0
+	preconditionTime = 1
+
+	memoNone   = 0
+	memoText   = 1
+	memoID     = 2
+	memoHash   = 3
+	memoReturn = 4
+
+	assetTypeNative     = 0
+	assetTypeAlphaNum4  = 1
+	assetTypeAlphaNum12 = 2
+
+	operationPayment                  = 1
+	operationPathPaymentStrictReceive = 2
+	operationPathPaymentStrictSend    = 13
+
+	maxMemoText   = 28
+	maxOperations = 100
+	maxSignatures = 20
+	maxPathAssets = 5
+	maxSignature  = 64
+)
+
+// operationNames names the supported operations
+var operationNames = map[uint32]string{
+	operationPayment:                  "payment",
+	operationPathPaymentStrictReceive: "pathPaymentStrictReceive",
+	operationPathPaymentStrictSend:    "pathPaymentStrictSend",
+}
+
+// MuxedAccount is an account, with the id of a muxed account
+type MuxedAccount struct {
+	key [32]byte
+	// id is nil for an account that is not muxed
+	id *uint64
+}
+
+// String returns the G... StrKey of the account, or the M... StrKey of a muxed account
+func (a MuxedAccount) String() string {
+	if a.id == nil {
+		return encodeStrKey(versionAccountID, a.key[:])
+	}
+	return encodeStrKey(versionMuxedAccount, binary.BigEndian.AppendUint64(append([]byte{}, a.key[:]...), *a.id))
+}
+
+// Asset is the native asset or an issued asset
+type Asset struct {
+	// code is empty for the native asset
+	code   string
+	issuer [32]byte
+}
+
+// String returns the code and the issuer of an issued asset, e.g. USDC:GA5Z..., empty for the native asset
+func (a Asset) String() string {
+	if a.code == "" {
+		return ""
+	}
+	return a.code + ":" + encodeStrKey(versionAccountID, a.issuer[:])
+}
+
+// Operation is a payment or a path payment. The sent amount is the amount of a payment, the send max of
+// a strict receive path payment or the send amount of a strict send path payment.
+type Operation struct {
+	kind        uint32
+	destination MuxedAccount
+	sendAsset   Asset
+	sendAmount  int64
+}
+
+// TransactionEnvelope is a decoded envelope of a transaction of payments
+type TransactionEnvelope struct {
+	// tx is the XDR of the transaction, whose hash with the network id is signed
+	tx     []byte
+	source MuxedAccount
+	// fee is the max fee of all the operations in stroops
+	fee uint32
+	// memo is the text, the id in decimal or the hash in hex, empty for no memo
+	memo       string
+	operations []Operation
+}
+
+// ParseTransactionEnvelope decodes the XDR of a transaction envelope. A fee bump or a v0 envelope is rejected,
+// as are the v2 preconditions, a Soroban extension and any operation other than a payment or a path payment.
+func ParseTransactionEnvelope(rawTx []byte) (*TransactionEnvelope, error) {
+	d := &decoder{data: rawTx}
+	envelopeType, err := d.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if envelopeType != envelopeTypeTx {
+		return nil, fmt.Errorf("unsupported envelope type %d", envelopeType)
+	}
+
+	start := d.offset
+	envelope := &TransactionEnvelope{}
+	if err := envelope.readTransaction(d); err != nil {
+		return nil, fmt.Errorf("decode transaction: %w", err)
+	}
+	envelope.tx = rawTx[start:d.offset]
+
+	if err := readSignatures(d); err != nil {
+		return nil, fmt.Errorf("decode signatures: %w", err)
+	}
+	if d.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after envelope", d.remaining())
+	}
+	return envelope, nil
+}
+
+func (e *TransactionEnvelope) readTransaction(d *decoder) (err error) {
+	if e.source, err = readMuxedAccount(d); err != nil {
+		return fmt.Errorf("decode source account: %w", err)
+	}
+	if e.fee, err = d.readUint32(); err != nil {
+		return err
+	}
+	if _, err := d.readInt64(); err != nil {
+		return fmt.Errorf("decode sequence number: %w", err)
+	}
+	if err := readPreconditions(d); err != nil {
+		return fmt.Errorf("decode preconditions: %w", err)
+	}
+	if e.memo, err = readMemo(d); err != nil {
+		return fmt.Errorf("decode memo: %w", err)
+	}
+
+	count, err := d.readArrayLength(maxOperations)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		op, err := e.readOperation(d)
+		if err != nil {
+			return fmt.Errorf("decode operation %d: %w", i, err)
+		}
+		e.operations = append(e.operations, op)
+	}
+	if count == 0 {
+		return fmt.Errorf("transaction has no operation")
+	}
+
+	ext, err := d.readUint32()
+	if err != nil {
+		return err
+	}
+	if ext != 0 {
+		return fmt.Errorf("unsupported transaction extension %d", ext)
+	}
+	return nil
+}
+
+func readMuxedAccount(d *decoder) (MuxedAccount, error) {
+	var account MuxedAccount
+	keyType, err := d.readUint32()
+	if err != nil {
+		return account, err
+	}
+	switch keyType {
+	case keyTypeEd25519:
+	case keyTypeMuxedEd25519:
+		id, err := d.readUint64()
+		if err != nil {
+			return account, err
+		}
+		account.id = &id
+	default:
+		return account, fmt.Errorf("unsupported key type %d", keyType)
+	}
+
+	key, err := d.readBytes(32)
+	if err != nil {
+		return account, err
+	}
+	copy(account.key[:], key)
+	return account, nil
+}
+
+// readAccountID reads an ed25519 public key
+func readAccountID(d *decoder) ([32]byte, error) {
+	var key [32]byte
+	keyType, err := d.readUint32()
+	if err != nil {
+		return key, err
+	}
+	if keyType != keyTypeEd25519 {
+		return key, fmt.Errorf("unsupported public key type %d", keyType)
+	}
+	b, err := d.readBytes(32)
+	if err != nil {
+		return key, err
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+// readPreconditions reads no precondition or time bounds
+func readPreconditions(d *decoder) error {
+	kind, err := d.readUint32()
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case preconditionNone:
+		return nil
+	case preconditionTime:
+		_, err := d.readBytes(16)
+		return err
+	default:
+		return fmt.Errorf("unsupported preconditions type %d", kind)
+	}
+}
+
+func readMemo(d *decoder) (string, error) {
+	kind, err := d.readUint32()
+	if err != nil {
+		return "", err
+	}
+	switch kind {
+	case memoNone:
+		return "", nil
+	case memoText:
+		text, err := d.readOpaque(maxMemoText)
+		if err != nil {
+			return "", err
+		}
+		if !utf8.Valid(text) {
+			return "", fmt.Errorf("memo text is not UTF-8")
+		}
+		return string(text), nil
+	case memoID:
+		id, err := d.readUint64()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(id, 10), nil
+	case memoHash, memoReturn:
+		hash, err := d.readBytes(32)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash), nil
+	default:
+		return "", fmt.Errorf("unsupported memo type %d", kind)
+	}
+}
+
+// readOperation reads a payment or a path payment, an operation with a source account other than the transaction
+// source account is rejected
+func (e *TransactionEnvelope) readOperation(d *decoder) (op Operation, err error) {
+	hasSource, err := d.readBool()
+	if err != nil {
+		return op, err
+	}
+	if hasSource {
+		source, err := readMuxedAccount(d)
+		if err != nil {
+			return op, fmt.Errorf("decode source account: %w", err)
+		}
+		if source.key != e.source.key {
+			return op, fmt.Errorf("operation source account %v is not the transaction source account", source)
+		}
+	}
+
+	if op.kind, err = d.readUint32(); err != nil {
+		return op, err
+	}
+	switch op.kind {
+	case operationPayment:
+		if op.destination, err = readMuxedAccount(d); err != nil {
+			return op, fmt.Errorf("decode destination: %w", err)
+		}
+		if op.sendAsset, err = readAsset(d); err != nil {
+			return op, err
+		}
+		op.sendAmount, err = d.readInt64()
+		return op, err
+	case operationPathPaymentStrictReceive, operationPathPaymentStrictSend:
+		// send asset and send max or send amount, destination, destination asset and amount, path
+		if op.sendAsset, err = readAsset(d); err != nil {
+			return op, err
+		}
+		if op.sendAmount, err = d.readInt64(); err != nil {
+			return op, err
+		}
+		if op.destination, err = readMuxedAccount(d); err != nil {
+			return op, fmt.Errorf("decode destination: %w", err)
+		}
+		if _, err := readAsset(d); err != nil {
+			return op, err
+		}
+		if _, err := d.readInt64(); err != nil {
+			return op, err
+		}
+		count, err := d.readArrayLength(maxPathAssets)
+		if err != nil {
+			return op, err
+		}
+		for i := 0; i < count; i++ {
+			if _, err := readAsset(d); err != nil {
+				return op, fmt.Errorf("decode path asset %d: %w", i, err)
+			}
+		}
+		return op, nil
+	default:
+		return op, fmt.Errorf("unsupported operation type %d", op.kind)
+	}
+}
+
+func readAsset(d *decoder) (Asset, error) {
+	var asset Asset
+	assetType, err := d.readUint32()
+	if err != nil {
+		return asset, err
+	}
+
+	var codeLength int
+	switch assetType {
+	case assetTypeNative:
+		return asset, nil
+	case assetTypeAlphaNum4:
+		codeLength = 4
+	case assetTypeAlphaNum12:
+		codeLength = 12
+	default:
+		return asset, fmt.Errorf("unsupported asset type %d", assetType)
+	}
+
+	code, err := d.readBytes(codeLength)
+	if err != nil {
+		return asset, err
+	}
+	if asset.code, err = assetCode(code); err != nil {
+		return asset, err
+	}
+	if asset.issuer, err = readAccountID(d); err != nil {
+		return asset, fmt.Errorf("decode asset issuer: %w", err)
+	}
+	return asset, nil
+}
+
+// assetCode returns the alphanumeric code padded with zeros
+func assetCode(b []byte) (string, error) {
+	length := len(b)
+	for length > 0 && b[length-1] == 0 {
+		length--
+	}
+	if length == 0 {
+		return "", fmt.Errorf("asset code is empty")
+	}
+	for _, c := range b[:length] {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return "", fmt.Errorf("invalid asset code %q", b)
+		}
+	}
+	return string(b[:length]), nil
+}
+
+// readSignatures reads the decorated signatures of the envelope, each a hint and a signature
+func readSignatures(d *decoder) error {
+	count, err := d.readArrayLength(maxSignatures)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		if _, err := d.readBytes(4); err != nil {
+			return err
+		}
+		if _, err := d.readOpaque(maxSignature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package stellar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(seed byte) [32]byte {
+	return [32]byte{seed, 31: seed}
+}
+
+func xdrUint32(v uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, v)
+}
+
+func xdrUint64(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+// xdrOpaque returns a variable length opaque padded to a multiple of 4 bytes
+func xdrOpaque(b []byte) []byte {
+	out := append(xdrUint32(uint32(len(b))), b...)
+	return append(out, make([]byte, (4-len(b)%4)%4)...)
+}
+
+func accountXDR(key [32]byte) []byte {
+	return append(xdrUint32(keyTypeEd25519), key[:]...)
+}
+
+func muxedAccountXDR(key [32]byte, id uint64) []byte {
+	return bytes.Join([][]byte{xdrUint32(keyTypeMuxedEd25519), xdrUint64(id), key[:]}, nil)
+}
+
+func nativeAssetXDR() []byte {
+	return xdrUint32(assetTypeNative)
+}
+
+// creditAssetXDR returns an alphanum4 asset, or an alphanum12 asset for a code longer than 4 characters
+func creditAssetXDR(code string, issuer [32]byte) []byte {
+	if len(code) <= 4 {
+		return bytes.Join([][]byte{xdrUint32(assetTypeAlphaNum4), []byte(code), make([]byte, 4-len(code)), accountXDR(issuer)}, nil)
+	}
+	return bytes.Join([][]byte{xdrUint32(assetTypeAlphaNum12), []byte(code), make([]byte, 12-len(code)), accountXDR(issuer)}, nil)
+}
+
+func paymentOpXDR(destination []byte, asset []byte, amount uint64) []byte {
+	return bytes.Join([][]byte{xdrUint32(0), xdrUint32(operationPayment), destination, asset, xdrUint64(amount)}, nil)
+}
+
+// pathPaymentOpXDR returns a path payment of the send asset to the native asset through the path
+func pathPaymentOpXDR(kind uint32, sendAsset []byte, sendAmount uint64, destination []byte, path ...[]byte) []byte {
+	return bytes.Join([][]byte{
+		xdrUint32(0), xdrUint32(kind), sendAsset, xdrUint64(sendAmount), destination, nativeAssetXDR(), xdrUint64(1),
+		xdrUint32(uint32(len(path))), bytes.Join(path, nil),
+	}, nil)
+}
+
+func textMemoXDR(text string) []byte {
+	return append(xdrUint32(memoText), xdrOpaque([]byte(text))...)
+}
+
+// transactionXDR returns a transaction with a fee of 100 stroops per operation and time bounds
+func transactionXDR(source []byte, memo []byte, ops ...[]byte) []byte {
+	if memo == nil {
+		memo = xdrUint32(memoNone)
+	}
+	return bytes.Join([][]byte{
+		source, xdrUint32(uint32(100 * len(ops))), xdrUint64(123456789), xdrUint32(preconditionTime), make([]byte, 16),
+		memo, xdrUint32(uint32(len(ops))), bytes.Join(ops, nil), xdrUint32(0),
+	}, nil)
+}
+
+// envelopeXDR returns the envelope of the transaction with a signature
+func envelopeXDR(tx []byte) []byte {
+	return bytes.Join([][]byte{xdrUint32(envelopeTypeTx), tx, xdrUint32(1), {1, 2, 3, 4}, xdrOpaque(make([]byte, 64))}, nil)
+}
+
+func TestParseTransactionEnvelope(t *testing.T) {
+	source := accountXDR(testKey(1))
+	destination := accountXDR(testKey(2))
+	payment := paymentOpXDR(destination, nativeAssetXDR(), 10000000)
+	tx := transactionXDR(source, nil, payment)
+
+	tests := []struct {
+		name      string
+		rawTx     []byte
+		wantMemo  string
+		wantOps   int
+		wantError bool
+	}{
+		{
+			name:    "Payment",
+			rawTx:   envelopeXDR(tx),
+			wantOps: 1,
+		},
+		{
+			name:    "Unsigned payment",
+			rawTx:   append(append(xdrUint32(envelopeTypeTx), tx...), xdrUint32(0)...),
+			wantOps: 1,
+		},
+		{
+			name:     "Text memo",
+			rawTx:    envelopeXDR(transactionXDR(source, textMemoXDR("invoice 42"), payment)),
+			wantMemo: "invoice 42",
+			wantOps:  1,
+		},
+		{
+			name:     "Id memo",
+			rawTx:    envelopeXDR(transactionXDR(source, append(xdrUint32(memoID), xdrUint64(123456)...), payment)),
+			wantMemo: "123456",
+			wantOps:  1,
+		},
+		{
+			name: "Hash memo",
+			rawTx: envelopeXDR(transactionXDR(source, append(xdrUint32(memoHash), bytes.Repeat([]byte{0xab}, 32)...),
+				payment)),
+			wantMemo: string(bytes.Repeat([]byte("ab"), 32)),
+			wantOps:  1,
+		},
+		{
+			name: "Path payments",
+			rawTx: envelopeXDR(transactionXDR(source, nil,
+				pathPaymentOpXDR(operationPathPaymentStrictReceive, creditAssetXDR("USDC", testKey(3)), 5, destination),
+				pathPaymentOpXDR(operationPathPaymentStrictSend, creditAssetXDR("LONGCODE", testKey(3)), 5, destination,
+					nativeAssetXDR()),
+			)),
+			wantOps: 2,
+		},
+		{
+			name: "Operation source account is the transaction source account",
+			rawTx: envelopeXDR(transactionXDR(source, nil,
+				bytes.Join([][]byte{xdrUint32(1), muxedAccountXDR(testKey(1), 7), payment[4:]}, nil))),
+			wantOps: 1,
+		},
+		{
+			name:      "Operation source account of another account",
+			rawTx:     envelopeXDR(transactionXDR(source, nil, bytes.Join([][]byte{xdrUint32(1), destination, payment[4:]}, nil))),
+			wantError: true,
+		},
+		{
+			name: "Unsupported operation",
+			rawTx: envelopeXDR(transactionXDR(source, nil,
+				bytes.Join([][]byte{xdrUint32(0), xdrUint32(0), destination, xdrUint64(10000000)}, nil))),
+			wantError: true,
+		},
+		{
+			name:      "No operation",
+			rawTx:     envelopeXDR(transactionXDR(source, nil)),
+			wantError: true,
+		},
+		{
+			name:      "Text memo too long",
+			rawTx:     envelopeXDR(transactionXDR(source, textMemoXDR(string(bytes.Repeat([]byte("a"), 29))), payment)),
+			wantError: true,
+		},
+		{
+			name:      "Invalid asset code",
+			rawTx:     envelopeXDR(transactionXDR(source, nil, paymentOpXDR(destination, creditAssetXDR("U-D", testKey(3)), 5))),
+			wantError: true,
+		},
+		{
+			name:      "Fee bump envelope",
+			rawTx:     append(xdrUint32(5), envelopeXDR(tx)[4:]...),
+			wantError: true,
+		},
+		{
+			name:      "Trailing bytes",
+			rawTx:     append(envelopeXDR(tx), 0, 0, 0, 0),
+			wantError: true,
+		},
+		{
+			name:      "Truncated",
+			rawTx:     envelopeXDR(tx)[:40],
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := ParseTransactionEnvelope(tt.rawTx)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testKey(1), envelope.source.key)
+			assert.Equal(t, tt.wantMemo, envelope.memo)
+			assert.Len(t, envelope.operations, tt.wantOps)
+			assert.Equal(t, uint32(100*tt.wantOps), envelope.fee)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package stellar
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	from := MuxedAccount{key: testKey(1)}
+	to := MuxedAccount{key: testKey(2)}
+	issuer := MuxedAccount{key: testKey(3)}
+	id := uint64(42)
+	muxedTo := MuxedAccount{key: testKey(2), id: &id}
+
+	tests := []struct {
+		name          string
+		rawTx         []byte
+		wantTransfers []token_adapter.Transfer
+		wantFee       int64
+	}{
+		{
+			name: "Payment with memo",
+			rawTx: envelopeXDR(transactionXDR(accountXDR(testKey(1)), textMemoXDR("123456"),
+				paymentOpXDR(accountXDR(testKey(2)), nativeAssetXDR(), 10000000))),
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, From: from.String(), To: to.String(), Amount: big.NewInt(10000000), Memo: "123456", Tag: "123456"},
+			},
+			wantFee: 100,
+		},
+		{
+			name: "Issued asset payment to a muxed account",
+			rawTx: envelopeXDR(transactionXDR(accountXDR(testKey(1)), nil,
+				paymentOpXDR(muxedAccountXDR(testKey(2), 42), creditAssetXDR("USDC", testKey(3)), 5))),
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, Asset: "USDC:" + issuer.String(), From: from.String(), To: muxedTo.String(), Amount: big.NewInt(5)},
+			},
+			wantFee: 100,
+		},
+		{
+			name: "Path payments",
+			rawTx: envelopeXDR(transactionXDR(accountXDR(testKey(1)), nil,
+				pathPaymentOpXDR(operationPathPaymentStrictReceive, creditAssetXDR("USDC", testKey(3)), 7, accountXDR(testKey(2))),
+				pathPaymentOpXDR(operationPathPaymentStrictSend, nativeAssetXDR(), 9, accountXDR(testKey(2))),
+			)),
+			wantTransfers: []token_adapter.Transfer{
+				{
+					Chain: defaultChainID, Asset: "USDC:" + issuer.String(), From: from.String(), To: to.String(), Amount: big.NewInt(7),
+					Method: "pathPaymentStrictReceive",
+				},
+				{Chain: defaultChainID, From: from.String(), To: to.String(), Amount: big.NewInt(9), Method: "pathPaymentStrictSend"},
+			},
+			wantFee: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := ParseTransactionEnvelope(tt.rawTx)
+			assert.NoError(t, err)
+			tx := &Transaction{tx: envelope, PrepareTransactionData: &PrepareTransactionData{rawTx: tt.rawTx}}
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			addresses, err := tx.GetDestinationAddresses()
+			assert.NoError(t, err)
+			assert.Equal(t, token_adapter.DestinationAddresses(tt.wantTransfers), addresses)
+
+			fee, err := tx.GetFee()
+			assert.NoError(t, err)
+			assert.Equal(t, big.NewInt(tt.wantFee), fee.FeeLimit)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package stellar
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// decoder reads the XDR of a transaction envelope
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.offset
+}
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > d.remaining() {
+		return nil, fmt.Errorf("read %d bytes at offset %d: out of range", n, d.offset)
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *decoder) readUint32() (uint32, error) {
+	b, err := d.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+func (d *decoder) readUint64() (uint64, error) {
+	b, err := d.readBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// readInt64 reads an int64, a negative value is rejected since the amounts and sequence numbers are positive
+func (d *decoder) readInt64() (int64, error) {
+	value, err := d.readUint64()
+	if err != nil {
+		return 0, err
+	}
+	if int64(value) < 0 {
+		return 0, fmt.Errorf("negative int64 %d", int64(value))
+	}
+	return int64(value), nil
+}
+
+func (d *decoder) readBool() (bool, error) {
+	value, err := d.readUint32()
+	if err != nil {
+		return false, err
+	}
+	if value > 1 {
+		return false, fmt.Errorf("invalid bool %d", value)
+	}
+	return value == 1, nil
+}
+
+// readOpaque reads a variable length opaque of at most max bytes, padded with zeros to a multiple of 4 bytes
+func (d *decoder) readOpaque(max int) ([]byte, error) {
+	length, err := d.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if length > uint32(max) {
+		return nil, fmt.Errorf("opaque of %d bytes exceeds %d", length, max)
+	}
+	b, err := d.readBytes(int(length))
+	if err != nil {
+		return nil, err
+	}
+	padding, err := d.readBytes((4 - int(length)%4) % 4)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range padding {
+		if v != 0 {
+			return nil, fmt.Errorf("non-zero padding")
+		}
+	}
+	return b, nil
+}
+
+// readArrayLength reads the length of an array of at most max items
+func (d *decoder) readArrayLength(max int) (int, error) {
+	length, err := d.readUint32()
+	if err != nil {
+		return 0, err
+	}
+	if length > uint32(max) {
+		return 0, fmt.Errorf("array of %d items exceeds %d", length, max)
+	}
+	return int(length), nil
+}
@@ -0,0 +1,20 @@
+package substrate
+
+import (
+	"github.com/shengdoushi/base58"
+	"golang.org/x/crypto/blake2b"
+)
+
+// ss58Prefix is hashed before the address to compute its checksum
+var ss58Prefix = []byte("SS58PRE")
+
+// AccountID is the 32 bytes public key of an account
+type AccountID [32]byte
+
+// SS58 returns the SS58 address of the account for a network prefix below 64, the base58 of the prefix,
+// the account and the first two bytes of their blake2b-512 checksum, e.g. 1... on Polkadot
+func (a AccountID) SS58(prefix byte) string {
+	payload := append([]byte{prefix}, a[:]...)
+	checksum := blake2b.Sum512(append(append([]byte{}, ss58Prefix...), payload...))
+	return base58.Encode(append(payload, checksum[:2]...), base58.BitcoinAlphabet)
+}
@@ -0,0 +1,18 @@
+package substrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// alice is the public key of the well-known development account Alice
+var alice = AccountID{
+	0xd4, 0x35, 0x93, 0xc7, 0x15, 0xfd, 0xd3, 0x1c, 0x61, 0x14, 0x1a, 0xbd, 0x04, 0xa9, 0x9f, 0xd6,
+	0x82, 0x2c, 0x85, 0x58, 0x85, 0x4c, 0xcd, 0xe3, 0x9a, 0x56, 0x84, 0xe7, 0xa5, 0x6d, 0xa2, 0x7d,
+}
+
+func TestAccountID_SS58(t *testing.T) {
+	assert.Equal(t, "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5", alice.SS58(0))
+	assert.Equal(t, "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", alice.SS58(42))
+}
@@ -0,0 +1,153 @@
+package substrate
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const (
+	// calls of the balances pallet
+	callTransferAllowDeath = 0
+	callTransferKeepAlive  = 3
+
+	// multiAddressID is the MultiAddress variant of an account id
+	multiAddressID = 0
+
+	// metadata hash modes of the CheckMetadataHash extension
+	metadataHashDisabled = 0
+	metadataHashEnabled  = 1
+)
+
+// callNames names the supported calls of the balances pallet
+var callNames = map[byte]string{
+	callTransferAllowDeath: "transferAllowDeath",
+	callTransferKeepAlive:  "transferKeepAlive",
+}
+
+// SigningPayload is a decoded signing payload of a balances transfer: the call, the signed extensions and
+// their additional signed data
+type SigningPayload struct {
+	method byte
+	dest   AccountID
+	// value in the base unit, e.g. planck
+	value *big.Int
+
+	// immortal is true for an immortal era, the block hash is then the genesis hash
+	immortal    bool
+	nonce       *big.Int
+	tip         *big.Int
+	specVersion uint32
+	txVersion   uint32
+	genesisHash [32]byte
+	blockHash   [32]byte
+}
+
+// ParseSigningPayload decodes a signing payload whose call is a transfer of the balances pallet.
+// The signed extensions are those of the Polkadot relay chain: the era, the nonce, the tip and the metadata hash mode,
+// followed by the spec version, the transaction version, the genesis hash, the block hash and the metadata hash.
+func ParseSigningPayload(payload []byte, balancesPallet byte) (*SigningPayload, error) {
+	d := &decoder{data: payload}
+	p := &SigningPayload{}
+
+	if err := p.readCall(d, balancesPallet); err != nil {
+		return nil, fmt.Errorf("decode call: %w", err)
+	}
+	if err := p.readExtra(d); err != nil {
+		return nil, fmt.Errorf("decode signed extensions: %w", err)
+	}
+	if d.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after signing payload", d.remaining())
+	}
+	return p, nil
+}
+
+func (p *SigningPayload) readCall(d *decoder, balancesPallet byte) error {
+	pallet, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	if p.method, err = d.readByte(); err != nil {
+		return err
+	}
+	if _, ok := callNames[p.method]; pallet != balancesPallet || !ok {
+		return fmt.Errorf("unsupported call %d of pallet %d", p.method, pallet)
+	}
+
+	variant, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	if variant != multiAddressID {
+		return fmt.Errorf("unsupported destination address variant %d", variant)
+	}
+	if p.dest, err = d.readHash(); err != nil {
+		return err
+	}
+	p.value, err = d.readCompact()
+	return err
+}
+
+func (p *SigningPayload) readExtra(d *decoder) (err error) {
+	if err := p.readEra(d); err != nil {
+		return fmt.Errorf("decode era: %w", err)
+	}
+	if p.nonce, err = d.readCompact(); err != nil {
+		return fmt.Errorf("decode nonce: %w", err)
+	}
+	if p.tip, err = d.readCompact(); err != nil {
+		return fmt.Errorf("decode tip: %w", err)
+	}
+	mode, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	if mode != metadataHashDisabled && mode != metadataHashEnabled {
+		return fmt.Errorf("invalid metadata hash mode %d", mode)
+	}
+
+	if p.specVersion, err = d.readU32(); err != nil {
+		return err
+	}
+	if p.txVersion, err = d.readU32(); err != nil {
+		return err
+	}
+	if p.genesisHash, err = d.readHash(); err != nil {
+		return err
+	}
+	if p.blockHash, err = d.readHash(); err != nil {
+		return err
+	}
+
+	// the metadata hash is present only when the mode enables it
+	hasMetadataHash, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	if hasMetadataHash != mode {
+		return fmt.Errorf("metadata hash option %d mismatches mode %d", hasMetadataHash, mode)
+	}
+	if hasMetadataHash == metadataHashEnabled {
+		_, err = d.readHash()
+	}
+	return err
+}
+
+// readEra reads an immortal era, a zero byte, or a mortal era of two bytes whose low four bits are the log2 of
+// the period minus one
+func (p *SigningPayload) readEra(d *decoder) error {
+	first, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	if first == 0 {
+		p.immortal = true
+		return nil
+	}
+	if _, err := d.readByte(); err != nil {
+		return err
+	}
+	if first&0x0f == 0 {
+		return fmt.Errorf("invalid mortal era period")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package substrate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testBlockHash = [32]byte{0xbb}
+
+func transferCall(pallet, method byte, dest AccountID, value uint64) []byte {
+	return append(append([]byte{pallet, method, multiAddressID}, dest[:]...), compact(value)...)
+}
+
+// encodePayload returns the signing payload of the call with a mortal era, the nonce 7 and the metadata hash
+// when enabled
+func encodePayload(call []byte, tip uint64, metadataHash bool, genesisHash, blockHash [32]byte) []byte {
+	payload := append(append([]byte{}, call...), 0x15, 0x03)
+	payload = append(payload, compact(7)...)
+	payload = append(payload, compact(tip)...)
+	mode := byte(metadataHashDisabled)
+	if metadataHash {
+		mode = metadataHashEnabled
+	}
+	payload = append(payload, mode)
+	payload = binary.LittleEndian.AppendUint32(payload, 1003000)
+	payload = binary.LittleEndian.AppendUint32(payload, 26)
+	payload = append(append(payload, genesisHash[:]...), blockHash[:]...)
+	if metadataHash {
+		return append(append(payload, 1), bytes.Repeat([]byte{0xcc}, 32)...)
+	}
+	return append(payload, 0)
+}
+
+func TestParseSigningPayload(t *testing.T) {
+	genesis := PolkadotNetwork.GenesisHash
+	call := transferCall(5, callTransferKeepAlive, alice, 10000000000)
+	valid := encodePayload(call, 0, true, genesis, testBlockHash)
+
+	tests := []struct {
+		name         string
+		payload      []byte
+		wantMethod   byte
+		wantImmortal bool
+		wantError    bool
+	}{
+		{
+			name:       "Transfer keep alive",
+			payload:    valid,
+			wantMethod: callTransferKeepAlive,
+		},
+		{
+			name:       "Transfer allow death without metadata hash",
+			payload:    encodePayload(transferCall(5, callTransferAllowDeath, alice, 10000000000), 0, false, genesis, testBlockHash),
+			wantMethod: callTransferAllowDeath,
+		},
+		{
+			name:         "Immortal era",
+			payload:      bytes.Replace(valid, append(append([]byte{}, call...), 0x15, 0x03), append(append([]byte{}, call...), 0x00), 1),
+			wantMethod:   callTransferKeepAlive,
+			wantImmortal: true,
+		},
+		{
+			name:      "Transfer all",
+			payload:   encodePayload(append([]byte{5, 4, multiAddressID}, append(alice[:], 0)...), 0, true, genesis, testBlockHash),
+			wantError: true,
+		},
+		{
+			name:      "Call of another pallet",
+			payload:   encodePayload(transferCall(10, callTransferKeepAlive, alice, 1), 0, true, genesis, testBlockHash),
+			wantError: true,
+		},
+		{
+			name: "Address20 destination",
+			payload: encodePayload(append(append([]byte{5, callTransferKeepAlive, 4}, make([]byte, 20)...), compact(1)...), 0, true,
+				genesis, testBlockHash),
+			wantError: true,
+		},
+		{
+			name:      "Metadata hash mismatches mode",
+			payload:   append(encodePayload(call, 0, false, genesis, testBlockHash)[:len(valid)-33], 1),
+			wantError: true,
+		},
+		{
+			name:      "Trailing bytes",
+			payload:   append(append([]byte{}, valid...), 0),
+			wantError: true,
+		},
+		{
+			name:      "Truncated",
+			payload:   valid[:len(valid)-1],
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := ParseSigningPayload(tt.payload, 5)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMethod, payload.method)
+			assert.Equal(t, alice, payload.dest)
+			assert.Equal(t, big.NewInt(10000000000), payload.value)
+			assert.Equal(t, tt.wantImmortal, payload.immortal)
+			assert.Equal(t, big.NewInt(7), payload.nonce)
+			assert.Equal(t, [32]byte(genesis), payload.genesisHash)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package substrate
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// maxCompactBytes is the size of a u128, the widest compact integer decoded
+const maxCompactBytes = 16
+
+// decoder reads the SCALE codec of a signing payload
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.offset
+}
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > d.remaining() {
+		return nil, fmt.Errorf("read %d bytes at offset %d: out of range", n, d.offset)
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *decoder) readByte() (byte, error) {
+	b, err := d.readBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *decoder) readU32() (uint32, error) {
+	b, err := d.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (d *decoder) readHash() ([32]byte, error) {
+	var hash [32]byte
+	b, err := d.readBytes(32)
+	if err != nil {
+		return hash, err
+	}
+	copy(hash[:], b)
+	return hash, nil
+}
+
+// readCompact reads a compact integer, the two low bits of the first byte tell its mode.
+// A value encoded in a wider mode than needed is rejected.
+func (d *decoder) readCompact() (*big.Int, error) {
+	first, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var value, min uint64
+	switch first & 0x03 {
+	case 0:
+		return big.NewInt(int64(first >> 2)), nil
+	case 1:
+		next, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		value, min = uint64(binary.LittleEndian.Uint16([]byte{first, next})>>2), 1<<6
+	case 2:
+		rest, err := d.readBytes(3)
+		if err != nil {
+			return nil, err
+		}
+		value, min = uint64(binary.LittleEndian.Uint32(append([]byte{first}, rest...))>>2), 1<<14
+	default:
+		length := int(first>>2) + 4
+		if length > maxCompactBytes {
+			return nil, fmt.Errorf("compact integer of %d bytes", length)
+		}
+		b, err := d.readBytes(length)
+		if err != nil {
+			return nil, err
+		}
+		if b[length-1] == 0 {
+			return nil, fmt.Errorf("non-canonical compact integer")
+		}
+		// little endian to big endian
+		reversed := make([]byte, length)
+		for i, v := range b {
+			reversed[length-1-i] = v
+		}
+		result := new(big.Int).SetBytes(reversed)
+		if result.Cmp(big.NewInt(1<<30)) < 0 {
+			return nil, fmt.Errorf("non-canonical compact integer")
+		}
+		return result, nil
+	}
+
+	if value < min {
+		return nil, fmt.Errorf("non-canonical compact integer")
+	}
+	return new(big.Int).SetUint64(value), nil
+}
@@ -0,0 +1,64 @@
+package substrate
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// compact encodes a compact integer in the narrowest mode
+func compact(value uint64) []byte {
+	switch {
+	case value < 1<<6:
+		return []byte{byte(value << 2)}
+	case value < 1<<14:
+		return binary.LittleEndian.AppendUint16(nil, uint16(value<<2|1))
+	case value < 1<<30:
+		return binary.LittleEndian.AppendUint32(nil, uint32(value<<2|2))
+	default:
+		b := binary.LittleEndian.AppendUint64(nil, value)
+		for b[len(b)-1] == 0 {
+			b = b[:len(b)-1]
+		}
+		return append([]byte{byte(len(b)-4)<<2 | 3}, b...)
+	}
+}
+
+func TestDecoder_ReadCompact(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		want      *big.Int
+		wantError bool
+	}{
+		{name: "Single byte", data: compact(42), want: big.NewInt(42)},
+		{name: "Two bytes", data: compact(1000), want: big.NewInt(1000)},
+		{name: "Four bytes", data: compact(1 << 20), want: big.NewInt(1 << 20)},
+		{name: "Big integer", data: compact(10000000000), want: big.NewInt(10000000000)},
+		{
+			name: "U128",
+			data: append([]byte{0x33}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}...),
+			want: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)),
+		},
+		{name: "Non-canonical two bytes", data: []byte{0x05, 0x00}, wantError: true},
+		{name: "Non-canonical four bytes", data: []byte{0x02, 0x01, 0x00, 0x00}, wantError: true},
+		{name: "Non-canonical big integer", data: []byte{0x03, 0x00, 0x00, 0x00, 0x01}, wantError: true},
+		{name: "Big integer with zero high byte", data: []byte{0x07, 0xff, 0xff, 0xff, 0xff, 0x00}, wantError: true},
+		{name: "Wider than u128", data: append([]byte{0x37}, make([]byte, 17)...), wantError: true},
+		{name: "Truncated", data: []byte{0x01}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := (&decoder{data: tt.data}).readCompact()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
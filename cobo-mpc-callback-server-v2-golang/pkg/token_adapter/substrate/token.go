@@ -0,0 +1,80 @@
+package substrate
+
+import (
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Network is a Substrate chain whose balances transfers are verified
+type Network struct {
+	ChainID        string
+	GenesisHash    common.Hash
+	BalancesPallet byte
+	SS58Prefix     byte
+}
+
+// PolkadotNetwork is the Polkadot relay chain
+var PolkadotNetwork = &Network{
+	ChainID:        "DOT",
+	GenesisHash:    common.HexToHash("0x91b171bb158e2d3848fa23a9f1c25182fb8e20313b2c1eb49219da7a70ce90c3"),
+	BalancesPallet: 5,
+	SS58Prefix:     0,
+}
+
+type Token struct {
+	tokenID string
+	network *Network
+}
+
+// NewToken returns the DOT token of the Polkadot relay chain
+func NewToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+		network: PolkadotNetwork,
+	}
+}
+
+func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	preTxData, err := prepareBuildTransactionData(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
+	}
+
+	payload, err := ParseSigningPayload(preTxData.rawTx, t.network.BalancesPallet)
+	if err != nil {
+		return nil, fmt.Errorf("prepare substrate signing payload error: %w", err)
+	}
+	if payload.genesisHash != t.network.GenesisHash {
+		return nil, fmt.Errorf("payload genesis hash %x is not the genesis of token %v", payload.genesisHash, t.tokenID)
+	}
+	if payload.immortal && payload.blockHash != payload.genesisHash {
+		return nil, fmt.Errorf("immortal payload block hash %x is not the genesis hash", payload.blockHash)
+	}
+	// the fee is computed from the weight of the call and is not part of the payload, only the tip is
+	if payload.tip.Sign() != 0 {
+		return nil, fmt.Errorf("payload tip %v is not supported", payload.tip)
+	}
+
+	return &Transaction{tx: payload, PrepareTransactionData: preTxData, token: t}, nil
+}
+
+func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
+		return nil, fmt.Errorf("transaction info raw tx is nil")
+	}
+
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
+	if len(rawTxBytes) == 0 {
+		return nil, fmt.Errorf("transaction raw tx is empty")
+	}
+
+	data = &PrepareTransactionData{rawTx: rawTxBytes, chainID: txInfo.Transaction.GetChainId()}
+	if len(txInfo.SourceAddresses) > 0 {
+		data.sourceAddress = txInfo.SourceAddresses[0].Address
+	}
+
+	return data, nil
+}
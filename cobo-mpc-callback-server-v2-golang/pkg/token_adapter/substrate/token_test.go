@@ -0,0 +1,97 @@
+package substrate
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("DOT")
+	dotToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "DOT", dotToken.tokenID)
+	assert.Equal(t, PolkadotNetwork, dotToken.network)
+}
+
+const (
+	// polkadotTransfer is the signing payload of a transferKeepAlive of 1 DOT to Alice at nonce 7, with a mortal era,
+	// spec version 1003000, transaction version 26, the Polkadot genesis hash and a metadata hash
+	polkadotTransfer = "050300d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d0700e40b540215031c0001f84d0f001a0000009" +
+		"1b171bb158e2d3848fa23a9f1c25182fb8e20313b2c1eb49219da7a70ce90c3c6a4c2a4a3f5ad3b1e8f9bb0e9c7b9bc6b2b2a7c1e0f4a1d9d8e63c2b5a4f7e10" +
+		"14a1e3b2c7d0f9e8a5b6c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a"
+
+	kusamaGenesisHash = "b0a8d493285c2df73290dfb7e61f870f17b41801197a149ca93654499ea3dafe"
+)
+
+func TestToken_BuildTransaction(t *testing.T) {
+	const (
+		source = "14E5nqKAp3oAJcmzgZhUD2RcptBeUBScxKHgJKU4HPNcKVf3"
+		// extra of the payload: the mortal era, the nonce 7 and the tip 0
+		extra = "15031c00"
+	)
+
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			// a payload of at most 256 bytes is signed as is
+			name:       "Transfer",
+			rawTx:      polkadotTransfer,
+			wantHashes: []string{"0x" + polkadotTransfer},
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "DOT",
+				From:   source,
+				To:     "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5",
+				Amount: big.NewInt(10000000000),
+			}},
+		},
+		{
+			name:      "Payload of Kusama",
+			rawTx:     strings.Replace(polkadotTransfer, PolkadotNetwork.GenesisHash.Hex()[2:], kusamaGenesisHash, 1),
+			wantError: true,
+		},
+		{
+			name:      "Payload with tip",
+			rawTx:     strings.Replace(polkadotTransfer, extra, "15031ca10f", 1),
+			wantError: true,
+		},
+		{
+			name:      "Immortal payload with block hash other than genesis",
+			rawTx:     strings.Replace(polkadotTransfer, extra, "001c00", 1),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewToken("DOT").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+				SourceAddresses: []coboWaaS2.AddressInfo{{Address: source}},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
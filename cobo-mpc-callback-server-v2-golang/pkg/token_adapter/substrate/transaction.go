@@ -0,0 +1,70 @@
+package substrate
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"golang.org/x/crypto/blake2b"
+)
+
+// maxUnhashedPayloadLength is the longest signing payload signed as is, a longer one is signed by its blake2b-256
+const maxUnhashedPayloadLength = 256
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx *SigningPayload
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx   []byte
+	chainID string
+	// sourceAddress is the signer, which is not part of the signing payload
+	sourceAddress string
+}
+
+// GetHashes implements Transaction interface for Substrate, the signed message is the signing payload,
+// or its blake2b-256 when it is longer than 256 bytes
+func (t *Transaction) GetHashes() ([]string, error) {
+	if len(t.rawTx) == 0 {
+		return nil, fmt.Errorf("transaction raw data is empty")
+	}
+
+	if len(t.rawTx) <= maxUnhashedPayloadLength {
+		return []string{"0x" + hex.EncodeToString(t.rawTx)}, nil
+	}
+	hash := blake2b.Sum256(t.rawTx)
+	return []string{"0x" + hex.EncodeToString(hash[:])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for Substrate
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Substrate, the recipient is in the SS58 form of the network
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = t.token.network.ChainID
+	}
+
+	return []token_adapter.Transfer{{
+		Chain:  chainID,
+		From:   t.sourceAddress,
+		To:     t.tx.dest.SS58(t.token.network.SS58Prefix),
+		Amount: t.tx.value,
+	}}, nil
+}
@@ -0,0 +1,70 @@
+package substrate
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestTransaction_GetHashes(t *testing.T) {
+	short := bytes.Repeat([]byte{1}, maxUnhashedPayloadLength)
+	long := bytes.Repeat([]byte{1}, maxUnhashedPayloadLength+1)
+	longHash := blake2b.Sum256(long)
+
+	tests := []struct {
+		name  string
+		rawTx []byte
+		want  string
+	}{
+		{
+			name:  "Payload signed as is",
+			rawTx: short,
+			want:  "0x" + hex.EncodeToString(short),
+		},
+		{
+			name:  "Payload signed by its hash",
+			rawTx: long,
+			want:  "0x" + hex.EncodeToString(longHash[:]),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &Transaction{PrepareTransactionData: &PrepareTransactionData{rawTx: tt.rawTx}}
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{tt.want}, hashes)
+		})
+	}
+}
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	rawTx := encodePayload(transferCall(5, callTransferAllowDeath, alice, 10000000000), 0, true, PolkadotNetwork.GenesisHash, testBlockHash)
+	payload, err := ParseSigningPayload(rawTx, PolkadotNetwork.BalancesPallet)
+	assert.NoError(t, err)
+
+	source := AccountID{1}.SS58(0)
+	tx := &Transaction{
+		tx:                     payload,
+		PrepareTransactionData: &PrepareTransactionData{rawTx: rawTx, sourceAddress: source},
+		token:                  &Token{tokenID: "DOT", network: PolkadotNetwork},
+	}
+
+	transfers, err := tx.GetTransfers()
+	assert.NoError(t, err)
+	assert.Equal(t, []token_adapter.Transfer{{
+		Chain:  "DOT",
+		From:   source,
+		To:     "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5",
+		Amount: big.NewInt(10000000000),
+	}}, transfers)
+
+	addresses, err := tx.GetDestinationAddresses()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5"}, addresses)
+}
@@ -0,0 +1,83 @@
+package sui
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("SUI")
+	suiToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "SUI", suiToken.tokenID)
+}
+
+// suiTransfer is the transaction data of 1 SUI split from the gas coin of
+// 0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e and transferred to
+// 0xa7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e1980309070123d5ef6, with a budget of 2000000 MIST at price 750
+const suiTransfer = "000002000800ca9a3b000000000020a7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e1980309070123d5ef60202000101000001010200" +
+	"000101007d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e011a3e898029d024eec1d44c6af5e2facded84d03b5373514f16e3d6" +
+	"6e0008105171b905000000000020b2e5c5d2ab5a5c2e8aa5cab1e34e27a0bbb2e39da80a6a1c7ebc0f8d0bcc6f6d7d20dcdb2bca4f508ea9613994683eb4e76e" +
+	"9c4ed371169677c1be02aaf0b58eee0200000000000080841e000000000000"
+
+func TestToken_BuildTransaction(t *testing.T) {
+	transfer := []token_adapter.Transfer{{
+		Chain:  defaultChainID,
+		From:   "0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e",
+		To:     "0xa7536c86055012cb7753fdb08ecb6c8bf1eb735ad75a2e1980309070123d5ef6",
+		Amount: big.NewInt(1000000000),
+	}}
+
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:          "Transfer",
+			rawTx:         suiTransfer,
+			wantHashes:    []string{"0x1a35f566c3695ed90232080beee78c03630e2ae81493edc5a0e08899e877da4a"},
+			wantTransfers: transfer,
+		},
+		{
+			name:          "Transfer expiring at epoch 512",
+			rawTx:         suiTransfer[:len(suiTransfer)-2] + "01" + "0002000000000000",
+			wantHashes:    []string{"0x2d6dee47201efbb03dc0b1b28d4cefd6882efecb55420d55a05268de47ea778d"},
+			wantTransfers: transfer,
+		},
+		{
+			name:      "Truncated transaction",
+			rawTx:     suiTransfer[:len(suiTransfer)-2],
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewToken("SUI").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package sui
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"golang.org/x/crypto/blake2b"
+)
+
+const defaultChainID = "SUI"
+
+// transactionIntent is the intent of a transaction: TransactionData scope, V0 version and Sui app
+var transactionIntent = []byte{0, 0, 0}
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx *TransactionData
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx   []byte
+	chainID string
+}
+
+// GetHashes implements Transaction interface for Sui, the hash is the blake2b-256 of the intent message
+func (t *Transaction) GetHashes() ([]string, error) {
+	if len(t.rawTx) == 0 {
+		return nil, fmt.Errorf("transaction raw data is empty")
+	}
+
+	message := append(append([]byte{}, transactionIntent...), t.rawTx...)
+	hash := blake2b.Sum256(message)
+	return []string{"0x" + hex.EncodeToString(hash[:])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for Sui
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for Sui.
+// Coins are split from the gas coin by SplitCoins and sent by TransferObjects, more coins may be merged into the
+// gas coin by MergeCoins. The type of an object input is not known from the transaction, so it can not be transferred.
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	// splits holds the amounts of the coins split by a command, transferred ones are set to nil
+	splits := make(map[uint16][]*big.Int)
+	var transfers []token_adapter.Transfer
+	for idx, cmd := range t.tx.commands {
+		switch cmd.kind {
+		case commandSplitCoins:
+			if cmd.coin.kind != argumentGasCoin {
+				return nil, fmt.Errorf("command %d splits a coin other than the gas coin", idx)
+			}
+			var amounts []*big.Int
+			for _, arg := range cmd.coins {
+				amount, err := t.pureU64(arg)
+				if err != nil {
+					return nil, fmt.Errorf("command %d split amount: %w", idx, err)
+				}
+				amounts = append(amounts, new(big.Int).SetUint64(amount))
+			}
+			splits[uint16(idx)] = amounts
+		case commandMergeCoins:
+			if cmd.coin.kind != argumentGasCoin {
+				return nil, fmt.Errorf("command %d merges into a coin other than the gas coin", idx)
+			}
+		case commandTransferObjects:
+			recipient, err := t.pureAddress(cmd.recipient)
+			if err != nil {
+				return nil, fmt.Errorf("command %d recipient: %w", idx, err)
+			}
+			for _, object := range cmd.objects {
+				amount, err := takeSplitCoin(splits, object)
+				if err != nil {
+					return nil, fmt.Errorf("command %d object: %w", idx, err)
+				}
+				transfers = append(transfers, token_adapter.Transfer{
+					Chain:  chainID,
+					From:   t.tx.sender.String(),
+					To:     recipient.String(),
+					Amount: amount,
+				})
+			}
+		default:
+			return nil, fmt.Errorf("unsupported command %d", idx)
+		}
+	}
+
+	return transfers, nil
+}
+
+// takeSplitCoin returns the amount of a coin split by a previous command, a coin is transferred once
+func takeSplitCoin(splits map[uint16][]*big.Int, arg argument) (*big.Int, error) {
+	var nested uint16
+	switch arg.kind {
+	case argumentResult:
+		// the result of a command with a single coin
+		if len(splits[arg.index]) != 1 {
+			return nil, fmt.Errorf("result %d is not a single split coin", arg.index)
+		}
+	case argumentNestedResult:
+		nested = arg.nested
+	case argumentGasCoin:
+		return nil, fmt.Errorf("transfer of the gas coin is not supported, the amount is unknown")
+	default:
+		return nil, fmt.Errorf("transfer of an input object is not supported, the coin type is unknown")
+	}
+
+	amounts, ok := splits[arg.index]
+	if !ok || int(nested) >= len(amounts) {
+		return nil, fmt.Errorf("result %d/%d is not a split coin", arg.index, nested)
+	}
+	amount := amounts[nested]
+	if amount == nil {
+		return nil, fmt.Errorf("split coin %d/%d is transferred twice", arg.index, nested)
+	}
+	amounts[nested] = nil
+	return amount, nil
+}
+
+// pureInput returns the BCS bytes of a pure input
+func (t *Transaction) pureInput(arg argument) ([]byte, error) {
+	if arg.kind != argumentInput {
+		return nil, fmt.Errorf("argument is not an input")
+	}
+	if int(arg.index) >= len(t.tx.inputs) {
+		return nil, fmt.Errorf("input %d out of range", arg.index)
+	}
+	input := t.tx.inputs[arg.index]
+	if input.object {
+		return nil, fmt.Errorf("input %d is an object", arg.index)
+	}
+	return input.pure, nil
+}
+
+func (t *Transaction) pureU64(arg argument) (uint64, error) {
+	b, err := t.pureInput(arg)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid u64 input length %d", len(b))
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (t *Transaction) pureAddress(arg argument) (Address, error) {
+	var address Address
+	b, err := t.pureInput(arg)
+	if err != nil {
+		return address, err
+	}
+	if len(b) != addressLength {
+		return address, fmt.Errorf("invalid address input length %d", len(b))
+	}
+	copy(address[:], b)
+	return address, nil
+}
+
+// GetFee implements FeeTransaction interface for Sui, the fee limit is the gas budget
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	return &token_adapter.Fee{
+		GasPrice: new(big.Int).SetUint64(t.tx.gasData.price),
+		FeeLimit: new(big.Int).SetUint64(t.tx.gasData.budget),
+	}, nil
+}
@@ -0,0 +1,326 @@
+package sui
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/bcs"
+)
+
+const (
+	addressLength = 32
+
+	transactionDataV1           = 0
+	kindProgrammableTransaction = 0
+	expirationNone              = 0
+	expirationEpoch             = 1
+	callArgPure                 = 0
+	callArgObject               = 1
+	objectArgImmOrOwned         = 0
+	objectArgShared             = 1
+	objectArgReceiving          = 2
+	commandTransferObjects      = 1
+	commandSplitCoins           = 2
+	commandMergeCoins           = 3
+	argumentGasCoin             = 0
+	argumentInput               = 1
+	argumentResult              = 2
+	argumentNestedResult        = 3
+	objectDigestLength          = 32
+)
+
+// commandNames names the Command variants in errors
+var commandNames = map[uint32]string{
+	0: "MoveCall",
+	1: "TransferObjects",
+	2: "SplitCoins",
+	3: "MergeCoins",
+	4: "Publish",
+	5: "MakeMoveVec",
+	6: "Upgrade",
+}
+
+// Address is a Sui address or object id
+type Address [addressLength]byte
+
+// String returns 0x followed by 64 hex characters
+func (a Address) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// TransactionData is a BCS decoded Sui TransactionData V1 of a programmable transaction
+type TransactionData struct {
+	inputs   []callArg
+	commands []command
+	sender   Address
+	gasData  gasData
+	// expirationEpoch is the epoch after which the transaction expires, nil if it does not expire
+	expirationEpoch *uint64
+}
+
+// callArg is an input of a programmable transaction, the reference of an object input is not decoded
+type callArg struct {
+	pure   []byte
+	object bool
+}
+
+// command is a TransferObjects, SplitCoins or MergeCoins command, another command is rejected when decoding
+type command struct {
+	kind uint32
+	// objects and recipient of TransferObjects
+	objects   []argument
+	recipient argument
+	// coin and amounts of SplitCoins, or destination coin and source coins of MergeCoins
+	coin  argument
+	coins []argument
+}
+
+type argument struct {
+	kind   uint32
+	index  uint16
+	nested uint16
+}
+
+type gasData struct {
+	owner  Address
+	price  uint64
+	budget uint64
+}
+
+// ParseTransactionData decodes the BCS bytes of a TransactionData
+func ParseTransactionData(rawTx []byte) (*TransactionData, error) {
+	d := bcs.NewDecoder(rawTx)
+	tx := &TransactionData{}
+
+	version, err := d.ReadVariant()
+	if err != nil {
+		return nil, fmt.Errorf("decode transaction data version: %w", err)
+	}
+	if version != transactionDataV1 {
+		return nil, fmt.Errorf("unsupported transaction data version %d", version)
+	}
+
+	kind, err := d.ReadVariant()
+	if err != nil {
+		return nil, fmt.Errorf("decode transaction kind: %w", err)
+	}
+	if kind != kindProgrammableTransaction {
+		return nil, fmt.Errorf("unsupported transaction kind %d, only programmable transaction is supported", kind)
+	}
+	if err := tx.readProgrammableTransaction(d); err != nil {
+		return nil, fmt.Errorf("decode programmable transaction: %w", err)
+	}
+
+	if tx.sender, err = readAddress(d); err != nil {
+		return nil, fmt.Errorf("decode sender: %w", err)
+	}
+	if err := tx.readGasData(d); err != nil {
+		return nil, fmt.Errorf("decode gas data: %w", err)
+	}
+	if err := tx.readExpiration(d); err != nil {
+		return nil, fmt.Errorf("decode expiration: %w", err)
+	}
+	if err := d.Finish(); err != nil {
+		return nil, fmt.Errorf("decode transaction data: %w", err)
+	}
+
+	return tx, nil
+}
+
+func readAddress(d *bcs.Decoder) (Address, error) {
+	var address Address
+	b, err := d.ReadFixedBytes(addressLength)
+	if err != nil {
+		return address, err
+	}
+	copy(address[:], b)
+	return address, nil
+}
+
+func (tx *TransactionData) readProgrammableTransaction(d *bcs.Decoder) error {
+	count, err := d.ReadLength()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		input, err := readCallArg(d)
+		if err != nil {
+			return fmt.Errorf("decode input %d: %w", i, err)
+		}
+		tx.inputs = append(tx.inputs, input)
+	}
+
+	if count, err = d.ReadLength(); err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		cmd, err := readCommand(d)
+		if err != nil {
+			return fmt.Errorf("decode command %d: %w", i, err)
+		}
+		tx.commands = append(tx.commands, cmd)
+	}
+	return nil
+}
+
+func readCallArg(d *bcs.Decoder) (callArg, error) {
+	variant, err := d.ReadVariant()
+	if err != nil {
+		return callArg{}, err
+	}
+	switch variant {
+	case callArgPure:
+		pure, err := d.ReadBytes()
+		return callArg{pure: pure}, err
+	case callArgObject:
+		return callArg{object: true}, readObjectArg(d)
+	default:
+		return callArg{}, fmt.Errorf("unsupported call arg variant %d", variant)
+	}
+}
+
+func readObjectArg(d *bcs.Decoder) error {
+	variant, err := d.ReadVariant()
+	if err != nil {
+		return err
+	}
+	switch variant {
+	case objectArgImmOrOwned, objectArgReceiving:
+		return readObjectRef(d)
+	case objectArgShared:
+		if _, err := readAddress(d); err != nil {
+			return err
+		}
+		if _, err := d.ReadU64(); err != nil {
+			return err
+		}
+		_, err := d.ReadBool()
+		return err
+	default:
+		return fmt.Errorf("unsupported object arg variant %d", variant)
+	}
+}
+
+// readObjectRef reads the id, version and digest of an object
+func readObjectRef(d *bcs.Decoder) error {
+	if _, err := readAddress(d); err != nil {
+		return err
+	}
+	if _, err := d.ReadU64(); err != nil {
+		return err
+	}
+	digest, err := d.ReadBytes()
+	if err != nil {
+		return err
+	}
+	if len(digest) != objectDigestLength {
+		return fmt.Errorf("invalid object digest length %d", len(digest))
+	}
+	return nil
+}
+
+func readCommand(d *bcs.Decoder) (command, error) {
+	variant, err := d.ReadVariant()
+	if err != nil {
+		return command{}, err
+	}
+
+	cmd := command{kind: variant}
+	switch variant {
+	case commandTransferObjects:
+		if cmd.objects, err = readArguments(d); err != nil {
+			return command{}, err
+		}
+		cmd.recipient, err = readArgument(d)
+	case commandSplitCoins, commandMergeCoins:
+		if cmd.coin, err = readArgument(d); err != nil {
+			return command{}, err
+		}
+		cmd.coins, err = readArguments(d)
+	default:
+		name, ok := commandNames[variant]
+		if !ok {
+			name = fmt.Sprintf("variant %d", variant)
+		}
+		return command{}, fmt.Errorf("unsupported command %v", name)
+	}
+	return cmd, err
+}
+
+func readArguments(d *bcs.Decoder) ([]argument, error) {
+	count, err := d.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	var args []argument
+	for i := 0; i < count; i++ {
+		arg, err := readArgument(d)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func readArgument(d *bcs.Decoder) (argument, error) {
+	variant, err := d.ReadVariant()
+	if err != nil {
+		return argument{}, err
+	}
+
+	arg := argument{kind: variant}
+	switch variant {
+	case argumentGasCoin:
+	case argumentInput, argumentResult:
+		arg.index, err = d.ReadU16()
+	case argumentNestedResult:
+		if arg.index, err = d.ReadU16(); err != nil {
+			return argument{}, err
+		}
+		arg.nested, err = d.ReadU16()
+	default:
+		return argument{}, fmt.Errorf("unsupported argument variant %d", variant)
+	}
+	return arg, err
+}
+
+func (tx *TransactionData) readGasData(d *bcs.Decoder) error {
+	count, err := d.ReadLength()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		if err := readObjectRef(d); err != nil {
+			return fmt.Errorf("decode gas payment %d: %w", i, err)
+		}
+	}
+	if tx.gasData.owner, err = readAddress(d); err != nil {
+		return err
+	}
+	if tx.gasData.price, err = d.ReadU64(); err != nil {
+		return err
+	}
+	tx.gasData.budget, err = d.ReadU64()
+	return err
+}
+
+func (tx *TransactionData) readExpiration(d *bcs.Decoder) error {
+	variant, err := d.ReadVariant()
+	if err != nil {
+		return err
+	}
+	switch variant {
+	case expirationNone:
+		return nil
+	case expirationEpoch:
+		epoch, err := d.ReadU64()
+		if err != nil {
+			return err
+		}
+		tx.expirationEpoch = &epoch
+		return nil
+	default:
+		return fmt.Errorf("unsupported expiration variant %d", variant)
+	}
+}
@@ -0,0 +1,194 @@
+package sui
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAddress(seed byte) Address {
+	var address Address
+	address[0] = seed
+	address[addressLength-1] = seed
+	return address
+}
+
+func objectRef(seed byte) []byte {
+	id := testAddress(seed)
+	b := binary.LittleEndian.AppendUint64(append([]byte{}, id[:]...), 42)
+	return append(append(b, objectDigestLength), bytes.Repeat([]byte{seed}, objectDigestLength)...)
+}
+
+func pureU64(value uint64) []byte {
+	return binary.LittleEndian.AppendUint64([]byte{callArgPure, 8}, value)
+}
+
+func pureAddress(address Address) []byte {
+	return append([]byte{callArgPure, addressLength}, address[:]...)
+}
+
+func ownedObject(seed byte) []byte {
+	return append([]byte{callArgObject, objectArgImmOrOwned}, objectRef(seed)...)
+}
+
+func gasCoin() []byte { return []byte{argumentGasCoin} }
+
+func input(index byte) []byte { return []byte{argumentInput, index, 0} }
+
+func result(index byte) []byte { return []byte{argumentResult, index, 0} }
+
+func nestedResult(index, nested byte) []byte {
+	return []byte{argumentNestedResult, index, 0, nested, 0}
+}
+
+func splitCoins(coin []byte, amounts ...[]byte) []byte {
+	b := append(append([]byte{commandSplitCoins}, coin...), byte(len(amounts)))
+	return append(b, bytes.Join(amounts, nil)...)
+}
+
+func mergeCoins(coin []byte, sources ...[]byte) []byte {
+	b := append(append([]byte{commandMergeCoins}, coin...), byte(len(sources)))
+	return append(b, bytes.Join(sources, nil)...)
+}
+
+func transferObjects(recipient []byte, objects ...[]byte) []byte {
+	b := append([]byte{commandTransferObjects, byte(len(objects))}, bytes.Join(objects, nil)...)
+	return append(b, recipient...)
+}
+
+// encodeTransactionData returns a programmable transaction of the sender with a budget of 5000000 at price 750
+func encodeTransactionData(sender Address, inputs [][]byte, commands ...[]byte) []byte {
+	b := []byte{transactionDataV1, kindProgrammableTransaction, byte(len(inputs))}
+	b = append(b, bytes.Join(inputs, nil)...)
+	b = append(b, byte(len(commands)))
+	b = append(b, bytes.Join(commands, nil)...)
+	b = append(b, sender[:]...)
+	b = append(append(b, 1), objectRef(9)...)
+	b = append(b, sender[:]...)
+	b = binary.LittleEndian.AppendUint64(b, 750)
+	b = binary.LittleEndian.AppendUint64(b, 5000000)
+	return append(b, expirationNone)
+}
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	sender := testAddress(1)
+	alice := testAddress(2)
+	bob := testAddress(3)
+
+	tests := []struct {
+		name          string
+		inputs        [][]byte
+		commands      [][]byte
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:     "Split and transfer",
+			inputs:   [][]byte{pureU64(1000000000), pureAddress(alice)},
+			commands: [][]byte{splitCoins(gasCoin(), input(0)), transferObjects(input(1), result(0))},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, From: sender.String(), To: alice.String(), Amount: big.NewInt(1000000000)},
+			},
+		},
+		{
+			name:   "Merge, split and transfer to two recipients",
+			inputs: [][]byte{ownedObject(5), pureU64(10), pureU64(20), pureAddress(alice), pureAddress(bob)},
+			commands: [][]byte{
+				mergeCoins(gasCoin(), input(0)),
+				splitCoins(gasCoin(), input(1), input(2)),
+				transferObjects(input(3), nestedResult(1, 0)),
+				transferObjects(input(4), nestedResult(1, 1)),
+			},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, From: sender.String(), To: alice.String(), Amount: big.NewInt(10)},
+				{Chain: defaultChainID, From: sender.String(), To: bob.String(), Amount: big.NewInt(20)},
+			},
+		},
+		{
+			name:      "Transfer of the gas coin",
+			inputs:    [][]byte{pureAddress(alice)},
+			commands:  [][]byte{transferObjects(input(0), gasCoin())},
+			wantError: true,
+		},
+		{
+			name:      "Transfer of an object input",
+			inputs:    [][]byte{ownedObject(5), pureAddress(alice)},
+			commands:  [][]byte{transferObjects(input(1), input(0))},
+			wantError: true,
+		},
+		{
+			name:      "Split of an object input",
+			inputs:    [][]byte{ownedObject(5), pureU64(10), pureAddress(alice)},
+			commands:  [][]byte{splitCoins(input(0), input(1)), transferObjects(input(2), result(0))},
+			wantError: true,
+		},
+		{
+			name:      "Split coin transferred twice",
+			inputs:    [][]byte{pureU64(10), pureAddress(alice)},
+			commands:  [][]byte{splitCoins(gasCoin(), input(0)), transferObjects(input(1), result(0), nestedResult(0, 0))},
+			wantError: true,
+		},
+		{
+			name:      "Result of two split coins",
+			inputs:    [][]byte{pureU64(10), pureU64(20), pureAddress(alice)},
+			commands:  [][]byte{splitCoins(gasCoin(), input(0), input(1)), transferObjects(input(2), result(0))},
+			wantError: true,
+		},
+		{
+			name:      "Recipient is not an address",
+			inputs:    [][]byte{pureU64(10)},
+			commands:  [][]byte{splitCoins(gasCoin(), input(0)), transferObjects(input(0), result(0))},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTx := encodeTransactionData(sender, tt.inputs, tt.commands...)
+			data, err := ParseTransactionData(rawTx)
+			assert.NoError(t, err)
+			tx := &Transaction{tx: data, PrepareTransactionData: &PrepareTransactionData{rawTx: rawTx}, token: &Token{tokenID: "SUI"}}
+
+			transfers, err := tx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			fee, err := tx.GetFee()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Fee{GasPrice: big.NewInt(750), FeeLimit: big.NewInt(5000000)}, fee)
+		})
+	}
+}
+
+func TestParseTransactionData(t *testing.T) {
+	sender := testAddress(1)
+	inputs := [][]byte{pureU64(10), pureAddress(testAddress(2))}
+	valid := encodeTransactionData(sender, inputs, splitCoins(gasCoin(), input(0)), transferObjects(input(1), result(0)))
+
+	data, err := ParseTransactionData(valid)
+	assert.NoError(t, err)
+	assert.Equal(t, sender, data.sender)
+	assert.Len(t, data.inputs, 2)
+	assert.Len(t, data.commands, 2)
+	assert.Nil(t, data.expirationEpoch)
+
+	// a MoveCall is rejected
+	_, err = ParseTransactionData(encodeTransactionData(sender, inputs, []byte{0}))
+	assert.Error(t, err)
+
+	_, err = ParseTransactionData(append(append([]byte{}, valid...), 0))
+	assert.Error(t, err)
+
+	systemTx := append([]byte{}, valid...)
+	systemTx[1] = 1
+	_, err = ParseTransactionData(systemTx)
+	assert.Error(t, err)
+}
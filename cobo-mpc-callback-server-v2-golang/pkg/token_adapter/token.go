@@ -12,6 +12,10 @@ var (
 	registryLock sync.RWMutex
 	// tokenRegistry save token id and creator
 	tokenRegistry = make(map[string]TokenCreator)
+	// decimalsRegistry save token id and decimals of the token amount
+	decimalsRegistry = make(map[string]int32)
+	// contractRegistry save token id and the contract or mint address of the token asset
+	contractRegistry = make(map[string]string)
 )
 
 func RegisterTokenCreator(tokenID string, creator TokenCreator) error {
@@ -76,3 +80,69 @@ func UnregisterTokenCreator(tokenID string) {
 	tokenID = strings.ToUpper(strings.TrimSpace(tokenID))
 	delete(tokenRegistry, tokenID)
 }
+
+func RegisterTokenDecimals(tokenID string, decimals int32) error {
+	if tokenID == "" {
+		return fmt.Errorf("token_id cannot be empty")
+	}
+	if decimals < 0 {
+		return fmt.Errorf("decimals cannot be negative")
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	tokenID = strings.ToUpper(strings.TrimSpace(tokenID))
+	if _, exists := decimalsRegistry[tokenID]; exists {
+		return fmt.Errorf("decimals with token_id %s is already registered", tokenID)
+	}
+
+	decimalsRegistry[tokenID] = decimals
+	return nil
+}
+
+func GetTokenDecimals(tokenID string) (int32, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	tokenID = strings.ToUpper(strings.TrimSpace(tokenID))
+	decimals, ok := decimalsRegistry[tokenID]
+	return decimals, ok
+}
+
+func RegisterTokenContract(tokenID string, contract string) error {
+	if tokenID == "" {
+		return fmt.Errorf("token_id cannot be empty")
+	}
+	contract = strings.TrimSpace(contract)
+	if contract == "" {
+		return fmt.Errorf("contract cannot be empty")
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	tokenID = strings.ToUpper(strings.TrimSpace(tokenID))
+	if _, exists := contractRegistry[tokenID]; exists {
+		return fmt.Errorf("contract with token_id %s is already registered", tokenID)
+	}
+
+	contractRegistry[tokenID] = contract
+	return nil
+}
+
+func GetTokenContract(tokenID string) (string, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	tokenID = strings.ToUpper(strings.TrimSpace(tokenID))
+	contract, ok := contractRegistry[tokenID]
+	return contract, ok
+}
+
+// GetTokenAsset returns the asset of the token transfers: the registered contract or mint address, empty for the
+// native asset of the chain
+func GetTokenAsset(tokenID string) string {
+	contract, _ := GetTokenContract(tokenID)
+	return contract
+}
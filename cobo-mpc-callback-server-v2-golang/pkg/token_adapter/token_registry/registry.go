@@ -1,10 +1,22 @@
 package token_registry
 
 import (
+	"strings"
+
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/aptos"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/bitcoin"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/cardano"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/cosmos"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/eth_base"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/near"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/solana"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/stellar"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/substrate"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/sui"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/ton"
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/tron"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/xrp"
 )
 
 func InitRegistry() {
@@ -31,4 +43,184 @@ func InitRegistry() {
 	if err := token_adapter.RegisterTokenCreator("SOL_USDC", solana.NewSPLToken); err != nil {
 		panic(err)
 	}
+
+	if err := token_adapter.RegisterTokenCreator("BTC", bitcoin.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("LTC", bitcoin.NewLitecoinToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("DOGE", bitcoin.NewDogecoinToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("BCH", bitcoin.NewBitcoinCashToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("ATOM", cosmos.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("APT", aptos.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("SUI", sui.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("XRP", xrp.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("TON", ton.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("ADA", cardano.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("DOT", substrate.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("XLM", stellar.NewToken); err != nil {
+		panic(err)
+	}
+
+	if err := token_adapter.RegisterTokenCreator("NEAR", near.NewToken); err != nil {
+		panic(err)
+	}
+
+	registerDecimals(map[string]int32{
+		"BTC":       8,
+		"LTC":       8,
+		"DOGE":      8,
+		"BCH":       8,
+		"ETH":       18,
+		"ETH_USDT":  6,
+		"TRON":      6,
+		"TRON_USDT": 6,
+		"SOL":       9,
+		"SOL_USDC":  6,
+		"ATOM":      6,
+		"APT":       8,
+		"SUI":       9,
+		"XRP":       6,
+		"TON":       9,
+		"ADA":       6,
+		"DOT":       10,
+		"XLM":       7,
+		"NEAR":      24,
+	})
+
+	registerContracts(map[string]string{
+		"ETH_USDT":  "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+		"TRON_USDT": "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t",
+		"SOL_USDC":  "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+	})
+}
+
+// RegisterEvmTokens registers the EVM tokens declared in config with the eth_base adapter
+func RegisterEvmTokens(tokens []eth_base.TokenConfig) error {
+	for _, cfg := range tokens {
+		creator, err := eth_base.NewTokenCreator(cfg)
+		if err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenCreator(cfg.TokenID, creator); err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenDecimals(cfg.TokenID, cfg.Decimals); err != nil {
+			return err
+		}
+		if cfg.ContractAddress != "" {
+			if err := token_adapter.RegisterTokenContract(cfg.TokenID, cfg.ContractAddress); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RegisterTrc10Tokens registers the TRC10 tokens declared in config with the tron adapter
+func RegisterTrc10Tokens(tokens []tron.Trc10TokenConfig) error {
+	for _, cfg := range tokens {
+		if err := tron.ValidateTrc10Token(cfg); err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenCreator(cfg.TokenID, tron.NewTrc10Token); err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenDecimals(cfg.TokenID, cfg.Decimals); err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenContract(cfg.TokenID, cfg.AssetID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterCosmosTokens registers the Cosmos SDK tokens declared in config with the cosmos adapter
+func RegisterCosmosTokens(tokens []cosmos.TokenConfig) error {
+	for _, cfg := range tokens {
+		creator, err := cosmos.NewTokenCreator(cfg)
+		if err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenCreator(cfg.TokenID, creator); err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenDecimals(cfg.TokenID, cfg.Decimals); err != nil {
+			return err
+		}
+		// a coin other than the staking coin is the asset of its transfers
+		if !strings.EqualFold(cfg.Type, cosmos.TokenTypeNative) {
+			if err := token_adapter.RegisterTokenContract(cfg.TokenID, cfg.Denom); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RegisterJettonTokens registers the jettons declared in config with the ton adapter
+func RegisterJettonTokens(tokens []ton.JettonConfig) error {
+	for _, cfg := range tokens {
+		creator, err := ton.NewJettonTokenCreator(cfg)
+		if err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenCreator(cfg.TokenID, creator); err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenDecimals(cfg.TokenID, cfg.Decimals); err != nil {
+			return err
+		}
+		if err := token_adapter.RegisterTokenContract(cfg.TokenID, cfg.JettonMaster); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func registerDecimals(decimals map[string]int32) {
+	for tokenID, d := range decimals {
+		if err := token_adapter.RegisterTokenDecimals(tokenID, d); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func registerContracts(contracts map[string]string) {
+	for tokenID, contract := range contracts {
+		if err := token_adapter.RegisterTokenContract(tokenID, contract); err != nil {
+			panic(err)
+		}
+	}
 }
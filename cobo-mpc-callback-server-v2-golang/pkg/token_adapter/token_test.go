@@ -21,6 +21,11 @@ func (m *MockTransaction) GetDestinationAddresses() ([]string, error) {
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockTransaction) GetTransfers() ([]Transfer, error) {
+	args := m.Called()
+	return args.Get(0).([]Transfer), args.Error(1)
+}
+
 // MockToken implements Token interface for testing
 type MockToken struct {
 	mock.Mock
@@ -231,3 +236,37 @@ func TestUnregisterTokenCreator(t *testing.T) {
 	// Verify token is no longer registered
 	assert.False(t, IsTokenIDSupported("ETH"))
 }
+
+func TestRegisterTokenDecimals(t *testing.T) {
+	// Clear registry before testing
+	decimalsRegistry = make(map[string]int32)
+
+	assert.NoError(t, RegisterTokenDecimals("ETH", 18))
+	assert.Error(t, RegisterTokenDecimals("eth", 18))
+	assert.Error(t, RegisterTokenDecimals("", 6))
+	assert.Error(t, RegisterTokenDecimals("USDT", -1))
+
+	decimals, ok := GetTokenDecimals("eth")
+	assert.True(t, ok)
+	assert.Equal(t, int32(18), decimals)
+
+	_, ok = GetTokenDecimals("BTC")
+	assert.False(t, ok)
+}
+
+func TestRegisterTokenContract(t *testing.T) {
+	// Clear registry before testing
+	contractRegistry = make(map[string]string)
+
+	assert.NoError(t, RegisterTokenContract("ETH_USDT", "0xdAC17F958D2ee523a2206206994597C13D831ec7"))
+	assert.Error(t, RegisterTokenContract("eth_usdt", "0xdAC17F958D2ee523a2206206994597C13D831ec7"))
+	assert.Error(t, RegisterTokenContract("", "0xdAC17F958D2ee523a2206206994597C13D831ec7"))
+	assert.Error(t, RegisterTokenContract("SOL_USDC", " "))
+
+	contract, ok := GetTokenContract("eth_usdt")
+	assert.True(t, ok)
+	assert.Equal(t, "0xdAC17F958D2ee523a2206206994597C13D831ec7", contract)
+
+	_, ok = GetTokenContract("ETH")
+	assert.False(t, ok)
+}
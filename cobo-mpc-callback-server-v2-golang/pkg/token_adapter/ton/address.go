@@ -0,0 +1,94 @@
+package ton
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// user-friendly address tags, a testnet address has the 0x80 bit set
+	tagBounceable    = 0x11
+	tagNonBounceable = 0x51
+	tagTestnet       = 0x80
+
+	userFriendlyAddressLength = 36
+)
+
+// Address is a standard address, a workchain and the hash of the account
+type Address struct {
+	workchain int8
+	hash      [32]byte
+}
+
+// String returns the raw form of the address, the workchain and the hex of the hash, e.g. 0:83df...
+func (a Address) String() string {
+	return strconv.Itoa(int(a.workchain)) + ":" + hex.EncodeToString(a.hash[:])
+}
+
+// UserFriendly returns the url safe base64 of the flags, the workchain, the hash and their crc16,
+// e.g. EQ... for a bounceable address and UQ... for a non-bounceable one
+func (a Address) UserFriendly(bounceable bool) string {
+	tag := byte(tagNonBounceable)
+	if bounceable {
+		tag = tagBounceable
+	}
+	b := append([]byte{tag, byte(a.workchain)}, a.hash[:]...)
+	b = binary.BigEndian.AppendUint16(b, crc16(b))
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+// ParseAddress parses the raw form or the user-friendly form of an address, a testnet address is rejected
+func ParseAddress(s string) (Address, error) {
+	var address Address
+	if workchain, hash, ok := strings.Cut(s, ":"); ok {
+		wc, err := strconv.ParseInt(workchain, 10, 8)
+		if err != nil {
+			return address, fmt.Errorf("invalid address workchain %q", workchain)
+		}
+		b, err := hex.DecodeString(hash)
+		if err != nil || len(b) != len(address.hash) {
+			return address, fmt.Errorf("invalid address hash %q", hash)
+		}
+		address.workchain = int8(wc)
+		copy(address.hash[:], b)
+		return address, nil
+	}
+
+	// the user-friendly form is either url safe or standard base64
+	b, err := base64.URLEncoding.DecodeString(strings.NewReplacer("+", "-", "/", "_").Replace(s))
+	if err != nil || len(b) != userFriendlyAddressLength {
+		return address, fmt.Errorf("invalid address %q", s)
+	}
+	if binary.BigEndian.Uint16(b[34:]) != crc16(b[:34]) {
+		return address, fmt.Errorf("invalid address checksum %q", s)
+	}
+	if b[0]&tagTestnet != 0 {
+		return address, fmt.Errorf("testnet address %q is not supported", s)
+	}
+	if b[0] != tagBounceable && b[0] != tagNonBounceable {
+		return address, fmt.Errorf("invalid address tag %02x", b[0])
+	}
+	address.workchain = int8(b[1])
+	copy(address.hash[:], b[2:34])
+	return address, nil
+}
+
+// crc16 is the CRC-16/XMODEM checksum of a user-friendly address
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,102 @@
+package ton
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddress_UserFriendly(t *testing.T) {
+	tests := []struct {
+		name       string
+		address    Address
+		bounceable bool
+		want       string
+	}{
+		{
+			name:       "Elector",
+			address:    Address{workchain: -1, hash: [32]byte(bytes.Repeat([]byte{0x33}, 32))},
+			bounceable: true,
+			want:       "Ef8zMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzM0vF",
+		},
+		{
+			name:       "Config",
+			address:    Address{workchain: -1, hash: [32]byte(bytes.Repeat([]byte{0x55}, 32))},
+			bounceable: true,
+			want:       "Ef9VVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVbxn",
+		},
+		{
+			name:       "Zero bounceable",
+			bounceable: true,
+			want:       "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			friendly := tt.address.UserFriendly(tt.bounceable)
+			assert.Equal(t, tt.want, friendly)
+
+			parsed, err := ParseAddress(friendly)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.address, parsed)
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	elector := Address{workchain: -1, hash: [32]byte(bytes.Repeat([]byte{0x33}, 32))}
+
+	tests := []struct {
+		name      string
+		address   string
+		want      Address
+		wantError bool
+	}{
+		{
+			name:    "Raw",
+			address: "-1:3333333333333333333333333333333333333333333333333333333333333333",
+			want:    elector,
+		},
+		{
+			name:    "Non-bounceable",
+			address: elector.UserFriendly(false),
+			want:    elector,
+		},
+		{
+			name:      "Invalid checksum",
+			address:   "Ef8zMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzM0vG",
+			wantError: true,
+		},
+		{
+			name:      "Testnet",
+			address:   "kf8zMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzM0vF",
+			wantError: true,
+		},
+		{
+			name:      "Invalid raw hash",
+			address:   "0:33",
+			wantError: true,
+		},
+		{
+			name:      "Invalid raw workchain",
+			address:   "256:3333333333333333333333333333333333333333333333333333333333333333",
+			wantError: true,
+		},
+	}
+
+	assert.Equal(t, "-1:3333333333333333333333333333333333333333333333333333333333333333", elector.String())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := ParseAddress(tt.address)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, address)
+		})
+	}
+}
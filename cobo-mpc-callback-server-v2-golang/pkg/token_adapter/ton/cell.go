@@ -0,0 +1,252 @@
+package ton
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+)
+
+const (
+	bocMagic = 0xb5ee9c72
+
+	maxCellBits = 1023
+	maxCellRefs = 4
+)
+
+// Cell is an ordinary cell of level 0, exotic cells such as pruned branches are rejected when decoding
+type Cell struct {
+	// data is the cell data padded to bytes with the completion tag
+	data  []byte
+	bits  int
+	refs  []*Cell
+	hash  [32]byte
+	depth uint16
+}
+
+// Hash returns the representation hash of the cell, the hash signed by a wallet
+func (c *Cell) Hash() [32]byte {
+	return c.hash
+}
+
+// descriptors returns the refs descriptor and the bits descriptor of the cell
+func (c *Cell) descriptors() []byte {
+	return []byte{byte(len(c.refs)), byte(c.bits/8 + (c.bits+7)/8)}
+}
+
+// computeHash computes the depth and the hash of the cell from those of its refs
+func (c *Cell) computeHash() {
+	h := sha256.New()
+	h.Write(c.descriptors())
+	h.Write(c.data)
+	for _, ref := range c.refs {
+		h.Write(binary.BigEndian.AppendUint16(nil, ref.depth))
+		if ref.depth+1 > c.depth {
+			c.depth = ref.depth + 1
+		}
+	}
+	for _, ref := range c.refs {
+		h.Write(ref.hash[:])
+	}
+	copy(c.hash[:], h.Sum(nil))
+}
+
+// bocCell is a serialized cell whose refs are still indexes
+type bocCell struct {
+	data []byte
+	bits int
+	refs []int
+}
+
+// ParseBOC decodes a bag of cells with a single root and returns the root cell
+func ParseBOC(b []byte) (*Cell, error) {
+	r := &byteReader{data: b}
+	magic, err := r.readUint(4)
+	if err != nil {
+		return nil, err
+	}
+	if magic != bocMagic {
+		return nil, fmt.Errorf("invalid boc magic %08x", magic)
+	}
+
+	flags, err := r.readUint(1)
+	if err != nil {
+		return nil, err
+	}
+	hasIndex, hasCRC := flags&0x80 != 0, flags&0x40 != 0
+	if flags&0x18 != 0 {
+		return nil, fmt.Errorf("unsupported boc flags %02x", flags)
+	}
+	size := int(flags & 0x07)
+	if size == 0 || size > 4 {
+		return nil, fmt.Errorf("invalid boc ref size %d", size)
+	}
+	offsetSize, err := r.readUint(1)
+	if err != nil {
+		return nil, err
+	}
+	if offsetSize == 0 || offsetSize > 8 {
+		return nil, fmt.Errorf("invalid boc offset size %d", offsetSize)
+	}
+
+	var header [4]uint64
+	for i := range header {
+		n := size
+		if i == 3 {
+			n = int(offsetSize)
+		}
+		if header[i], err = r.readUint(n); err != nil {
+			return nil, err
+		}
+	}
+	cellCount, rootCount, absentCount, dataSize := header[0], header[1], header[2], header[3]
+	if rootCount != 1 {
+		return nil, fmt.Errorf("boc has %d roots, one is required", rootCount)
+	}
+	if absentCount != 0 {
+		return nil, fmt.Errorf("boc has %d absent cells", absentCount)
+	}
+	// a cell has at least its two descriptor bytes
+	if cellCount == 0 || cellCount > dataSize/2 {
+		return nil, fmt.Errorf("invalid boc cell count %d for %d bytes of cells", cellCount, dataSize)
+	}
+	rootIndex, err := r.readUint(size)
+	if err != nil {
+		return nil, err
+	}
+	if rootIndex >= cellCount {
+		return nil, fmt.Errorf("boc root index %d out of range", rootIndex)
+	}
+	if hasIndex {
+		if _, err := r.readBytes(int(cellCount) * int(offsetSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	cellData, err := r.readBytes(int(dataSize))
+	if err != nil {
+		return nil, err
+	}
+	if hasCRC {
+		checksum, err := r.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		expected := crc32.Checksum(b[:len(b)-4], crc32.MakeTable(crc32.Castagnoli))
+		if binary.LittleEndian.Uint32(checksum) != expected {
+			return nil, fmt.Errorf("invalid boc crc32c")
+		}
+	}
+	if r.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after boc", r.remaining())
+	}
+
+	cells, err := parseBOCCells(cellData, int(cellCount), size)
+	if err != nil {
+		return nil, err
+	}
+	return buildCells(cells)[rootIndex], nil
+}
+
+// parseBOCCells decodes the serialized cells, a ref must point to a later cell so cells are in topological order
+func parseBOCCells(data []byte, count int, refSize int) ([]bocCell, error) {
+	r := &byteReader{data: data}
+	cells := make([]bocCell, count)
+	for i := range cells {
+		d1, err := r.readUint(1)
+		if err != nil {
+			return nil, fmt.Errorf("cell %d: %w", i, err)
+		}
+		d2, err := r.readUint(1)
+		if err != nil {
+			return nil, fmt.Errorf("cell %d: %w", i, err)
+		}
+		// d1 holds the refs count, the exotic flag, the with hashes flag and the level
+		if d1&0xf8 != 0 {
+			return nil, fmt.Errorf("cell %d is not an ordinary cell of level 0", i)
+		}
+		refCount := int(d1 & 0x07)
+		if refCount > maxCellRefs {
+			return nil, fmt.Errorf("cell %d has %d refs", i, refCount)
+		}
+
+		cellData, err := r.readBytes(int(d2+1) / 2)
+		if err != nil {
+			return nil, fmt.Errorf("cell %d: %w", i, err)
+		}
+		cellBits := len(cellData) * 8
+		// an odd bits descriptor means the last byte ends with the completion tag
+		if d2%2 == 1 {
+			last := cellData[len(cellData)-1]
+			if last == 0 {
+				return nil, fmt.Errorf("cell %d has no completion tag", i)
+			}
+			cellBits -= bits.TrailingZeros8(last) + 1
+		}
+		if cellBits > maxCellBits {
+			return nil, fmt.Errorf("cell %d has %d bits", i, cellBits)
+		}
+
+		cells[i] = bocCell{data: cellData, bits: cellBits}
+		for j := 0; j < refCount; j++ {
+			ref, err := r.readUint(refSize)
+			if err != nil {
+				return nil, fmt.Errorf("cell %d: %w", i, err)
+			}
+			if ref <= uint64(i) || ref >= uint64(count) {
+				return nil, fmt.Errorf("cell %d ref %d out of order", i, ref)
+			}
+			cells[i].refs = append(cells[i].refs, int(ref))
+		}
+	}
+	if r.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after cells", r.remaining())
+	}
+	return cells, nil
+}
+
+// buildCells links and hashes the cells from the last one, whose refs are all built
+func buildCells(cells []bocCell) []*Cell {
+	built := make([]*Cell, len(cells))
+	for i := len(cells) - 1; i >= 0; i-- {
+		c := &Cell{data: cells[i].data, bits: cells[i].bits}
+		for _, ref := range cells[i].refs {
+			c.refs = append(c.refs, built[ref])
+		}
+		c.computeHash()
+		built[i] = c
+	}
+	return built
+}
+
+// byteReader reads the big endian integers of a bag of cells
+type byteReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *byteReader) remaining() int {
+	return len(r.data) - r.offset
+}
+
+func (r *byteReader) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > r.remaining() {
+		return nil, fmt.Errorf("read %d bytes at offset %d: out of range", n, r.offset)
+	}
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return b, nil
+}
+
+func (r *byteReader) readUint(n int) (uint64, error) {
+	b, err := r.readBytes(n)
+	if err != nil {
+		return 0, err
+	}
+	var value uint64
+	for _, v := range b {
+		value = value<<8 | uint64(v)
+	}
+	return value, nil
+}
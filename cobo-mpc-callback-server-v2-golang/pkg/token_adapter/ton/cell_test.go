@@ -0,0 +1,265 @@
+package ton
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// builder builds test cells bit by bit
+type builder struct {
+	bits []bool
+	refs []*Cell
+}
+
+func newBuilder() *builder {
+	return &builder{}
+}
+
+func (b *builder) storeBit(bit bool) *builder {
+	b.bits = append(b.bits, bit)
+	return b
+}
+
+func (b *builder) storeUint(value uint64, n int) *builder {
+	for i := n - 1; i >= 0; i-- {
+		b.storeBit(value>>i&1 == 1)
+	}
+	return b
+}
+
+func (b *builder) storeBytes(p []byte) *builder {
+	for _, v := range p {
+		b.storeUint(uint64(v), 8)
+	}
+	return b
+}
+
+func (b *builder) storeCoins(value uint64) *builder {
+	v := new(big.Int).SetUint64(value).Bytes()
+	return b.storeUint(uint64(len(v)), 4).storeBytes(v)
+}
+
+func (b *builder) storeAddress(address *Address) *builder {
+	if address == nil {
+		return b.storeUint(0, 2)
+	}
+	return b.storeUint(0b100, 3).storeUint(uint64(uint8(address.workchain)), 8).storeBytes(address.hash[:])
+}
+
+func (b *builder) storeRef(c *Cell) *builder {
+	b.refs = append(b.refs, c)
+	return b
+}
+
+func (b *builder) endCell() *Cell {
+	c := &Cell{bits: len(b.bits), refs: b.refs, data: make([]byte, (len(b.bits)+7)/8)}
+	for i, bit := range b.bits {
+		if bit {
+			c.data[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	if len(b.bits)%8 != 0 {
+		c.data[len(b.bits)/8] |= 0x80 >> (len(b.bits) % 8)
+	}
+	c.computeHash()
+	return c
+}
+
+// serializeBOC serializes the cells of root in pre-order with one byte refs and a crc32c
+func serializeBOC(root *Cell) []byte {
+	var cells []*Cell
+	var visit func(c *Cell)
+	visit = func(c *Cell) {
+		cells = append(cells, c)
+		for _, ref := range c.refs {
+			visit(ref)
+		}
+	}
+	visit(root)
+
+	index := make(map[*Cell]int)
+	for i, c := range cells {
+		index[c] = i
+	}
+	var data []byte
+	for _, c := range cells {
+		data = append(data, c.descriptors()...)
+		data = append(data, c.data...)
+		for _, ref := range c.refs {
+			data = append(data, byte(index[ref]))
+		}
+	}
+
+	boc := binary.BigEndian.AppendUint32(nil, bocMagic)
+	boc = append(boc, 0x41, 2, byte(len(cells)), 1, 0)
+	boc = binary.BigEndian.AppendUint16(boc, uint16(len(data)))
+	boc = append(boc, 0)
+	boc = append(boc, data...)
+	return binary.LittleEndian.AppendUint32(boc, crc32.Checksum(boc, crc32.MakeTable(crc32.Castagnoli)))
+}
+
+func TestParseBOC(t *testing.T) {
+	emptyCell, err := base64.StdEncoding.DecodeString("te6cckEBAQEAAgAAAEysuc0=")
+	assert.NoError(t, err)
+
+	// a cell of the 12 bits 0xabc with a ref to a cell of the bytes "ref", serialized and hashed by a reference cell
+	// implementation independent of this package
+	valid, err := hex.DecodeString("b5ee9c724102020100000a000103abc80100067265662867fa6e")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		boc       []byte
+		wantHash  string
+		wantError bool
+	}{
+		{
+			name:     "Empty cell",
+			boc:      emptyCell,
+			wantHash: "96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+		},
+		{
+			name:     "Cell with ref",
+			boc:      valid,
+			wantHash: "328339adaadf7b6538b9ec3415c01aa12a4ca391454a6ca12d28c2f3d0060445",
+		},
+		{
+			name:      "Invalid magic",
+			boc:       append([]byte{0}, valid[1:]...),
+			wantError: true,
+		},
+		{
+			name:      "Invalid crc32c",
+			boc:       append(append([]byte{}, valid[:len(valid)-1]...), valid[len(valid)-1]^1),
+			wantError: true,
+		},
+		{
+			name:      "Trailing bytes",
+			boc:       append(append([]byte{}, valid...), 0),
+			wantError: true,
+		},
+		{
+			name:      "Truncated",
+			boc:       valid[:len(valid)-6],
+			wantError: true,
+		},
+		{
+			name:      "Empty",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cell, err := ParseBOC(tt.boc)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			hash := cell.Hash()
+			assert.Equal(t, tt.wantHash, hex.EncodeToString(hash[:]))
+		})
+	}
+}
+
+func TestParseBOCCells(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		count     int
+		wantBits  []int
+		wantError bool
+	}{
+		{
+			name:     "Completion tag",
+			data:     []byte{0, 1, 0xa8},
+			count:    1,
+			wantBits: []int{4},
+		},
+		{
+			name:     "Ref to later cell",
+			data:     []byte{1, 2, 0xff, 1, 0, 0},
+			count:    2,
+			wantBits: []int{8, 0},
+		},
+		{
+			name:      "No completion tag",
+			data:      []byte{0, 1, 0},
+			count:     1,
+			wantError: true,
+		},
+		{
+			name:      "Exotic cell",
+			data:      []byte{8, 0},
+			count:     1,
+			wantError: true,
+		},
+		{
+			name:      "Ref to earlier cell",
+			data:      []byte{0, 0, 1, 0, 0},
+			count:     2,
+			wantError: true,
+		},
+		{
+			name:      "Trailing bytes",
+			data:      []byte{0, 0, 0},
+			count:     1,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells, err := parseBOCCells(tt.data, tt.count, 1)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			for i, bits := range tt.wantBits {
+				assert.Equal(t, bits, cells[i].bits)
+			}
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	address := &Address{workchain: -1, hash: [32]byte{1}}
+	c := newBuilder().storeBit(true).storeUint(300, 9).storeCoins(1000000000).storeAddress(nil).storeAddress(address).
+		storeRef(newBuilder().endCell()).endCell()
+	s := c.BeginParse()
+
+	bit, err := s.LoadBit()
+	assert.NoError(t, err)
+	assert.True(t, bit)
+	value, err := s.LoadUint(9)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), value)
+	coins, err := s.LoadCoins()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000000000), coins)
+	none, err := s.LoadAddress()
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+	loaded, err := s.LoadAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, address, loaded)
+
+	assert.Error(t, s.EndParse())
+	_, err = s.LoadRef()
+	assert.NoError(t, err)
+	assert.NoError(t, s.EndParse())
+	_, err = s.LoadBit()
+	assert.Error(t, err)
+
+	external := newBuilder().storeUint(0b01, 2).storeUint(0, 9).endCell()
+	_, err = external.BeginParse().LoadAddress()
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package ton
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+)
+
+// JettonConfig declares a jetton registered with the ton adapter at startup
+type JettonConfig struct {
+	TokenID string `mapstructure:"token_id"`
+	// JettonMaster is the jetton master contract, the asset of the jetton transfers
+	JettonMaster string `mapstructure:"jetton_master"`
+	// JettonWallets are the jetton wallets of the source wallets, a jetton transfer sent to another contract is rejected
+	JettonWallets []string `mapstructure:"jetton_wallets"`
+	Decimals      int32    `mapstructure:"decimals"`
+}
+
+// NewJettonTokenCreator returns the creator of a jetton declared in config
+func NewJettonTokenCreator(cfg JettonConfig) (token_adapter.TokenCreator, error) {
+	if strings.TrimSpace(cfg.TokenID) == "" {
+		return nil, fmt.Errorf("jetton token id is empty")
+	}
+	if _, err := ParseAddress(cfg.JettonMaster); err != nil {
+		return nil, fmt.Errorf("invalid jetton master of jetton %v: %w", cfg.TokenID, err)
+	}
+	if len(cfg.JettonWallets) == 0 {
+		return nil, fmt.Errorf("jetton wallets of jetton %v are empty", cfg.TokenID)
+	}
+	if cfg.Decimals < 0 {
+		return nil, fmt.Errorf("decimals of jetton %v is negative", cfg.TokenID)
+	}
+
+	jettonWallets := make(map[Address]bool, len(cfg.JettonWallets))
+	for _, wallet := range cfg.JettonWallets {
+		address, err := ParseAddress(wallet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jetton wallet of jetton %v: %w", cfg.TokenID, err)
+		}
+		jettonWallets[address] = true
+	}
+
+	return func(tokenID string) token_adapter.Token {
+		return &Token{
+			tokenID:       tokenID,
+			jettonMaster:  cfg.JettonMaster,
+			jettonWallets: jettonWallets,
+		}
+	}, nil
+}
@@ -0,0 +1,48 @@
+package ton
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJettonTokenCreator(t *testing.T) {
+	wallet := testAddress(8)
+	valid := JettonConfig{
+		TokenID:       "TON_USDT",
+		JettonMaster:  testAddress(7).UserFriendly(true),
+		JettonWallets: []string{wallet.String()},
+		Decimals:      6,
+	}
+
+	tests := []struct {
+		name      string
+		update    func(cfg *JettonConfig)
+		wantError bool
+	}{
+		{name: "Valid jetton", update: func(cfg *JettonConfig) {}},
+		{name: "Empty token id", update: func(cfg *JettonConfig) { cfg.TokenID = " " }, wantError: true},
+		{name: "Invalid jetton master", update: func(cfg *JettonConfig) { cfg.JettonMaster = "EQ" }, wantError: true},
+		{name: "Empty jetton wallets", update: func(cfg *JettonConfig) { cfg.JettonWallets = nil }, wantError: true},
+		{name: "Invalid jetton wallet", update: func(cfg *JettonConfig) { cfg.JettonWallets = []string{"0:00"} }, wantError: true},
+		{name: "Negative decimals", update: func(cfg *JettonConfig) { cfg.Decimals = -1 }, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.update(&cfg)
+			creator, err := NewJettonTokenCreator(cfg)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			token, ok := creator(cfg.TokenID).(*Token)
+			assert.True(t, ok)
+			assert.Equal(t, cfg.JettonMaster, token.jettonMaster)
+			assert.Equal(t, map[Address]bool{*wallet: true}, token.jettonWallets)
+		})
+	}
+}
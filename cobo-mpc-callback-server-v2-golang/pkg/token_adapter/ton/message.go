@@ -0,0 +1,244 @@
+package ton
+
+import (
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+)
+
+const (
+	// opComment is the op of a text comment, the text follows the op and continues in the ref
+	opComment = 0
+	// opJettonTransfer is the op of a transfer request to a jetton wallet
+	opJettonTransfer = 0x0f8a7ea5
+)
+
+// InternalMessage is an internal message sent by a wallet
+type InternalMessage struct {
+	bounce      bool
+	destination Address
+	// value in nanotons
+	value *big.Int
+	body  *MessageBody
+}
+
+// MessageBody is the decoded body of an internal message, an empty body is a plain transfer
+type MessageBody struct {
+	comment string
+	jetton  *JettonTransfer
+}
+
+// JettonTransfer is a transfer request to a jetton wallet of the sender
+type JettonTransfer struct {
+	// amount in the jetton base unit
+	amount      *big.Int
+	destination Address
+	// responseDestination receives the excess of the attached value, nil for none
+	responseDestination *Address
+	forwardTonAmount    *big.Int
+	comment             string
+}
+
+// ParseInternalMessage decodes an internal message of a wallet request. A message with extra currencies or
+// a state init is rejected, as is a body other than a comment or a jetton transfer.
+func ParseInternalMessage(c *Cell) (*InternalMessage, error) {
+	s := c.BeginParse()
+	external, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if external {
+		return nil, fmt.Errorf("external out message is not supported")
+	}
+
+	msg := &InternalMessage{}
+	// ihr_disabled, bounce and bounced flags
+	if _, err := s.LoadBit(); err != nil {
+		return nil, err
+	}
+	if msg.bounce, err = s.LoadBit(); err != nil {
+		return nil, err
+	}
+	if _, err := s.LoadBit(); err != nil {
+		return nil, err
+	}
+
+	// the source is set by the wallet
+	if _, err := s.LoadAddress(); err != nil {
+		return nil, fmt.Errorf("decode source: %w", err)
+	}
+	destination, err := s.LoadAddress()
+	if err != nil {
+		return nil, fmt.Errorf("decode destination: %w", err)
+	}
+	if destination == nil {
+		return nil, fmt.Errorf("message has no destination")
+	}
+	msg.destination = *destination
+
+	if msg.value, err = s.LoadCoins(); err != nil {
+		return nil, fmt.Errorf("decode value: %w", err)
+	}
+	hasExtraCurrencies, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if hasExtraCurrencies {
+		return nil, fmt.Errorf("extra currencies are not supported")
+	}
+	// ihr_fee and fwd_fee, then created_lt and created_at
+	for i := 0; i < 2; i++ {
+		if _, err := s.LoadCoins(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.LoadUint(64); err != nil {
+		return nil, err
+	}
+	if _, err := s.LoadUint(32); err != nil {
+		return nil, err
+	}
+
+	hasInit, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if hasInit {
+		return nil, fmt.Errorf("message with state init is not supported")
+	}
+
+	body, err := loadEither(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode body: %w", err)
+	}
+	if msg.body, err = parseMessageBody(body); err != nil {
+		return nil, fmt.Errorf("decode body: %w", err)
+	}
+	return msg, nil
+}
+
+// loadEither reads an Either X ^X, the rest of the slice for the inline value or a slice of the ref
+func loadEither(s *Slice) (*Slice, error) {
+	isRef, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if !isRef {
+		return s, nil
+	}
+	ref, err := s.LoadRef()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EndParse(); err != nil {
+		return nil, err
+	}
+	return ref.BeginParse(), nil
+}
+
+func parseMessageBody(s *Slice) (*MessageBody, error) {
+	body := &MessageBody{}
+	if s.RemainingBits() == 0 && s.RemainingRefs() == 0 {
+		return body, nil
+	}
+
+	op, err := s.LoadUint(32)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case opComment:
+		body.comment, err = parseComment(s)
+	case opJettonTransfer:
+		body.jetton, err = parseJettonTransfer(s)
+	default:
+		return nil, fmt.Errorf("unsupported op %08x", op)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// parseComment reads the UTF-8 text of a comment, which continues in the single ref of each cell
+func parseComment(s *Slice) (string, error) {
+	var text []byte
+	for {
+		if s.RemainingBits()%8 != 0 {
+			return "", fmt.Errorf("comment of %d bits is not bytes", s.RemainingBits())
+		}
+		b, err := s.LoadBytes(s.RemainingBits() / 8)
+		if err != nil {
+			return "", err
+		}
+		text = append(text, b...)
+
+		if s.RemainingRefs() == 0 {
+			break
+		}
+		next, err := s.LoadRef()
+		if err != nil {
+			return "", err
+		}
+		if err := s.EndParse(); err != nil {
+			return "", err
+		}
+		s = next.BeginParse()
+	}
+
+	if !utf8.Valid(text) {
+		return "", fmt.Errorf("comment is not UTF-8 text")
+	}
+	return string(text), nil
+}
+
+// parseJettonTransfer reads a jetton transfer request, a custom payload or a forward payload other than
+// a comment is rejected
+func parseJettonTransfer(s *Slice) (*JettonTransfer, error) {
+	// query_id
+	if _, err := s.LoadUint(64); err != nil {
+		return nil, err
+	}
+
+	transfer := &JettonTransfer{}
+	var err error
+	if transfer.amount, err = s.LoadCoins(); err != nil {
+		return nil, fmt.Errorf("decode jetton amount: %w", err)
+	}
+	destination, err := s.LoadAddress()
+	if err != nil {
+		return nil, fmt.Errorf("decode jetton destination: %w", err)
+	}
+	if destination == nil {
+		return nil, fmt.Errorf("jetton transfer has no destination")
+	}
+	transfer.destination = *destination
+	if transfer.responseDestination, err = s.LoadAddress(); err != nil {
+		return nil, fmt.Errorf("decode jetton response destination: %w", err)
+	}
+
+	hasCustomPayload, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if hasCustomPayload {
+		return nil, fmt.Errorf("jetton custom payload is not supported")
+	}
+	if transfer.forwardTonAmount, err = s.LoadCoins(); err != nil {
+		return nil, fmt.Errorf("decode jetton forward ton amount: %w", err)
+	}
+
+	payload, err := loadEither(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode jetton forward payload: %w", err)
+	}
+	forward, err := parseMessageBody(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode jetton forward payload: %w", err)
+	}
+	if forward.jetton != nil {
+		return nil, fmt.Errorf("jetton forward payload is a jetton transfer")
+	}
+	transfer.comment = forward.comment
+	return transfer, nil
+}
@@ -0,0 +1,197 @@
+package ton
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testAddress(n byte) *Address {
+	return &Address{hash: [32]byte{n}}
+}
+
+// internalMessage builds an internal message, the body is stored in a ref unless it is nil
+func internalMessage(destination *Address, bounce bool, value uint64, body *Cell) *Cell {
+	b := newBuilder().storeUint(0, 1).storeBit(true).storeBit(bounce).storeBit(false).
+		storeAddress(nil).storeAddress(destination).storeCoins(value).storeBit(false).
+		storeCoins(0).storeCoins(0).storeUint(0, 64).storeUint(0, 32).storeBit(false)
+	if body == nil {
+		return b.storeBit(false).endCell()
+	}
+	return b.storeBit(true).storeRef(body).endCell()
+}
+
+// commentBody builds a comment whose text is split in cells of at most 100 bytes
+func commentBody(text string) *Cell {
+	var chunks []string
+	for len(text) > 100 {
+		chunks = append(chunks, text[:100])
+		text = text[100:]
+	}
+	chunks = append(chunks, text)
+
+	var next *Cell
+	for i := len(chunks) - 1; i >= 0; i-- {
+		b := newBuilder()
+		if i == 0 {
+			b.storeUint(opComment, 32)
+		}
+		b.storeBytes([]byte(chunks[i]))
+		if next != nil {
+			b.storeRef(next)
+		}
+		next = b.endCell()
+	}
+	return next
+}
+
+// jettonTransferBody builds a jetton transfer, the forward payload is stored in a ref unless it is nil
+func jettonTransferBody(amount uint64, destination, response *Address, forwardPayload *Cell) *Cell {
+	b := newBuilder().storeUint(opJettonTransfer, 32).storeUint(1, 64).storeCoins(amount).
+		storeAddress(destination).storeAddress(response).storeBit(false).storeCoins(1)
+	if forwardPayload == nil {
+		return b.storeBit(false).endCell()
+	}
+	return b.storeBit(true).storeRef(forwardPayload).endCell()
+}
+
+func TestParseInternalMessage(t *testing.T) {
+	longComment := strings.Repeat("memo", 40)
+
+	tests := []struct {
+		name        string
+		message     *Cell
+		wantBounce  bool
+		wantComment string
+		wantJetton  bool
+		wantError   bool
+	}{
+		{
+			name:       "Plain transfer",
+			message:    internalMessage(testAddress(1), true, 100, nil),
+			wantBounce: true,
+		},
+		{
+			name:        "Comment",
+			message:     internalMessage(testAddress(1), false, 100, commentBody("123456")),
+			wantComment: "123456",
+		},
+		{
+			name:        "Comment in several cells",
+			message:     internalMessage(testAddress(1), false, 100, commentBody(longComment)),
+			wantComment: longComment,
+		},
+		{
+			name:       "Jetton transfer",
+			message:    internalMessage(testAddress(1), true, 100, jettonTransferBody(5, testAddress(2), nil, nil)),
+			wantBounce: true,
+			wantJetton: true,
+		},
+		{
+			name:      "Unsupported op",
+			message:   internalMessage(testAddress(1), true, 100, newBuilder().storeUint(0x12345678, 32).endCell()),
+			wantError: true,
+		},
+		{
+			name:      "Comment not UTF-8",
+			message:   internalMessage(testAddress(1), true, 100, newBuilder().storeUint(0, 32).storeBytes([]byte{0xff}).endCell()),
+			wantError: true,
+		},
+		{
+			name:      "No destination",
+			message:   internalMessage(nil, true, 100, nil),
+			wantError: true,
+		},
+		{
+			name: "State init",
+			message: newBuilder().storeUint(0, 1).storeBit(true).storeBit(true).storeBit(false).
+				storeAddress(nil).storeAddress(testAddress(1)).storeCoins(1).storeBit(false).
+				storeCoins(0).storeCoins(0).storeUint(0, 64).storeUint(0, 32).storeBit(true).endCell(),
+			wantError: true,
+		},
+		{
+			name: "Extra currencies",
+			message: newBuilder().storeUint(0, 1).storeBit(true).storeBit(true).storeBit(false).
+				storeAddress(nil).storeAddress(testAddress(1)).storeCoins(1).storeBit(true).endCell(),
+			wantError: true,
+		},
+		{
+			name:      "External message",
+			message:   newBuilder().storeUint(0b11, 2).endCell(),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseInternalMessage(tt.message)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, *testAddress(1), msg.destination)
+			assert.Equal(t, big.NewInt(100), msg.value)
+			assert.Equal(t, tt.wantBounce, msg.bounce)
+			assert.Equal(t, tt.wantComment, msg.body.comment)
+			assert.Equal(t, tt.wantJetton, msg.body.jetton != nil)
+		})
+	}
+}
+
+func TestParseJettonTransfer(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        *Cell
+		wantComment string
+		wantError   bool
+	}{
+		{
+			name: "No forward payload",
+			body: jettonTransferBody(5, testAddress(2), testAddress(3), nil),
+		},
+		{
+			name:        "Comment forward payload",
+			body:        jettonTransferBody(5, testAddress(2), testAddress(3), commentBody("123456")),
+			wantComment: "123456",
+		},
+		{
+			name:      "Unsupported forward payload",
+			body:      jettonTransferBody(5, testAddress(2), nil, newBuilder().storeUint(0x12345678, 32).endCell()),
+			wantError: true,
+		},
+		{
+			name:      "Jetton transfer forward payload",
+			body:      jettonTransferBody(5, testAddress(2), nil, jettonTransferBody(5, testAddress(4), nil, nil)),
+			wantError: true,
+		},
+		{
+			name: "Custom payload",
+			body: newBuilder().storeUint(opJettonTransfer, 32).storeUint(1, 64).storeCoins(5).
+				storeAddress(testAddress(2)).storeAddress(nil).storeBit(true).storeRef(newBuilder().endCell()).endCell(),
+			wantError: true,
+		},
+		{
+			name:      "No destination",
+			body:      jettonTransferBody(5, nil, nil, nil),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := parseMessageBody(tt.body.BeginParse())
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, big.NewInt(5), body.jetton.amount)
+			assert.Equal(t, *testAddress(2), body.jetton.destination)
+			assert.Equal(t, testAddress(3), body.jetton.responseDestination)
+			assert.Equal(t, tt.wantComment, body.jetton.comment)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package ton
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Slice reads the bits and refs of a cell in order
+type Slice struct {
+	cell   *Cell
+	bitPos int
+	refPos int
+}
+
+// BeginParse returns a slice reading the cell from its start
+func (c *Cell) BeginParse() *Slice {
+	return &Slice{cell: c}
+}
+
+// RemainingBits returns the number of bits left
+func (s *Slice) RemainingBits() int {
+	return s.cell.bits - s.bitPos
+}
+
+// RemainingRefs returns the number of refs left
+func (s *Slice) RemainingRefs() int {
+	return len(s.cell.refs) - s.refPos
+}
+
+// EndParse rejects the bits and refs left, a value is decoded from all of its cell
+func (s *Slice) EndParse() error {
+	if s.RemainingBits() != 0 || s.RemainingRefs() != 0 {
+		return fmt.Errorf("%d trailing bits and %d trailing refs", s.RemainingBits(), s.RemainingRefs())
+	}
+	return nil
+}
+
+func (s *Slice) LoadBit() (bool, error) {
+	if s.RemainingBits() < 1 {
+		return false, fmt.Errorf("read bit at %d: out of range", s.bitPos)
+	}
+	bit := s.cell.data[s.bitPos/8]>>(7-s.bitPos%8)&1 == 1
+	s.bitPos++
+	return bit, nil
+}
+
+// LoadUint reads an unsigned integer of n bits, n is at most 64
+func (s *Slice) LoadUint(n int) (uint64, error) {
+	if n < 0 || n > 64 || n > s.RemainingBits() {
+		return 0, fmt.Errorf("read %d bits at %d: out of range", n, s.bitPos)
+	}
+	var value uint64
+	for i := 0; i < n; i++ {
+		bit, _ := s.LoadBit()
+		value <<= 1
+		if bit {
+			value |= 1
+		}
+	}
+	return value, nil
+}
+
+// LoadBytes reads n bytes
+func (s *Slice) LoadBytes(n int) ([]byte, error) {
+	if n < 0 || n*8 > s.RemainingBits() {
+		return nil, fmt.Errorf("read %d bytes at %d: out of range", n, s.bitPos)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		v, _ := s.LoadUint(8)
+		b[i] = byte(v)
+	}
+	return b, nil
+}
+
+// LoadCoins reads an amount of VarUInteger 16, a 4 bits length followed by the bytes of the value
+func (s *Slice) LoadCoins() (*big.Int, error) {
+	length, err := s.LoadUint(4)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.LoadBytes(int(length))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (s *Slice) LoadRef() (*Cell, error) {
+	if s.RemainingRefs() < 1 {
+		return nil, fmt.Errorf("read ref %d: out of range", s.refPos)
+	}
+	ref := s.cell.refs[s.refPos]
+	s.refPos++
+	return ref, nil
+}
+
+// LoadAddress reads a MsgAddress, nil for addr_none. An external or variable length address is rejected.
+func (s *Slice) LoadAddress() (*Address, error) {
+	tag, err := s.LoadUint(2)
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case 0b00:
+		return nil, nil
+	case 0b10:
+	default:
+		return nil, fmt.Errorf("unsupported address tag %02b", tag)
+	}
+
+	anycast, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if anycast {
+		return nil, fmt.Errorf("anycast address is not supported")
+	}
+	workchain, err := s.LoadUint(8)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := s.LoadBytes(32)
+	if err != nil {
+		return nil, err
+	}
+
+	address := &Address{workchain: int8(workchain)}
+	copy(address.hash[:], hash)
+	return address, nil
+}
@@ -0,0 +1,65 @@
+package ton
+
+import (
+	"fmt"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Token struct {
+	tokenID string
+	// jettonMaster is the asset of a jetton declared in config, jettonWallets are its wallets allowed as destination
+	// of a jetton transfer; the TON token sends no jetton
+	jettonMaster  string
+	jettonWallets map[Address]bool
+}
+
+// NewToken returns the TON token, whose transactions are requests to a wallet v3, v4 or v5
+func NewToken(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID: tokenID,
+	}
+}
+
+func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	preTxData, err := prepareBuildTransactionData(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
+	}
+
+	root, err := ParseBOC(preTxData.rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("prepare ton boc error: %w", err)
+	}
+	body, err := ParseWalletBody(root)
+	if err != nil {
+		return nil, fmt.Errorf("prepare ton wallet body error: %w", err)
+	}
+
+	return &Transaction{tx: body, root: root, PrepareTransactionData: preTxData, token: t}, nil
+}
+
+func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *PrepareTransactionData, err error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.RawTxInfo == nil || txInfo.Transaction.RawTxInfo.UnsignedRawTx == nil {
+		return nil, fmt.Errorf("transaction info raw tx is nil")
+	}
+
+	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
+	rawTxBytes := common.FromHex(rawTx)
+	if len(rawTxBytes) == 0 {
+		return nil, fmt.Errorf("transaction raw tx is empty")
+	}
+
+	data = &PrepareTransactionData{rawTx: rawTxBytes, chainID: txInfo.Transaction.GetChainId()}
+	if len(txInfo.SourceAddresses) > 0 {
+		source, err := ParseAddress(txInfo.SourceAddresses[0].Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source address: %w", err)
+		}
+		data.sourceAddress = txInfo.SourceAddresses[0].Address
+		data.source = &source
+	}
+
+	return data, nil
+}
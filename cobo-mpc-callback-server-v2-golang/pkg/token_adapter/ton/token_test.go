@@ -0,0 +1,100 @@
+package ton
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("TON")
+	tonToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "TON", tonToken.tokenID)
+}
+
+func TestToken_BuildTransaction(t *testing.T) {
+	// a wallet v5 request sending 100 nanotons with the comment 123456 to EQCxE6mU...sDs in mode 3, serialized and
+	// hashed by a reference cell implementation independent of this package
+	valid := common.FromHex("b5ee9c724102050100005f0001217369676e7fffff116553f10000000007a001020a0ec3c86d030203000001626200" +
+		"5889d4ca5a81250b38cfb489c99475bacacb61c512fac81458a37f66e1b10eff0b2000000000000000000000000001040014000000003132333435362ea26c66")
+	source := testAddress(9).UserFriendly(false)
+
+	tests := []struct {
+		name      string
+		rawTx     []byte
+		source    string
+		wantError bool
+	}{
+		{
+			name:   "Valid wallet v5 body",
+			rawTx:  valid,
+			source: source,
+		},
+		{
+			name:  "Without source address",
+			rawTx: valid,
+		},
+		{
+			name:      "Invalid source address",
+			rawTx:     valid,
+			source:    "0x8B45b84e2cF29E5F826797dF7e1Aa93FC71a2bfd",
+			wantError: true,
+		},
+		{
+			name:      "Not a wallet body",
+			rawTx:     serializeBOC(newBuilder().storeUint(1, 32).endCell()),
+			wantError: true,
+		},
+		{
+			name:      "Invalid boc",
+			rawTx:     valid[:len(valid)-1],
+			wantError: true,
+		},
+		{
+			name:      "Empty raw tx",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTx := hex.EncodeToString(tt.rawTx)
+			txInfo := &token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &rawTx},
+				},
+			}
+			if tt.source != "" {
+				txInfo.SourceAddresses = []coboWaaS2.AddressInfo{{Address: tt.source}}
+			}
+
+			tx, err := NewToken("TON").BuildTransaction(txInfo)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			// the signed hash is the representation hash of the body cell
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"0xbb05b78de7bb0f0142be0d01edd8067d8ee6d2e5a97c31afee4ede18cc0ac18d"}, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.source, transfers[0].From)
+			assert.Equal(t, "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs", transfers[0].To)
+			assert.Equal(t, big.NewInt(100), transfers[0].Amount)
+			assert.Equal(t, "123456", transfers[0].Tag)
+		})
+	}
+
+	_, err := NewToken("TON").BuildTransaction(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package ton
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+)
+
+const (
+	defaultChainID = "TON"
+
+	methodJettonTransfer = "jettonTransfer"
+)
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx   *WalletBody
+	root *Cell
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx   []byte
+	chainID string
+	// sourceAddress is the wallet sending the request as given, source is its parsed address
+	sourceAddress string
+	source        *Address
+}
+
+// GetHashes implements Transaction interface for TON, the hash is the representation hash of the body cell
+func (t *Transaction) GetHashes() ([]string, error) {
+	if t.root == nil {
+		return nil, fmt.Errorf("transaction body cell is nil")
+	}
+
+	hash := t.root.Hash()
+	return []string{"0x" + hex.EncodeToString(hash[:])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for TON
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no destination addresses parse in transaction")
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for TON, each internal message is a transfer.
+// The recipient of a message is in the user-friendly form with the bounce flag of the message, and a comment is
+// both the memo and the tag, since exchanges tell their users apart by comment.
+// A jetton transfer is a transfer of the jetton master to the jetton recipient in the non-bounceable form, and is
+// rejected unless the destination of the message is a jetton wallet of the token. The value attached to the message
+// is a TON transfer to the jetton wallet, which is also counted in the fee.
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	var transfers []token_adapter.Transfer
+	for idx, out := range t.tx.messages {
+		msg, err := ParseInternalMessage(out.message)
+		if err != nil {
+			return nil, fmt.Errorf("decode message %d: %w", idx, err)
+		}
+
+		jetton := msg.body.jetton
+		if jetton == nil {
+			transfers = append(transfers, token_adapter.Transfer{
+				Chain:  chainID,
+				From:   t.sourceAddress,
+				To:     msg.destination.UserFriendly(msg.bounce),
+				Amount: msg.value,
+				Memo:   msg.body.comment,
+				Tag:    msg.body.comment,
+			})
+			continue
+		}
+
+		// the excess of the attached value must return to the wallet
+		if jetton.responseDestination != nil && (t.source == nil || *jetton.responseDestination != *t.source) {
+			return nil, fmt.Errorf("message %d jetton response destination %v is not the source address", idx,
+				jetton.responseDestination)
+		}
+		if !t.token.jettonWallets[msg.destination] {
+			return nil, fmt.Errorf("message %d destination %v is not a jetton wallet of token %v", idx,
+				msg.destination.UserFriendly(true), t.token.tokenID)
+		}
+		transfers = append(transfers, token_adapter.Transfer{
+			Chain:  chainID,
+			From:   t.sourceAddress,
+			To:     msg.destination.UserFriendly(msg.bounce),
+			Amount: msg.value,
+		}, token_adapter.Transfer{
+			Chain:  chainID,
+			Asset:  t.token.jettonMaster,
+			From:   t.sourceAddress,
+			To:     jetton.destination.UserFriendly(false),
+			Amount: jetton.amount,
+			Memo:   jetton.comment,
+			Tag:    jetton.comment,
+			Method: methodJettonTransfer,
+		})
+	}
+
+	return transfers, nil
+}
+
+// GetFee implements FeeTransaction interface for TON. The network fee is paid from the wallet balance and is not
+// part of the request, so the fee limit is the value attached to jetton transfers.
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	feeLimit := new(big.Int)
+	for idx, out := range t.tx.messages {
+		msg, err := ParseInternalMessage(out.message)
+		if err != nil {
+			return nil, fmt.Errorf("decode message %d: %w", idx, err)
+		}
+		if msg.body.jetton != nil {
+			feeLimit.Add(feeLimit, msg.value)
+		}
+	}
+	return &token_adapter.Fee{FeeLimit: feeLimit}, nil
+}
@@ -0,0 +1,115 @@
+package ton
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	source := testAddress(9)
+	from := source.UserFriendly(false)
+	jettonWallet := testAddress(8)
+	jettonMaster := testAddress(7).UserFriendly(true)
+	tonToken := &Token{tokenID: "TON"}
+	jettonToken := &Token{tokenID: "TON_USDT", jettonMaster: jettonMaster, jettonWallets: map[Address]bool{*jettonWallet: true}}
+
+	tests := []struct {
+		name          string
+		token         *Token
+		body          *Cell
+		source        *Address
+		wantTransfers []token_adapter.Transfer
+		wantFeeLimit  *big.Int
+		wantError     bool
+	}{
+		{
+			name:  "Transfers with comment",
+			token: tonToken,
+			body: walletV3Body(true, 3,
+				internalMessage(testAddress(1), true, 100, nil),
+				internalMessage(testAddress(2), false, 200, commentBody("123456"))),
+			source: source,
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, From: from, To: testAddress(1).UserFriendly(true), Amount: big.NewInt(100)},
+				{Chain: defaultChainID, From: from, To: testAddress(2).UserFriendly(false), Amount: big.NewInt(200), Memo: "123456", Tag: "123456"},
+			},
+			wantFeeLimit: big.NewInt(0),
+		},
+		{
+			name:  "Jetton transfer with comment",
+			token: jettonToken,
+			body: walletV5Body(3,
+				internalMessage(jettonWallet, true, 50000000, jettonTransferBody(5, testAddress(2), source, commentBody("123456")))),
+			source: source,
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: defaultChainID, From: from, To: jettonWallet.UserFriendly(true), Amount: big.NewInt(50000000)},
+				{
+					Chain: defaultChainID, Asset: jettonMaster, From: from, To: testAddress(2).UserFriendly(false),
+					Amount: big.NewInt(5), Memo: "123456", Tag: "123456", Method: methodJettonTransfer,
+				},
+			},
+			wantFeeLimit: big.NewInt(50000000),
+		},
+		{
+			name:      "Jetton transfer to another contract",
+			token:     jettonToken,
+			body:      walletV5Body(3, internalMessage(testAddress(3), true, 50000000, jettonTransferBody(5, testAddress(2), source, nil))),
+			source:    source,
+			wantError: true,
+		},
+		{
+			name:      "Jetton transfer of the TON token",
+			token:     tonToken,
+			body:      walletV5Body(3, internalMessage(jettonWallet, true, 50000000, jettonTransferBody(5, testAddress(2), source, nil))),
+			source:    source,
+			wantError: true,
+		},
+		{
+			name:      "Jetton excess to another address",
+			token:     jettonToken,
+			body:      walletV5Body(3, internalMessage(jettonWallet, true, 50000000, jettonTransferBody(5, testAddress(2), testAddress(3), nil))),
+			source:    source,
+			wantError: true,
+		},
+		{
+			name:      "Jetton excess without source address",
+			token:     jettonToken,
+			body:      walletV5Body(3, internalMessage(jettonWallet, true, 50000000, jettonTransferBody(5, testAddress(2), source, nil))),
+			wantError: true,
+		},
+		{
+			name:      "Unsupported message body",
+			token:     tonToken,
+			body:      walletV3Body(false, 3, internalMessage(testAddress(1), true, 100, newBuilder().storeUint(7, 32).endCell())),
+			source:    source,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := ParseWalletBody(tt.body)
+			assert.NoError(t, err)
+			data := &PrepareTransactionData{source: tt.source}
+			if tt.source != nil {
+				data.sourceAddress = tt.source.UserFriendly(false)
+			}
+			tx := &Transaction{tx: body, root: tt.body, PrepareTransactionData: data, token: tt.token}
+
+			transfers, err := tx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+
+			fee, err := tx.GetFee()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Fee{FeeLimit: tt.wantFeeLimit}, fee)
+		})
+	}
+}
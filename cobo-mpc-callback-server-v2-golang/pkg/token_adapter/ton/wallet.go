@@ -0,0 +1,205 @@
+package ton
+
+import (
+	"fmt"
+)
+
+// Wallet versions
+const (
+	WalletV3 = "v3"
+	WalletV4 = "v4"
+	WalletV5 = "v5"
+)
+
+const (
+	// walletV5ExternalSigned is the op of a signed external request to a wallet v5
+	walletV5ExternalSigned = 0x7369676e
+	// actionSendMsg is the tag of the send message action in the out actions list of a wallet v5
+	actionSendMsg = 0x0ec3c86d
+	// walletV4OpSend is the op of a simple send request to a wallet v4, the other ops install or remove plugins
+	walletV4OpSend = 0
+
+	// walletHeaderBits is the size of the wallet id, valid until and seqno of a request
+	walletHeaderBits    = 96
+	maxWalletV3Messages = 4
+	maxWalletV5Actions  = 255
+
+	// sendModePayFeesSeparately, sendModeIgnoreErrors and sendModeBounceOnActionFail are the supported send modes,
+	// a mode carrying the balance or the inbound value sends an amount that is not in the message
+	sendModePayFeesSeparately  = 1
+	sendModeIgnoreErrors       = 2
+	sendModeBounceOnActionFail = 16
+	supportedSendModes         = sendModePayFeesSeparately | sendModeIgnoreErrors | sendModeBounceOnActionFail
+)
+
+// WalletBody is the unsigned body of an external request to a wallet v3, v4 or v5
+type WalletBody struct {
+	version    string
+	walletID   uint32
+	validUntil uint32
+	seqno      uint32
+	messages   []OutMessage
+}
+
+// OutMessage is an internal message sent by a wallet with its send mode
+type OutMessage struct {
+	mode    uint8
+	message *Cell
+}
+
+// ParseWalletBody decodes the unsigned body of a wallet request. The wallet version is told by the size of the body:
+// a wallet v3 body has a send mode and a message ref for each message, a wallet v4 body adds an op before them,
+// and a wallet v5 body has an op, the header and the out actions list.
+func ParseWalletBody(root *Cell) (*WalletBody, error) {
+	s := root.BeginParse()
+	refs := len(root.refs)
+
+	var body *WalletBody
+	var err error
+	switch {
+	case root.bits == 32+walletHeaderBits+2 && refs <= 1:
+		body, err = parseWalletV5Body(s)
+	case root.bits == walletHeaderBits+8*refs && refs <= maxWalletV3Messages:
+		body, err = parseWalletV3Body(s, WalletV3)
+	case root.bits == walletHeaderBits+8+8*refs && refs <= maxWalletV3Messages:
+		body, err = parseWalletV3Body(s, WalletV4)
+	default:
+		return nil, fmt.Errorf("unsupported wallet body of %d bits and %d refs", root.bits, refs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EndParse(); err != nil {
+		return nil, fmt.Errorf("decode wallet %v body: %w", body.version, err)
+	}
+
+	if len(body.messages) == 0 {
+		return nil, fmt.Errorf("wallet %v body sends no message", body.version)
+	}
+	for idx, msg := range body.messages {
+		if msg.mode&^supportedSendModes != 0 {
+			return nil, fmt.Errorf("message %d has unsupported send mode %d", idx, msg.mode)
+		}
+	}
+	return body, nil
+}
+
+func (b *WalletBody) readHeader(s *Slice) error {
+	for _, field := range []*uint32{&b.walletID, &b.validUntil, &b.seqno} {
+		value, err := s.LoadUint(32)
+		if err != nil {
+			return err
+		}
+		*field = uint32(value)
+	}
+	return nil
+}
+
+// parseWalletV3Body decodes a wallet v3 or v4 body, whose messages are in the refs of the body
+func parseWalletV3Body(s *Slice, version string) (*WalletBody, error) {
+	body := &WalletBody{version: version}
+	if err := body.readHeader(s); err != nil {
+		return nil, fmt.Errorf("decode wallet %v header: %w", version, err)
+	}
+	if version == WalletV4 {
+		op, err := s.LoadUint(8)
+		if err != nil {
+			return nil, err
+		}
+		if op != walletV4OpSend {
+			return nil, fmt.Errorf("unsupported wallet v4 op %d", op)
+		}
+	}
+
+	for s.RemainingRefs() > 0 {
+		mode, err := s.LoadUint(8)
+		if err != nil {
+			return nil, err
+		}
+		message, err := s.LoadRef()
+		if err != nil {
+			return nil, err
+		}
+		body.messages = append(body.messages, OutMessage{mode: uint8(mode), message: message})
+	}
+	return body, nil
+}
+
+// parseWalletV5Body decodes a wallet v5 body, whose send message actions are in the out actions list.
+// Extended actions such as adding an extension are rejected.
+func parseWalletV5Body(s *Slice) (*WalletBody, error) {
+	op, err := s.LoadUint(32)
+	if err != nil {
+		return nil, err
+	}
+	if op != walletV5ExternalSigned {
+		return nil, fmt.Errorf("unsupported wallet v5 op %08x", op)
+	}
+
+	body := &WalletBody{version: WalletV5}
+	if err := body.readHeader(s); err != nil {
+		return nil, fmt.Errorf("decode wallet v5 header: %w", err)
+	}
+
+	hasActions, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if hasActions {
+		list, err := s.LoadRef()
+		if err != nil {
+			return nil, err
+		}
+		if body.messages, err = parseOutActions(list); err != nil {
+			return nil, fmt.Errorf("decode wallet v5 out actions: %w", err)
+		}
+	}
+
+	hasExtendedActions, err := s.LoadBit()
+	if err != nil {
+		return nil, err
+	}
+	if hasExtendedActions {
+		return nil, fmt.Errorf("wallet v5 extended actions are not supported")
+	}
+	return body, nil
+}
+
+// parseOutActions decodes an out actions list, each node holds the previous node and an action,
+// so the last action is in the first node
+func parseOutActions(list *Cell) ([]OutMessage, error) {
+	var messages []OutMessage
+	for list.bits != 0 || len(list.refs) != 0 {
+		if len(messages) == maxWalletV5Actions {
+			return nil, fmt.Errorf("more than %d actions", maxWalletV5Actions)
+		}
+
+		s := list.BeginParse()
+		prev, err := s.LoadRef()
+		if err != nil {
+			return nil, err
+		}
+		tag, err := s.LoadUint(32)
+		if err != nil {
+			return nil, err
+		}
+		if tag != actionSendMsg {
+			return nil, fmt.Errorf("unsupported action %08x", tag)
+		}
+		mode, err := s.LoadUint(8)
+		if err != nil {
+			return nil, err
+		}
+		message, err := s.LoadRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.EndParse(); err != nil {
+			return nil, err
+		}
+
+		messages = append([]OutMessage{{mode: uint8(mode), message: message}}, messages...)
+		list = prev
+	}
+	return messages, nil
+}
@@ -0,0 +1,122 @@
+package ton
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testWalletID = 698983191
+
+// walletV3Body builds a wallet v3 body, or a wallet v4 simple send body with the op
+func walletV3Body(v4 bool, mode uint64, messages ...*Cell) *Cell {
+	b := newBuilder().storeUint(testWalletID, 32).storeUint(1700000000, 32).storeUint(7, 32)
+	if v4 {
+		b.storeUint(walletV4OpSend, 8)
+	}
+	for _, msg := range messages {
+		b.storeUint(mode, 8).storeRef(msg)
+	}
+	return b.endCell()
+}
+
+// walletV5Body builds a wallet v5 body sending the messages by the out actions list
+func walletV5Body(mode uint64, messages ...*Cell) *Cell {
+	b := newBuilder().storeUint(walletV5ExternalSigned, 32).storeUint(2147483409, 32).storeUint(1700000000, 32).
+		storeUint(7, 32)
+	if len(messages) == 0 {
+		return b.storeBit(false).storeBit(false).endCell()
+	}
+
+	list := newBuilder().endCell()
+	for _, msg := range messages {
+		list = newBuilder().storeRef(list).storeUint(actionSendMsg, 32).storeUint(mode, 8).storeRef(msg).endCell()
+	}
+	return b.storeBit(true).storeRef(list).storeBit(false).endCell()
+}
+
+func TestParseWalletBody(t *testing.T) {
+	first := internalMessage(testAddress(1), true, 100, nil)
+	second := internalMessage(testAddress(2), true, 200, nil)
+
+	tests := []struct {
+		name        string
+		body        *Cell
+		wantVersion string
+		wantError   bool
+	}{
+		{
+			name:        "Wallet v3",
+			body:        walletV3Body(false, 3, first, second),
+			wantVersion: WalletV3,
+		},
+		{
+			name:        "Wallet v4",
+			body:        walletV3Body(true, 3, first, second),
+			wantVersion: WalletV4,
+		},
+		{
+			name:        "Wallet v5",
+			body:        walletV5Body(3, first, second),
+			wantVersion: WalletV5,
+		},
+		{
+			name:      "Wallet v4 plugin op",
+			body:      newBuilder().storeUint(testWalletID, 32).storeUint(0, 64).storeUint(2, 8).storeUint(3, 8).storeRef(first).endCell(),
+			wantError: true,
+		},
+		{
+			name: "Wallet v5 extended actions",
+			body: newBuilder().storeUint(walletV5ExternalSigned, 32).storeUint(0, 96).storeBit(false).storeBit(true).
+				endCell(),
+			wantError: true,
+		},
+		{
+			name: "Wallet v5 set code action",
+			body: newBuilder().storeUint(walletV5ExternalSigned, 32).storeUint(0, 96).storeBit(true).storeBit(false).
+				storeRef(newBuilder().storeRef(newBuilder().endCell()).storeUint(0xad4de08e, 32).storeRef(first).endCell()).
+				endCell(),
+			wantError: true,
+		},
+		{
+			name:      "Wallet v5 internal signed op",
+			body:      newBuilder().storeUint(0x73696e74, 32).storeUint(0, 96).storeBit(false).storeBit(false).endCell(),
+			wantError: true,
+		},
+		{
+			name:      "Send all balance",
+			body:      walletV3Body(false, 128, first),
+			wantError: true,
+		},
+		{
+			name:      "Carry inbound value",
+			body:      walletV5Body(64, first),
+			wantError: true,
+		},
+		{
+			name:      "No message",
+			body:      walletV5Body(3),
+			wantError: true,
+		},
+		{
+			name:      "Unknown body",
+			body:      newBuilder().storeUint(0, 100).endCell(),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := ParseWalletBody(tt.body)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, body.version)
+			assert.Equal(t, uint32(7), body.seqno)
+			// messages are in the order they are sent
+			assert.Equal(t, []OutMessage{{mode: 3, message: first}, {mode: 3, message: second}}, body.messages)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tron
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+)
+
+// Config bounds the Tron transactions and declares the TRC10 tokens registered at startup
+type Config struct {
+	// MaxFeeLimit is the max fee_limit of a transaction in sun, not checked if zero
+	MaxFeeLimit int64 `mapstructure:"max_fee_limit"`
+	// MaxExpiration is the max time from now to the expiration of a transaction, not checked if zero
+	MaxExpiration time.Duration `mapstructure:"max_expiration"`
+	// Trc10Tokens are verified by the tron adapter in addition to the built-in TRON and TRON_USDT
+	Trc10Tokens []Trc10TokenConfig `mapstructure:"trc10_tokens"`
+}
+
+// Trc10TokenConfig declares a TRC10 token
+type Trc10TokenConfig struct {
+	TokenID string `mapstructure:"token_id"`
+	// AssetID is the TRC10 token id carried by the transfer asset contract, e.g. 1002000
+	AssetID  string `mapstructure:"asset_id"`
+	Decimals int32  `mapstructure:"decimals"`
+}
+
+var (
+	boundsLock sync.RWMutex
+	// bounds are the fee limit and expiration bounds of every Tron transaction
+	bounds Config
+)
+
+// SetBounds sets the fee limit and expiration bounds of the Tron transactions
+func SetBounds(cfg Config) error {
+	if cfg.MaxFeeLimit < 0 {
+		return fmt.Errorf("tron max fee limit is negative")
+	}
+	if cfg.MaxExpiration < 0 {
+		return fmt.Errorf("tron max expiration is negative")
+	}
+
+	boundsLock.Lock()
+	defer boundsLock.Unlock()
+
+	bounds = Config{MaxFeeLimit: cfg.MaxFeeLimit, MaxExpiration: cfg.MaxExpiration}
+	return nil
+}
+
+// ValidateTrc10Token checks the declaration of a TRC10 token
+func ValidateTrc10Token(cfg Trc10TokenConfig) error {
+	if strings.TrimSpace(cfg.TokenID) == "" {
+		return fmt.Errorf("trc10 token id is empty")
+	}
+	if cfg.AssetID == "" || strings.Trim(cfg.AssetID, "0123456789") != "" {
+		return fmt.Errorf("invalid asset id %q of trc10 token %v", cfg.AssetID, cfg.TokenID)
+	}
+	if cfg.Decimals < 0 {
+		return fmt.Errorf("decimals of trc10 token %v is negative", cfg.TokenID)
+	}
+	return nil
+}
+
+// checkBounds rejects a transaction whose fee limit or expiration exceeds the bounds, or which is expired
+func checkBounds(tx *core.TransactionRaw, now time.Time) error {
+	boundsLock.RLock()
+	maxFeeLimit, maxExpiration := bounds.MaxFeeLimit, bounds.MaxExpiration
+	boundsLock.RUnlock()
+
+	if maxFeeLimit > 0 && tx.GetFeeLimit() > maxFeeLimit {
+		return fmt.Errorf("fee limit %v exceeds max fee limit %v", tx.GetFeeLimit(), maxFeeLimit)
+	}
+	if maxExpiration > 0 {
+		// expiration is in milliseconds
+		expiration := time.UnixMilli(tx.GetExpiration())
+		if !expiration.After(now) {
+			return fmt.Errorf("transaction expired at %v", expiration.UTC())
+		}
+		if expiration.Sub(now) > maxExpiration {
+			return fmt.Errorf("expiration %v is more than %v from now", expiration.UTC(), maxExpiration)
+		}
+	}
+	return nil
+}
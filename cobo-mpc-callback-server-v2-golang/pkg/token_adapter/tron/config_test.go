@@ -0,0 +1,64 @@
+package tron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckBounds(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	assert.NoError(t, SetBounds(Config{MaxFeeLimit: 100_000_000, MaxExpiration: time.Hour}))
+	defer func() { assert.NoError(t, SetBounds(Config{})) }()
+
+	tests := []struct {
+		name      string
+		tx        *core.TransactionRaw
+		wantError bool
+	}{
+		{
+			name: "Within bounds",
+			tx:   &core.TransactionRaw{FeeLimit: 100_000_000, Expiration: now.Add(time.Minute).UnixMilli()},
+		},
+		{
+			name:      "Fee limit exceeded",
+			tx:        &core.TransactionRaw{FeeLimit: 100_000_001, Expiration: now.Add(time.Minute).UnixMilli()},
+			wantError: true,
+		},
+		{
+			name:      "Expired",
+			tx:        &core.TransactionRaw{Expiration: now.UnixMilli()},
+			wantError: true,
+		},
+		{
+			name:      "Expiration too far",
+			tx:        &core.TransactionRaw{Expiration: now.Add(2 * time.Hour).UnixMilli()},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBounds(tt.tx, now)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetBounds(t *testing.T) {
+	assert.Error(t, SetBounds(Config{MaxFeeLimit: -1}))
+	assert.Error(t, SetBounds(Config{MaxExpiration: -time.Second}))
+}
+
+func TestValidateTrc10Token(t *testing.T) {
+	assert.NoError(t, ValidateTrc10Token(Trc10TokenConfig{TokenID: "TRON_BTT", AssetID: "1002000", Decimals: 6}))
+	assert.Error(t, ValidateTrc10Token(Trc10TokenConfig{AssetID: "1002000"}))
+	assert.Error(t, ValidateTrc10Token(Trc10TokenConfig{TokenID: "TRON_BTT", AssetID: "BTT"}))
+	assert.Error(t, ValidateTrc10Token(Trc10TokenConfig{TokenID: "TRON_BTT", AssetID: "1002000", Decimals: -1}))
+}
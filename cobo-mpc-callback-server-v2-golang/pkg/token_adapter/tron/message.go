@@ -0,0 +1,64 @@
+package tron
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// messagePrefix is the TRON signed message prefix of signMessageV2
+const messagePrefix = "\x19TRON Signed Message:\n"
+
+// Message is a message signed by the source address with signMessageV2
+type Message struct {
+	token   *Token
+	message []byte
+}
+
+// MessageHash returns the signMessageV2 digest, the keccak256 of the prefixed message
+func MessageHash(message []byte) common.Hash {
+	return crypto.Keccak256Hash([]byte(messagePrefix+strconv.Itoa(len(message))), message)
+}
+
+// GetHashes implements Transaction interface for a Tron message
+func (m *Message) GetHashes() ([]string, error) {
+	return []string{MessageHash(m.message).String()}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for a Tron message
+func (m *Message) GetDestinationAddresses() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetTransfers implements Transaction interface for a Tron message
+func (m *Message) GetTransfers() ([]token_adapter.Transfer, error) {
+	return []token_adapter.Transfer{}, nil
+}
+
+// GetMessage implements MessageTransaction interface for a Tron message
+func (m *Message) GetMessage() (*token_adapter.Message, error) {
+	return &token_adapter.Message{
+		Standard: token_adapter.MessageStandardTronMessageV2,
+		Content:  token_adapter.MessageContent(m.message),
+	}, nil
+}
+
+// buildMessage builds the message of a message sign request, nil for another request
+func (t *Token) buildMessage(txInfo *token_adapter.TransactionInfo) (*Message, error) {
+	if txInfo == nil || txInfo.Transaction == nil || txInfo.Transaction.Destination.TransactionMessageSignEIP191Destination == nil {
+		return nil, nil //nolint:nilnil
+	}
+	if t.trc20Token {
+		return nil, fmt.Errorf("message is not supported by trc20 token %v", t.tokenID)
+	}
+
+	message, err := base64.StdEncoding.DecodeString(txInfo.Transaction.Destination.TransactionMessageSignEIP191Destination.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+	return &Message{token: t, message: message}, nil
+}
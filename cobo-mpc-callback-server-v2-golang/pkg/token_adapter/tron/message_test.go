@@ -0,0 +1,71 @@
+package tron
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken_BuildTransaction_Message(t *testing.T) {
+	message := []byte("hello world")
+	wantHash := crypto.Keccak256Hash([]byte("\x19TRON Signed Message:\n11hello world"))
+
+	tests := []struct {
+		name      string
+		token     *Token
+		message   string
+		wantError bool
+	}{
+		{
+			name:    "Message",
+			token:   &Token{tokenID: "TRON"},
+			message: base64.StdEncoding.EncodeToString(message),
+		},
+		{
+			name:      "Message not in base64",
+			token:     &Token{tokenID: "TRON"},
+			message:   "not base64!",
+			wantError: true,
+		},
+		{
+			name:      "TRC20 token",
+			token:     &Token{tokenID: "TRON_USDT", trc20Token: true},
+			message:   base64.StdEncoding.EncodeToString(message),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := tt.token.BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					Destination: coboWaaS2.TransactionMessageSignEIP191DestinationAsTransactionDestination(
+						&coboWaaS2.TransactionMessageSignEIP191Destination{Message: tt.message},
+					),
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{wantHash.String()}, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Empty(t, transfers)
+
+			msg, err := tx.(token_adapter.MessageTransaction).GetMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Message{Standard: token_adapter.MessageStandardTronMessageV2, Content: "hello world"}, msg)
+		})
+	}
+}
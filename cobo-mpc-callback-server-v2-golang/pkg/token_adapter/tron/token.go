@@ -2,6 +2,7 @@ package tron
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/ethereum/go-ethereum/common"
@@ -10,6 +11,9 @@ import (
 type Token struct {
 	tokenID    string
 	trc20Token bool
+	trc10Token bool
+	// contractAddress is the expected TRC20 contract or TRC10 token id, the contract registered for the token id when empty
+	contractAddress string
 }
 
 func NewToken(tokenID string) token_adapter.Token {
@@ -26,7 +30,22 @@ func NewTrc20Token(tokenID string) token_adapter.Token {
 	}
 }
 
+func NewTrc10Token(tokenID string) token_adapter.Token {
+	return &Token{
+		tokenID:    tokenID,
+		trc10Token: true,
+	}
+}
+
 func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_adapter.Transaction, error) {
+	message, err := t.buildMessage(txInfo)
+	if err != nil {
+		return nil, fmt.Errorf("prepare message error: %w", err)
+	}
+	if message != nil {
+		return message, nil
+	}
+
 	preTxData, err := prepareBuildTransactionData(txInfo)
 	if err != nil {
 		return nil, fmt.Errorf("prepare build transaction data error: %w", err)
@@ -36,6 +55,9 @@ func (t *Token) BuildTransaction(txInfo *token_adapter.TransactionInfo) (token_a
 	if err != nil {
 		return nil, fmt.Errorf("prepare tron transaction error: %w", err)
 	}
+	if err := checkBounds(tx, time.Now()); err != nil {
+		return nil, fmt.Errorf("check tron transaction bounds error: %w", err)
+	}
 
 	return &Transaction{tx: tx, PrepareTransactionData: preTxData, token: t}, nil
 }
@@ -48,5 +70,20 @@ func prepareBuildTransactionData(txInfo *token_adapter.TransactionInfo) (data *P
 	rawTx := *txInfo.Transaction.RawTxInfo.UnsignedRawTx
 	rawTxBytes := common.FromHex(rawTx)
 
-	return &PrepareTransactionData{rawTx: rawTxBytes}, nil
+	return &PrepareTransactionData{rawTx: rawTxBytes, chainID: txInfo.Transaction.GetChainId()}, nil
+}
+
+// checkContract rejects a TRC20 contract or a TRC10 token id other than the contract bound to the token
+func (t *Token) checkContract(contractAddress string) error {
+	expected := t.contractAddress
+	if expected == "" {
+		var ok bool
+		if expected, ok = token_adapter.GetTokenContract(t.tokenID); !ok {
+			return fmt.Errorf("contract of token %v is not registered", t.tokenID)
+		}
+	}
+	if contractAddress != expected {
+		return fmt.Errorf("transaction contract %v mismatch contract %v of token %v", contractAddress, expected, t.tokenID)
+	}
+	return nil
 }
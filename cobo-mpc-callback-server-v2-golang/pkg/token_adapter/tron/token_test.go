@@ -8,6 +8,9 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// usdtContractAddress is the contract of the TRC20 transfer example
+const usdtContractAddress = "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+
 // Sample transaction raw data, in real tests, replace with valid Tron transaction data
 var (
 	// TRON transfer transaction example
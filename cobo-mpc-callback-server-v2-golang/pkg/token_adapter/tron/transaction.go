@@ -5,13 +5,19 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"math/big"
 	"sync"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/erc20"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/fbsobreira/gotron-sdk/pkg/address"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	"google.golang.org/protobuf/proto"
 )
 
+const defaultChainID = "TRON"
+
 // hashPool holds sha256 instances for transaction hashing
 var hashPool = sync.Pool{
 	New: func() interface{} { return sha256.New() },
@@ -26,7 +32,8 @@ type Transaction struct {
 
 // PrepareTransactionData contains the raw transaction data
 type PrepareTransactionData struct {
-	rawTx []byte
+	rawTx   []byte
+	chainID string
 }
 
 // GetHashes implements Transaction interface for Tron
@@ -51,65 +58,176 @@ func (t *Transaction) GetHashes() ([]string, error) {
 
 // GetDestinationAddresses implements Transaction interface for Tron
 func (t *Transaction) GetDestinationAddresses() ([]string, error) {
-	if t.tx == nil {
-		return nil, fmt.Errorf("transaction raw data is nil")
+	_, destinations, err := t.decodeContract()
+	if err != nil {
+		return nil, err
+	}
+
+	return destinations, nil
+}
+
+// GetTransfers implements Transaction interface for Tron
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	transfers, _, err := t.decodeContract()
+	if err != nil {
+		return nil, err
 	}
 
-	var addresses []string
+	return transfers, nil
+}
+
+// decodeContract decodes the only contract of the transaction into its transfers and destinations.
+// A transfer, a TRC-10 transfer and a resource delegation go to their receiver, a vote to the voted witnesses,
+// and a freeze or unfreeze of the owner balance has no destination.
+func (t *Transaction) decodeContract() ([]token_adapter.Transfer, []string, error) {
+	if t.tx == nil {
+		return nil, nil, fmt.Errorf("transaction raw data is nil")
+	}
 
 	// Get transaction contract
 	if len(t.tx.GetContract()) == 0 {
-		return nil, fmt.Errorf("transaction contract is empty")
+		return nil, nil, fmt.Errorf("transaction contract is empty")
+	}
+	if len(t.tx.GetContract()) > 1 {
+		return nil, nil, fmt.Errorf("transaction has %d contracts, only one is supported", len(t.tx.GetContract()))
 	}
 
 	contract := t.tx.GetContract()[0]
 	contractType := contract.GetType()
 
-	if t.token.trc20Token {
+	transfer := token_adapter.Transfer{
+		Chain: t.chainID,
+		Memo:  string(t.tx.GetData()),
+	}
+	if transfer.Chain == "" {
+		transfer.Chain = defaultChainID
+	}
+
+	switch {
+	case t.token.trc20Token:
 		// TRC20 transfer
 		if contractType != core.Transaction_Contract_TriggerSmartContract {
-			return nil, fmt.Errorf("not a TRC20 transfer contract")
+			return nil, nil, fmt.Errorf("not a TRC20 transfer contract")
 		}
 
 		parameter := new(core.TriggerSmartContract)
 		if err := proto.Unmarshal(contract.GetParameter().GetValue(), parameter); err != nil {
-			return nil, fmt.Errorf("unmarshal trigger smart contract error: %w", err)
+			return nil, nil, fmt.Errorf("unmarshal trigger smart contract error: %w", err)
 		}
 
-		// Decode TRC20 transfer data to get the recipient address
-		data := parameter.GetData()
-		if len(data) < 4+32 { // method(4) + address(32)
-			return nil, fmt.Errorf("invalid TRC20 transfer data length")
+		contractAddress := address.Address(parameter.GetContractAddress()).String()
+		if err := t.token.checkContract(contractAddress); err != nil {
+			return nil, nil, err
 		}
 
-		// Check if it's a transfer function (method ID: a9059cbb)
-		methodID := hex.EncodeToString(data[:4])
-		if methodID != "a9059cbb" {
-			return nil, fmt.Errorf("not a TRC20 transfer method")
+		call, err := erc20.DecodeCall(parameter.GetData())
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode TRC20 call error: %w", err)
+		}
+		transfer.Asset = contractAddress
+		transfer.From = address.Address(parameter.GetOwnerAddress()).String()
+		if call.Owner != nil {
+			transfer.From = tronAddress(*call.Owner)
+		}
+		transfer.To = tronAddress(call.To)
+		transfer.Amount = call.Amount
+		transfer.Method = call.Method
+		transfer.UnlimitedApproval = call.IsUnlimitedApproval()
+	case t.token.trc10Token:
+		// TRC10 transfer
+		if contractType != core.Transaction_Contract_TransferAssetContract {
+			return nil, nil, fmt.Errorf("not a TRC10 transfer contract")
 		}
 
-		// Extract address (note: Tron addresses need special handling)
-		addressBytes := data[16:36]
-		// Convert to Tron address format
-		addressBytes = append([]byte{0x41}, addressBytes...)
-
-		tronAddress := address.Address(addressBytes)
-		addresses = append(addresses, tronAddress.String())
-	} else {
-		// Native TRON transfer
-		if contractType != core.Transaction_Contract_TransferContract {
-			return nil, fmt.Errorf("not a TRX transfer contract")
+		parameter := new(core.TransferAssetContract)
+		if err := proto.Unmarshal(contract.GetParameter().GetValue(), parameter); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal transfer asset contract error: %w", err)
 		}
 
+		assetID := string(parameter.GetAssetName())
+		if err := t.token.checkContract(assetID); err != nil {
+			return nil, nil, err
+		}
+		transfer.Asset = assetID
+		transfer.From = address.Address(parameter.GetOwnerAddress()).String()
+		transfer.To = address.Address(parameter.GetToAddress()).String()
+		transfer.Amount = big.NewInt(parameter.GetAmount())
+	default:
+		return t.decodeNativeContract(contract, transfer)
+	}
+
+	return []token_adapter.Transfer{transfer}, []string{transfer.To}, nil
+}
+
+// decodeNativeContract decodes a TRX transfer, a resource staking or a vote contract
+func (t *Transaction) decodeNativeContract(
+	contract *core.Transaction_Contract,
+	transfer token_adapter.Transfer,
+) ([]token_adapter.Transfer, []string, error) {
+	value := contract.GetParameter().GetValue()
+
+	switch contract.GetType() {
+	case core.Transaction_Contract_TransferContract:
 		parameter := new(core.TransferContract)
-		if err := proto.Unmarshal(contract.GetParameter().GetValue(), parameter); err != nil {
-			return nil, fmt.Errorf("unmarshal transfer contract error: %w", err)
+		if err := proto.Unmarshal(value, parameter); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal transfer contract error: %w", err)
+		}
+		transfer.From = address.Address(parameter.GetOwnerAddress()).String()
+		transfer.To = address.Address(parameter.GetToAddress()).String()
+		transfer.Amount = big.NewInt(parameter.GetAmount())
+		return []token_adapter.Transfer{transfer}, []string{transfer.To}, nil
+	case core.Transaction_Contract_FreezeBalanceV2Contract:
+		parameter := new(core.FreezeBalanceV2Contract)
+		if err := proto.Unmarshal(value, parameter); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal freeze balance v2 contract error: %w", err)
+		}
+		// the frozen balance stays with the owner
+		return []token_adapter.Transfer{}, []string{}, nil
+	case core.Transaction_Contract_UnfreezeBalanceV2Contract:
+		parameter := new(core.UnfreezeBalanceV2Contract)
+		if err := proto.Unmarshal(value, parameter); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal unfreeze balance v2 contract error: %w", err)
+		}
+		return []token_adapter.Transfer{}, []string{}, nil
+	case core.Transaction_Contract_DelegateResourceContract:
+		parameter := new(core.DelegateResourceContract)
+		if err := proto.Unmarshal(value, parameter); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal delegate resource contract error: %w", err)
 		}
-		tronAddress := address.Address(parameter.GetToAddress())
-		addresses = append(addresses, tronAddress.String())
+		// the receiver uses the resources of the staked balance
+		transfer.From = address.Address(parameter.GetOwnerAddress()).String()
+		transfer.To = address.Address(parameter.GetReceiverAddress()).String()
+		transfer.Amount = big.NewInt(parameter.GetBalance())
+		transfer.Method = "delegateResource"
+		return []token_adapter.Transfer{transfer}, []string{transfer.To}, nil
+	case core.Transaction_Contract_VoteWitnessContract:
+		parameter := new(core.VoteWitnessContract)
+		if err := proto.Unmarshal(value, parameter); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal vote witness contract error: %w", err)
+		}
+		// votes move no value, the voted witnesses are the destinations
+		destinations := make([]string, 0, len(parameter.GetVotes()))
+		for _, vote := range parameter.GetVotes() {
+			destinations = append(destinations, address.Address(vote.GetVoteAddress()).String())
+		}
+		return []token_adapter.Transfer{}, destinations, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported TRX contract %v", contract.GetType())
 	}
+}
+
+// GetFee implements FeeTransaction interface for Tron
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction raw data is nil")
+	}
+
+	return &token_adapter.Fee{FeeLimit: big.NewInt(t.tx.GetFeeLimit())}, nil
+}
 
-	return addresses, nil
+// tronAddress converts a 20 bytes ABI address to the Tron base58 address
+func tronAddress(addr common.Address) string {
+	return address.Address(append([]byte{address.TronBytePrefix}, addr.Bytes()...)).String()
 }
 
 // ParseTronTransaction parses a raw transaction bytes into a Tron TransactionRaw
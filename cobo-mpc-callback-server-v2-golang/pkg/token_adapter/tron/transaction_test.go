@@ -1,10 +1,16 @@
 package tron
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 func Test_Transaction_GetHashes(t *testing.T) {
@@ -88,7 +94,7 @@ func TestTransaction_GetDestinationAddresses_TRC20(t *testing.T) {
 		PrepareTransactionData: &PrepareTransactionData{
 			rawTx: rawTxBytes,
 		},
-		token: &Token{tokenID: "TRON_USDT", trc20Token: true},
+		token: &Token{tokenID: "TRON_USDT", trc20Token: true, contractAddress: usdtContractAddress},
 	}
 
 	// Test GetDestinationAddresses method
@@ -110,7 +116,7 @@ func TestTransaction_GetDestinationAddresses_InvalidContract(t *testing.T) {
 		PrepareTransactionData: &PrepareTransactionData{
 			rawTx: rawTxBytes,
 		},
-		token: &Token{tokenID: "TRX_USDT", trc20Token: true},
+		token: &Token{tokenID: "TRX_USDT", trc20Token: true, contractAddress: usdtContractAddress},
 	}
 
 	// Expect error since TRON transaction is not TriggerSmartContract type
@@ -172,3 +178,203 @@ func TestParseTronTransaction(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, tx)
 }
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		isTrc20       bool
+		contract      string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:    "TRX transfer",
+			rawTx:   tronRawTx,
+			isTrc20: false,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "TRON",
+				From:   "TVpZV9L9v3HzcUiXkws2DWbiAomFQhXSzU",
+				To:     "TEDv9wo5epcVi7pW3rEZUa7wbtPuAKZCer",
+				Amount: big.NewInt(10000000),
+			}},
+		},
+		{
+			name:     "TRC20 transfer",
+			rawTx:    trc20RawTx,
+			isTrc20:  true,
+			contract: usdtContractAddress,
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "TRON",
+				Asset:  "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t",
+				From:   "TVpZV9L9v3HzcUiXkws2DWbiAomFQhXSzU",
+				To:     "THKAcY3fvSyfkzbYxj2aAgxC5R6YAPMJqa",
+				Amount: big.NewInt(10000000),
+				Method: "transfer",
+			}},
+		},
+		{
+			name:      "TRC20 transfer of another contract",
+			rawTx:     trc20RawTx,
+			isTrc20:   true,
+			contract:  "TEkxiTehnzSmSe2XqrBj4w32RUN966rdz8",
+			wantError: true,
+		},
+		{
+			name:      "TRC20 transfer without contract",
+			rawTx:     trc20RawTx,
+			isTrc20:   true,
+			wantError: true,
+		},
+		{
+			name:      "TRX transfer as TRC20",
+			rawTx:     tronRawTx,
+			isTrc20:   true,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTxBytes := common.FromHex(tt.rawTx)
+			tx, err := ParseTronTransaction(rawTxBytes)
+			assert.NoError(t, err)
+
+			tronTx := &Transaction{
+				tx: tx,
+				PrepareTransactionData: &PrepareTransactionData{
+					rawTx: rawTxBytes,
+				},
+				token: &Token{tokenID: "TEST", trc20Token: tt.isTrc20, contractAddress: tt.contract},
+			}
+
+			transfers, err := tronTx.GetTransfers()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTransfers, transfers)
+			}
+		})
+	}
+}
+
+func newContract(t *testing.T, contractType core.Transaction_Contract_ContractType, parameter proto.Message) *core.Transaction_Contract {
+	value, err := anypb.New(parameter)
+	assert.NoError(t, err)
+	return &core.Transaction_Contract{Type: contractType, Parameter: value}
+}
+
+func TestTransaction_GetTransfers_Contracts(t *testing.T) {
+	owner, err := address.Base58ToAddress("TVpZV9L9v3HzcUiXkws2DWbiAomFQhXSzU")
+	assert.NoError(t, err)
+	receiver, err := address.Base58ToAddress("TEDv9wo5epcVi7pW3rEZUa7wbtPuAKZCer")
+	assert.NoError(t, err)
+	witness, err := address.Base58ToAddress("THKAcY3fvSyfkzbYxj2aAgxC5R6YAPMJqa")
+	assert.NoError(t, err)
+
+	transferTRX := newContract(t, core.Transaction_Contract_TransferContract,
+		&core.TransferContract{OwnerAddress: owner, ToAddress: receiver, Amount: 5})
+	transferAsset := newContract(t, core.Transaction_Contract_TransferAssetContract,
+		&core.TransferAssetContract{AssetName: []byte("1002000"), OwnerAddress: owner, ToAddress: receiver, Amount: 7})
+
+	tests := []struct {
+		name             string
+		token            *Token
+		contracts        []*core.Transaction_Contract
+		wantTransfers    []token_adapter.Transfer
+		wantDestinations []string
+		wantError        bool
+	}{
+		{
+			name:      "TRC10 transfer",
+			token:     &Token{tokenID: "TRON_BTT", trc10Token: true, contractAddress: "1002000"},
+			contracts: []*core.Transaction_Contract{transferAsset},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "TRON", Asset: "1002000", From: owner.String(), To: receiver.String(), Amount: big.NewInt(7)},
+			},
+			wantDestinations: []string{receiver.String()},
+		},
+		{
+			name:      "TRC10 transfer of another token",
+			token:     &Token{tokenID: "TRON_BTT", trc10Token: true, contractAddress: "1000001"},
+			contracts: []*core.Transaction_Contract{transferAsset},
+			wantError: true,
+		},
+		{
+			name:      "TRC10 transfer as TRX",
+			token:     &Token{tokenID: "TRON"},
+			contracts: []*core.Transaction_Contract{transferAsset},
+			wantError: true,
+		},
+		{
+			name:  "Freeze balance",
+			token: &Token{tokenID: "TRON"},
+			contracts: []*core.Transaction_Contract{newContract(t, core.Transaction_Contract_FreezeBalanceV2Contract,
+				&core.FreezeBalanceV2Contract{OwnerAddress: owner, FrozenBalance: 100, Resource: core.ResourceCode_ENERGY})},
+			wantTransfers:    []token_adapter.Transfer{},
+			wantDestinations: []string{},
+		},
+		{
+			name:  "Unfreeze balance",
+			token: &Token{tokenID: "TRON"},
+			contracts: []*core.Transaction_Contract{newContract(t, core.Transaction_Contract_UnfreezeBalanceV2Contract,
+				&core.UnfreezeBalanceV2Contract{OwnerAddress: owner, UnfreezeBalance: 100})},
+			wantTransfers:    []token_adapter.Transfer{},
+			wantDestinations: []string{},
+		},
+		{
+			name:  "Delegate resource",
+			token: &Token{tokenID: "TRON"},
+			contracts: []*core.Transaction_Contract{newContract(t, core.Transaction_Contract_DelegateResourceContract,
+				&core.DelegateResourceContract{OwnerAddress: owner, Balance: 100, ReceiverAddress: receiver})},
+			wantTransfers: []token_adapter.Transfer{
+				{Chain: "TRON", From: owner.String(), To: receiver.String(), Amount: big.NewInt(100), Method: "delegateResource"},
+			},
+			wantDestinations: []string{receiver.String()},
+		},
+		{
+			name:  "Vote witness",
+			token: &Token{tokenID: "TRON"},
+			contracts: []*core.Transaction_Contract{newContract(t, core.Transaction_Contract_VoteWitnessContract,
+				&core.VoteWitnessContract{OwnerAddress: owner, Votes: []*core.VoteWitnessContract_Vote{{VoteAddress: witness, VoteCount: 10}}})},
+			wantTransfers:    []token_adapter.Transfer{},
+			wantDestinations: []string{witness.String()},
+		},
+		{
+			name:  "Unsupported contract",
+			token: &Token{tokenID: "TRON"},
+			contracts: []*core.Transaction_Contract{newContract(t, core.Transaction_Contract_WithdrawBalanceContract,
+				&core.WithdrawBalanceContract{OwnerAddress: owner})},
+			wantError: true,
+		},
+		{
+			name:      "Multiple contracts",
+			token:     &Token{tokenID: "TRON"},
+			contracts: []*core.Transaction_Contract{transferTRX, transferTRX},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tronTx := &Transaction{
+				tx:                     &core.TransactionRaw{Contract: tt.contracts},
+				PrepareTransactionData: &PrepareTransactionData{},
+				token:                  tt.token,
+			}
+
+			transfers, err := tronTx.GetTransfers()
+			destinations, destinationErr := tronTx.GetDestinationAddresses()
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Error(t, destinationErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, destinationErr)
+			assert.Equal(t, tt.wantTransfers, transfers)
+			assert.Equal(t, tt.wantDestinations, destinations)
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package token_adapter
 
-import coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+import (
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter/erc20"
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/utils"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+)
 
 // Transaction represents a generic blockchain transaction
 type Transaction interface {
@@ -9,6 +17,125 @@ type Transaction interface {
 
 	// GetDestinationAddresses returns a list of destination addresses
 	GetDestinationAddresses() ([]string, error)
+
+	// GetTransfers returns the transfers decoded from the transaction
+	GetTransfers() ([]Transfer, error)
+}
+
+// Transfer is the canonical view of a value movement in a transaction
+type Transfer struct {
+	// Chain is the chain id of the transaction, e.g. ETH, TRON, SOL
+	Chain string
+	// Asset is the token contract or mint address, empty for the native asset
+	Asset string
+	From  string
+	To    string
+	// Amount in the asset base unit, the allowance for an approval
+	Amount *big.Int
+	Memo   string
+	// Tag is the destination tag identifying the recipient at the destination address, e.g. the XRP DestinationTag,
+	// empty if none
+	Tag string
+	// Method is the decoded token method, e.g. transfer or approve, empty for a native transfer.
+	// To is the spender for an approval
+	Method string
+	// UnlimitedApproval flags an approval of the max allowance
+	UnlimitedApproval bool
+}
+
+// SourceTransaction is implemented by UTXO transactions, whose senders are the owners of the spent outputs
+type SourceTransaction interface {
+	// GetSourceAddresses returns the address of the output spent by every input
+	GetSourceAddresses() ([]string, error)
+}
+
+// CallTransaction is implemented by transactions of an account calling an address, whose transfers may be unwrapped
+// from the call, e.g. the calls executed by a Safe
+type CallTransaction interface {
+	// GetCall returns the call as a transfer to the called address of the native value sent, nil for a contract
+	// creation
+	GetCall() (*Transfer, error)
+}
+
+// FeeTransaction is implemented by transactions whose fee settings can be decoded
+type FeeTransaction interface {
+	// GetFee returns the fee settings of the transaction
+	GetFee() (*Fee, error)
+}
+
+// Fee is the fee settings of a transaction, nil fields are not applicable
+type Fee struct {
+	// GasLimit is the maximum gas units
+	GasLimit *big.Int
+	// GasPrice is the legacy gas price or the EIP-1559 max fee per gas, in wei
+	GasPrice *big.Int
+	// GasTipCap is the EIP-1559 max priority fee per gas, in wei
+	GasTipCap *big.Int
+	// FeeLimit is the maximum fee paid, in the native token base unit
+	FeeLimit *big.Int
+}
+
+// ContractCallTransaction is implemented by transactions whose contract call can be decoded
+type ContractCallTransaction interface {
+	// GetContractCall returns the decoded contract call, nil if the called contract is unknown
+	GetContractCall() (*ContractCall, error)
+}
+
+// ContractCall is a contract call decoded with the contract ABI
+type ContractCall struct {
+	Contract string
+	// Method is the ABI method name, e.g. deposit
+	Method string
+	Args   []ContractCallArg
+	// Value is the native amount sent with the call, in the base unit
+	Value *big.Int
+}
+
+// ContractCallArg is a typed argument of a contract call
+type ContractCallArg struct {
+	Name string
+	// Type is the ABI type, e.g. address, uint256
+	Type  string
+	Value interface{}
+}
+
+// MessageTransaction is implemented by message sign requests. Such a request carries a message instead of a raw
+// transaction, so BuildTransaction returns the message when the request has one.
+type MessageTransaction interface {
+	// GetMessage returns the signed message
+	GetMessage() (*Message, error)
+}
+
+// Message standards
+const (
+	MessageStandardEIP191         = "EIP191"
+	MessageStandardEIP712         = "EIP712"
+	MessageStandardSolanaOffchain = "SolanaOffchain"
+	MessageStandardTronMessageV2  = "TronMessageV2"
+	MessageStandardBIP137         = "BIP137"
+	MessageStandardBIP322         = "BIP322"
+)
+
+// Message is a message signed instead of a transaction
+type Message struct {
+	// Standard is the message standard, e.g. EIP191, EIP712
+	Standard string
+	// PrimaryType is the EIP-712 primary type, e.g. Permit, empty for another standard
+	PrimaryType string
+	// Domain is the EIP-712 domain, nil for another standard
+	Domain *TypedDataDomain
+	// Content is the text of the message, empty if the message is not UTF-8 text
+	Content string
+	// Decoded is set when the transfers granted by EIP-712 typed data are decoded, e.g. a Permit or a SafeTx.
+	// Other typed data has no transfers to check, and is only signed when allow-listed by a policy rule.
+	Decoded bool
+}
+
+// TypedDataDomain is the EIP-712 domain of a message, empty fields are not part of the domain
+type TypedDataDomain struct {
+	Name              string
+	ChainID           *big.Int
+	VerifyingContract string
 }
 
 // Token represents a specific blockchain implementation
@@ -21,3 +148,45 @@ type TransactionInfo struct {
 	SourceAddresses []coboWaaS2.AddressInfo
 	Transaction     *coboWaaS2.Transaction
 }
+
+// TotalAmount sums the amount moved by the transfers of the asset, the token contract or mint address registered
+// for the token or empty for the native asset. Transfers of another asset and approvals are not counted.
+func TotalAmount(transfers []Transfer, asset string) *big.Int {
+	total := new(big.Int)
+	for _, transfer := range transfers {
+		if transfer.Amount != nil && transfer.Moves(asset) {
+			total.Add(total, transfer.Amount)
+		}
+	}
+	return total
+}
+
+// Moves reports whether the transfer moves the asset, empty for the native asset.
+// An approval only grants an allowance, so it moves nothing.
+func (t Transfer) Moves(asset string) bool {
+	if erc20.IsApprovalMethod(t.Method) {
+		return false
+	}
+	// EVM contract addresses are case-insensitive hex, the other assets are case-sensitive
+	if utils.Has0xPrefix(asset) {
+		return strings.EqualFold(t.Asset, asset)
+	}
+	return t.Asset == asset
+}
+
+// DestinationAddresses returns the recipients of transfers in order
+func DestinationAddresses(transfers []Transfer) []string {
+	addresses := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		addresses = append(addresses, transfer.To)
+	}
+	return addresses
+}
+
+// MessageContent returns the message as text, empty if it is not valid UTF-8
+func MessageContent(message []byte) string {
+	if !utf8.Valid(message) {
+		return ""
+	}
+	return string(message)
+}
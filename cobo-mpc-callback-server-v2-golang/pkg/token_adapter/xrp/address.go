@@ -0,0 +1,22 @@
+package xrp
+
+import (
+	"crypto/sha256"
+
+	"github.com/shengdoushi/base58"
+)
+
+// accountAddressVersion is the version byte of a classic address
+const accountAddressVersion = 0x00
+
+// AccountID is the 20 bytes id of an XRP Ledger account
+type AccountID [accountIDLength]byte
+
+// String returns the classic address of the account, the base58check of the version byte and the id
+// with the Ripple alphabet, e.g. rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh
+func (a AccountID) String() string {
+	payload := append([]byte{accountAddressVersion}, a[:]...)
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	return base58.Encode(append(payload, second[:4]...), base58.RippleAlphabet)
+}
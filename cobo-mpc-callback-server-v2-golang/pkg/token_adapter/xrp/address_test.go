@@ -0,0 +1,30 @@
+package xrp
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountID_String(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		want      string
+	}{
+		{name: "Account zero", accountID: "0000000000000000000000000000000000000000", want: "rrrrrrrrrrrrrrrrrrrrrhoLvTp"},
+		{name: "Account one", accountID: "0000000000000000000000000000000000000001", want: "rrrrrrrrrrrrrrrrrrrrBZbvji"},
+		{name: "Genesis account", accountID: "b5f762798a53d543a014caf8b297cff8f2f937e8", want: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.accountID)
+			assert.NoError(t, err)
+			var account AccountID
+			copy(account[:], b)
+			assert.Equal(t, tt.want, account.String())
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package xrp
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Type codes of the XRPL binary codec
+const (
+	typeUInt16    = 1
+	typeUInt32    = 2
+	typeHash256   = 5
+	typeAmount    = 6
+	typeBlob      = 7
+	typeAccountID = 8
+	typeSTObject  = 14
+	typeSTArray   = 15
+)
+
+// fieldID is the type code and field code of a field, serialized fields are sorted by type code then field code
+type fieldID struct {
+	typeCode  int
+	fieldCode int
+}
+
+func (f fieldID) less(other fieldID) bool {
+	if f.typeCode != other.typeCode {
+		return f.typeCode < other.typeCode
+	}
+	return f.fieldCode < other.fieldCode
+}
+
+// Fields of a Payment, a field not read by the payment decoder is rejected
+var (
+	fieldTransactionType    = fieldID{typeUInt16, 2}
+	fieldFlags              = fieldID{typeUInt32, 2}
+	fieldSourceTag          = fieldID{typeUInt32, 3}
+	fieldSequence           = fieldID{typeUInt32, 4}
+	fieldDestinationTag     = fieldID{typeUInt32, 14}
+	fieldLastLedgerSequence = fieldID{typeUInt32, 27}
+	fieldTicketSequence     = fieldID{typeUInt32, 41}
+	fieldNetworkID          = fieldID{typeUInt32, 1}
+	fieldInvoiceID          = fieldID{typeHash256, 17}
+	fieldAmount             = fieldID{typeAmount, 1}
+	fieldFee                = fieldID{typeAmount, 8}
+	fieldSigningPubKey      = fieldID{typeBlob, 3}
+	fieldTxnSignature       = fieldID{typeBlob, 4}
+	fieldAccount            = fieldID{typeAccountID, 1}
+	fieldDestination        = fieldID{typeAccountID, 3}
+	fieldMemos              = fieldID{typeSTArray, 9}
+	fieldMemo               = fieldID{typeSTObject, 10}
+	fieldMemoType           = fieldID{typeBlob, 12}
+	fieldMemoData           = fieldID{typeBlob, 13}
+	fieldMemoFormat         = fieldID{typeBlob, 14}
+	fieldObjectEndMarker    = fieldID{typeSTObject, 1}
+	fieldArrayEndMarker     = fieldID{typeSTArray, 1}
+)
+
+// fieldNames names the fields in errors
+var fieldNames = map[fieldID]string{
+	fieldTransactionType:    "TransactionType",
+	fieldFlags:              "Flags",
+	fieldSourceTag:          "SourceTag",
+	fieldSequence:           "Sequence",
+	fieldDestinationTag:     "DestinationTag",
+	fieldLastLedgerSequence: "LastLedgerSequence",
+	fieldTicketSequence:     "TicketSequence",
+	fieldNetworkID:          "NetworkID",
+	fieldInvoiceID:          "InvoiceID",
+	fieldAmount:             "Amount",
+	fieldFee:                "Fee",
+	fieldSigningPubKey:      "SigningPubKey",
+	fieldTxnSignature:       "TxnSignature",
+	fieldAccount:            "Account",
+	fieldDestination:        "Destination",
+	fieldMemos:              "Memos",
+	fieldMemo:               "Memo",
+	fieldMemoType:           "MemoType",
+	fieldMemoData:           "MemoData",
+	fieldMemoFormat:         "MemoFormat",
+}
+
+func (f fieldID) String() string {
+	if name, ok := fieldNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("field %d/%d", f.typeCode, f.fieldCode)
+}
+
+// decoder reads the XRPL binary codec
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.offset
+}
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > d.remaining() {
+		return nil, fmt.Errorf("read %d bytes at offset %d: out of range", n, d.offset)
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *decoder) readByte() (int, error) {
+	b, err := d.readBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return int(b[0]), nil
+}
+
+// readFieldID reads a field header, a code of 16 or more is in a following byte
+func (d *decoder) readFieldID() (fieldID, error) {
+	first, err := d.readByte()
+	if err != nil {
+		return fieldID{}, err
+	}
+
+	f := fieldID{typeCode: first >> 4, fieldCode: first & 0x0f}
+	if f.typeCode == 0 {
+		if f.typeCode, err = d.readByte(); err != nil {
+			return fieldID{}, err
+		}
+		if f.typeCode < 16 {
+			return fieldID{}, fmt.Errorf("non-canonical type code %d", f.typeCode)
+		}
+	}
+	if f.fieldCode == 0 {
+		if f.fieldCode, err = d.readByte(); err != nil {
+			return fieldID{}, err
+		}
+		if f.fieldCode < 16 {
+			return fieldID{}, fmt.Errorf("non-canonical field code %d", f.fieldCode)
+		}
+	}
+	return f, nil
+}
+
+func (d *decoder) readUint16() (uint16, error) {
+	b, err := d.readBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+func (d *decoder) readUint32() (uint32, error) {
+	b, err := d.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+// readVariableLength reads the length prefix of a blob or an account id
+func (d *decoder) readVariableLength() (int, error) {
+	b1, err := d.readByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case b1 <= 192:
+		return b1, nil
+	case b1 <= 240:
+		b2, err := d.readByte()
+		if err != nil {
+			return 0, err
+		}
+		return 193 + (b1-193)*256 + b2, nil
+	case b1 <= 254:
+		b, err := d.readBytes(2)
+		if err != nil {
+			return 0, err
+		}
+		return 12481 + (b1-241)*65536 + int(b[0])*256 + int(b[1]), nil
+	default:
+		return 0, fmt.Errorf("invalid variable length prefix %d", b1)
+	}
+}
+
+func (d *decoder) readVariableBytes() ([]byte, error) {
+	length, err := d.readVariableLength()
+	if err != nil {
+		return nil, err
+	}
+	return d.readBytes(length)
+}
+
+// readXRPAmount reads an amount of XRP in drops, an issued currency or MPT amount is rejected
+func (d *decoder) readXRPAmount() (uint64, error) {
+	b, err := d.readBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	value := binary.BigEndian.Uint64(b)
+	// the first bit flags an issued currency, the third bit an MPT amount
+	if value&(1<<63) != 0 || value&(1<<61) != 0 {
+		return 0, fmt.Errorf("amount is not in XRP")
+	}
+	// the second bit is the positive sign
+	if value&(1<<62) == 0 {
+		return 0, fmt.Errorf("amount is negative")
+	}
+	return value &^ (1 << 62), nil
+}
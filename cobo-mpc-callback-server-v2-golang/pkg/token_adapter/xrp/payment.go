@@ -0,0 +1,176 @@
+package xrp
+
+import (
+	"fmt"
+)
+
+const (
+	transactionTypePayment = 0
+
+	accountIDLength = 20
+
+	// tfPartialPayment lets a payment deliver less than its amount
+	tfPartialPayment = 0x00020000
+)
+
+// Payment is a decoded XRP Payment, the fields not needed for verification are checked but not kept
+type Payment struct {
+	account     AccountID
+	destination AccountID
+	// amount and fee in drops
+	amount uint64
+	fee    uint64
+	flags  uint32
+	// destinationTag is nil if the payment has no DestinationTag
+	destinationTag *uint32
+	networkID      *uint32
+	// memos are the MemoData of the memos
+	memos [][]byte
+}
+
+// ParsePayment decodes the signing fields of a Payment serialized by the XRPL binary codec.
+// The fields must be in canonical order, and a field other than those of a direct XRP payment is rejected,
+// including the TxnSignature of a signed transaction.
+func ParsePayment(rawTx []byte) (*Payment, error) {
+	d := &decoder{data: rawTx}
+	p := &Payment{}
+	seen := make(map[fieldID]bool)
+	var previous *fieldID
+
+	for d.remaining() > 0 {
+		f, err := d.readFieldID()
+		if err != nil {
+			return nil, fmt.Errorf("decode field header: %w", err)
+		}
+		if previous != nil && !previous.less(f) {
+			return nil, fmt.Errorf("%v is not in canonical order after %v", f, *previous)
+		}
+		previous = &f
+		seen[f] = true
+
+		if err := p.readField(d, f); err != nil {
+			return nil, fmt.Errorf("decode %v: %w", f, err)
+		}
+	}
+
+	for _, required := range []fieldID{fieldTransactionType, fieldAccount, fieldDestination, fieldAmount, fieldFee} {
+		if !seen[required] {
+			return nil, fmt.Errorf("payment has no %v", required)
+		}
+	}
+	if p.flags&tfPartialPayment != 0 {
+		return nil, fmt.Errorf("partial payment is not supported")
+	}
+
+	return p, nil
+}
+
+func (p *Payment) readField(d *decoder, f fieldID) (err error) {
+	switch f {
+	case fieldTransactionType:
+		var transactionType uint16
+		if transactionType, err = d.readUint16(); err != nil {
+			return err
+		}
+		if transactionType != transactionTypePayment {
+			return fmt.Errorf("transaction type %d is not a payment", transactionType)
+		}
+	case fieldFlags:
+		p.flags, err = d.readUint32()
+	case fieldDestinationTag:
+		var tag uint32
+		if tag, err = d.readUint32(); err == nil {
+			p.destinationTag = &tag
+		}
+	case fieldNetworkID:
+		var networkID uint32
+		if networkID, err = d.readUint32(); err == nil {
+			p.networkID = &networkID
+		}
+	case fieldSourceTag, fieldSequence, fieldLastLedgerSequence, fieldTicketSequence:
+		_, err = d.readUint32()
+	case fieldInvoiceID:
+		_, err = d.readBytes(32)
+	case fieldAmount:
+		p.amount, err = d.readXRPAmount()
+	case fieldFee:
+		p.fee, err = d.readXRPAmount()
+	case fieldSigningPubKey:
+		_, err = d.readVariableBytes()
+	case fieldAccount:
+		p.account, err = readAccountID(d)
+	case fieldDestination:
+		p.destination, err = readAccountID(d)
+	case fieldMemos:
+		p.memos, err = readMemos(d)
+	default:
+		return fmt.Errorf("unsupported payment field")
+	}
+	return err
+}
+
+func readAccountID(d *decoder) (AccountID, error) {
+	var account AccountID
+	b, err := d.readVariableBytes()
+	if err != nil {
+		return account, err
+	}
+	if len(b) != accountIDLength {
+		return account, fmt.Errorf("invalid account id length %d", len(b))
+	}
+	copy(account[:], b)
+	return account, nil
+}
+
+// readMemos reads the MemoData of the Memo objects of the Memos array up to the array end marker
+func readMemos(d *decoder) ([][]byte, error) {
+	var memos [][]byte
+	for {
+		f, err := d.readFieldID()
+		if err != nil {
+			return nil, err
+		}
+		if f == fieldArrayEndMarker {
+			return memos, nil
+		}
+		if f != fieldMemo {
+			return nil, fmt.Errorf("unexpected %v in memos", f)
+		}
+
+		data, err := readMemo(d)
+		if err != nil {
+			return nil, fmt.Errorf("memo %d: %w", len(memos), err)
+		}
+		memos = append(memos, data)
+	}
+}
+
+// readMemo reads the fields of a Memo object up to the object end marker and returns its MemoData
+func readMemo(d *decoder) ([]byte, error) {
+	var data []byte
+	var previous *fieldID
+	for {
+		f, err := d.readFieldID()
+		if err != nil {
+			return nil, err
+		}
+		if f == fieldObjectEndMarker {
+			return data, nil
+		}
+		if previous != nil && !previous.less(f) {
+			return nil, fmt.Errorf("%v is not in canonical order after %v", f, *previous)
+		}
+		previous = &f
+
+		if f != fieldMemoType && f != fieldMemoData && f != fieldMemoFormat {
+			return nil, fmt.Errorf("unsupported memo field %v", f)
+		}
+		value, err := d.readVariableBytes()
+		if err != nil {
+			return nil, fmt.Errorf("decode %v: %w", f, err)
+		}
+		if f == fieldMemoData {
+			data = value
+		}
+	}
+}
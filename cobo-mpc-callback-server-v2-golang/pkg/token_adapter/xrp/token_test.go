@@ -0,0 +1,87 @@
+package xrp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	coboWaaS2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	token := NewToken("XRP")
+	xrpToken, ok := token.(*Token)
+	assert.True(t, ok)
+	assert.Equal(t, "XRP", xrpToken.tokenID)
+}
+
+const (
+	// xrpPayment is the signing fields of a payment of 1 XRP with destination tag 123 from rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn
+	// to ra5nK24KXen9AHvsdFTKHSANinZseWnPcX, the accounts and the signing key of the xrpl.org Payment example
+	xrpPayment = "120000228000000024000000032E0000007B6140000000000F424068400000000000000A732103AB40A0490F9B7ED8DF29D246BF2D626982" +
+		"0A0EE7742ACDD457BEA7C7D0931EDB81144B4E9C06F24296074F7BC48F92A97916C6DC5EA983143E9D4A2B8AA0780F682D136F7A56D6724E" +
+		"F53754"
+
+	// xrpIssuedPayment is the signing fields of the xrpl.org Payment example of 1 USD, whose signing hash is
+	// 045cd0f9c0e3a5e714a2bc83ba946e49083eb1084f41246ea4efd2eaa3b46b9f
+	xrpIssuedPayment = "1200002280000000240000000361D4838D7EA4C6800000000000000000000000000055534400000000004B4E9C06F24296074F7BC48F92A9" +
+		"7916C6DC5EA968400000000000000A732103AB40A0490F9B7ED8DF29D246BF2D6269820A0EE7742ACDD457BEA7C7D0931EDB81144B4E9C06" +
+		"F24296074F7BC48F92A97916C6DC5EA983143E9D4A2B8AA0780F682D136F7A56D6724EF53754"
+)
+
+func TestToken_BuildTransaction(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawTx         string
+		wantHashes    []string
+		wantTransfers []token_adapter.Transfer
+		wantError     bool
+	}{
+		{
+			name:       "Payment",
+			rawTx:      xrpPayment,
+			wantHashes: []string{"0x263c727f857a14ad532dcd30490f291b3dc6791e78ce43be7e4824e79daff0d4"},
+			wantTransfers: []token_adapter.Transfer{{
+				Chain:  "XRP",
+				From:   "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn",
+				To:     "ra5nK24KXen9AHvsdFTKHSANinZseWnPcX",
+				Amount: big.NewInt(1000000),
+				Tag:    "123",
+			}},
+		},
+		{
+			name:      "Payment on a network id",
+			rawTx:     "12000021" + "0000535A" + xrpPayment[6:],
+			wantError: true,
+		},
+		{
+			name:      "Issued currency payment",
+			rawTx:     xrpIssuedPayment,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewToken("XRP").BuildTransaction(&token_adapter.TransactionInfo{
+				Transaction: &coboWaaS2.Transaction{
+					RawTxInfo: &coboWaaS2.TransactionRawTxInfo{UnsignedRawTx: &tt.rawTx},
+				},
+			})
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hashes, err := tx.GetHashes()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHashes, hashes)
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTransfers, transfers)
+		})
+	}
+}
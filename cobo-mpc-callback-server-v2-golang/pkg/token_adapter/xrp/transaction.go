@@ -0,0 +1,90 @@
+package xrp
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+)
+
+const defaultChainID = "XRP"
+
+// transactionSigningPrefix is the STX\0 prefix of the signing data of a single signed transaction
+var transactionSigningPrefix = []byte{'S', 'T', 'X', 0}
+
+// Transaction structure
+type Transaction struct {
+	token *Token
+	*PrepareTransactionData
+	tx *Payment
+}
+
+// PrepareTransactionData contains the raw transaction data
+type PrepareTransactionData struct {
+	rawTx   []byte
+	chainID string
+}
+
+// GetHashes implements Transaction interface for XRP, the hash is the first half of the sha512 of the signing data
+func (t *Transaction) GetHashes() ([]string, error) {
+	if len(t.rawTx) == 0 {
+		return nil, fmt.Errorf("transaction raw data is empty")
+	}
+
+	hash := sha512.Sum512(append(append([]byte{}, transactionSigningPrefix...), t.rawTx...))
+	return []string{"0x" + hex.EncodeToString(hash[:32])}, nil
+}
+
+// GetDestinationAddresses implements Transaction interface for XRP
+func (t *Transaction) GetDestinationAddresses() ([]string, error) {
+	transfers, err := t.GetTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	return token_adapter.DestinationAddresses(transfers), nil
+}
+
+// GetTransfers implements Transaction interface for XRP, the tag of the transfer is the DestinationTag
+func (t *Transaction) GetTransfers() ([]token_adapter.Transfer, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	chainID := t.chainID
+	if chainID == "" {
+		chainID = defaultChainID
+	}
+
+	var memos []string
+	for _, data := range t.tx.memos {
+		if content := token_adapter.MessageContent(data); content != "" {
+			memos = append(memos, content)
+		}
+	}
+
+	transfer := token_adapter.Transfer{
+		Chain:  chainID,
+		From:   t.tx.account.String(),
+		To:     t.tx.destination.String(),
+		Amount: new(big.Int).SetUint64(t.tx.amount),
+		Memo:   strings.Join(memos, "\n"),
+	}
+	if t.tx.destinationTag != nil {
+		transfer.Tag = strconv.FormatUint(uint64(*t.tx.destinationTag), 10)
+	}
+	return []token_adapter.Transfer{transfer}, nil
+}
+
+// GetFee implements FeeTransaction interface for XRP, the fee limit is the Fee in drops
+func (t *Transaction) GetFee() (*token_adapter.Fee, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("transaction data is nil")
+	}
+
+	return &token_adapter.Fee{FeeLimit: new(big.Int).SetUint64(t.tx.fee)}, nil
+}
@@ -0,0 +1,144 @@
+package xrp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/CoboGlobal/cobo-mpc-callback-server-v2/pkg/token_adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAccount(seed byte) AccountID {
+	var account AccountID
+	account[0] = seed
+	account[accountIDLength-1] = seed
+	return account
+}
+
+func uint32Field(header []byte, value uint32) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte{}, header...), value)
+}
+
+func amountField(header byte, drops uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte{header}, drops|1<<62)
+}
+
+func accountField(header byte, account AccountID) []byte {
+	return append([]byte{header, accountIDLength}, account[:]...)
+}
+
+func memosField(data ...string) []byte {
+	b := []byte{0xf9}
+	for _, d := range data {
+		b = append(b, 0xea, 0x7d, byte(len(d)))
+		b = append(append(b, d...), 0xe1)
+	}
+	return append(b, 0xf1)
+}
+
+// encodePayment returns the signing fields of a payment of 1 XRP with a fee of 12 drops, with the extra fields in order
+func encodePayment(from, to AccountID, tagAndMemos ...[]byte) []byte {
+	fields := [][]byte{
+		{0x12, 0x00, 0x00}, // TransactionType Payment
+		uint32Field([]byte{0x22}, 0),
+		uint32Field([]byte{0x24}, 7),
+	}
+	var memos []byte
+	for _, field := range tagAndMemos {
+		if field[0] == 0xf9 {
+			memos = field
+			continue
+		}
+		fields = append(fields, field)
+	}
+	fields = append(fields,
+		uint32Field([]byte{0x20, 0x1b}, 90000000),
+		amountField(0x61, 1000000),
+		amountField(0x68, 12),
+		append([]byte{0x73, 33}, bytes.Repeat([]byte{0x02}, 33)...),
+		accountField(0x81, from),
+		accountField(0x83, to),
+		memos,
+	)
+	return bytes.Join(fields, nil)
+}
+
+func TestTransaction_GetTransfers(t *testing.T) {
+	from := testAccount(1)
+	to := testAccount(2)
+	valid := encodePayment(from, to)
+	// Account and Destination are the last fields
+	accounts := len(valid) - 2*(2+accountIDLength)
+
+	tests := []struct {
+		name         string
+		rawTx        []byte
+		wantTransfer token_adapter.Transfer
+		wantError    bool
+	}{
+		{
+			name:         "Payment",
+			rawTx:        valid,
+			wantTransfer: token_adapter.Transfer{Chain: defaultChainID, From: from.String(), To: to.String(), Amount: big.NewInt(1000000)},
+		},
+		{
+			name:  "Payment with destination tag and memos",
+			rawTx: encodePayment(from, to, uint32Field([]byte{0x2e}, 123456), memosField("invoice 42", "thanks")),
+			wantTransfer: token_adapter.Transfer{
+				Chain: defaultChainID, From: from.String(), To: to.String(), Amount: big.NewInt(1000000), Memo: "invoice 42\nthanks", Tag: "123456",
+			},
+		},
+		{
+			name:      "Not a payment",
+			rawTx:     bytes.Replace(valid, []byte{0x12, 0x00, 0x00}, []byte{0x12, 0x00, 0x14}, 1),
+			wantError: true,
+		},
+		{
+			name:      "Partial payment",
+			rawTx:     bytes.Replace(valid, uint32Field([]byte{0x22}, 0), uint32Field([]byte{0x22}, tfPartialPayment), 1),
+			wantError: true,
+		},
+		{
+			name:      "Issued currency amount",
+			rawTx:     bytes.Replace(valid, amountField(0x61, 1000000), append([]byte{0x61, 0xd4}, make([]byte, 47)...), 1),
+			wantError: true,
+		},
+		{
+			name:      "Signed transaction",
+			rawTx:     bytes.Join([][]byte{valid[:accounts], {0x74, 2, 0x30, 0x00}, valid[accounts:]}, nil),
+			wantError: true,
+		},
+		{
+			name:      "Fields out of order",
+			rawTx:     append(uint32Field([]byte{0x22}, 0), valid...),
+			wantError: true,
+		},
+		{
+			name:      "Missing destination",
+			rawTx:     valid[:len(valid)-2-accountIDLength],
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment, err := ParsePayment(tt.rawTx)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			tx := &Transaction{tx: payment, PrepareTransactionData: &PrepareTransactionData{rawTx: tt.rawTx}, token: &Token{tokenID: "XRP"}}
+
+			transfers, err := tx.GetTransfers()
+			assert.NoError(t, err)
+			assert.Equal(t, []token_adapter.Transfer{tt.wantTransfer}, transfers)
+
+			fee, err := tx.GetFee()
+			assert.NoError(t, err)
+			assert.Equal(t, &token_adapter.Fee{FeeLimit: big.NewInt(12)}, fee)
+		})
+	}
+}